@@ -9,17 +9,23 @@ import (
 	"syscall"
 
 	"github.com/D1sordxr/image-processor/internal/application/image/usecase"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
 	"github.com/D1sordxr/image-processor/internal/domain/core/shared/vo"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/image/fetcher"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/image/processor"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/image/webhook"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/queue/kafka/image/consumer"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/queue/kafka/image/producer"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/queue/outbox"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/minio"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/minio/repositories/image/s3repo"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/executor"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/listener"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/repo"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/txmanager"
 	defaultWorker "github.com/D1sordxr/image-processor/internal/infrastructure/worker"
 	"github.com/D1sordxr/image-processor/internal/transport/kafka/handler/image"
+	imagePoller "github.com/D1sordxr/image-processor/internal/transport/poller/handler/image"
 
 	loadApp "github.com/D1sordxr/image-processor/internal/infrastructure/app"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/config"
@@ -44,27 +50,79 @@ func main() {
 
 	storageExecutor := executor.New(storageConn.Storage)
 	txManager := txmanager.New(storageExecutor)
+	statusBroker := listener.NewStatusBroker(log, cfg.Storage)
 
 	imageRepo := repo.New(storageExecutor)
+	cardTemplateRepo := repo.NewCardTemplateRepository(storageExecutor)
+	uploadSessionRepo := repo.NewUploadSessionRepository(storageExecutor)
+	batchRepo := repo.NewBatchRepository(storageExecutor)
+	webhookRepo := repo.NewWebhookRepository(storageExecutor)
+	outboxRepo := repo.NewOutboxRepository(storageExecutor)
+	deadLetterRepo := repo.NewDeadLetterRepository(storageExecutor)
 	imageS3Repo := s3repo.New(s3Conn, cfg.S3Storage.BucketName)
 	imageProducer := producer.New(log, brokerConn.Producer, cfg.Broker.ImageTopic)
-	imageConsumer := consumer.New(log, brokerConn.Consumer, cfg.Broker.ImageTopic)
+	retryTiers := make([]consumer.RetryTier, 0, len(options.RetryDelays))
+	for _, delay := range options.RetryDelays {
+		retryTiers = append(retryTiers, consumer.RetryTier{Topic: cfg.Broker.RetryTopic(delay), Delay: delay})
+	}
+	imageConsumer := consumer.New(
+		log,
+		brokerConn.Consumer,
+		brokerConn.RetryProducer,
+		brokerConn.DeadLetterProducer,
+		cfg.Broker.ImageTopic,
+		retryTiers,
+		cfg.Broker.Workers,
+	)
+	deadLetterConsumer := consumer.NewDeadLetterConsumer(log, brokerConn.DeadLetterConsumer, cfg.Broker.DeadLetterTopic)
 	imageProcessor := processor.New()
+	imageFetcher := fetcher.New(cfg.Fetcher)
+	webhookSender := webhook.New(cfg.Webhook)
 	imageUC := usecase.New(
 		log,
 		txManager,
 		imageRepo,
+		cardTemplateRepo,
+		uploadSessionRepo,
+		batchRepo,
+		webhookRepo,
+		deadLetterRepo,
+		webhookSender,
+		statusBroker,
 		imageS3Repo,
-		imageProducer,
+		outboxRepo,
 		imageProcessor,
 		vo.NewBaseURL(cfg.Server.Host, cfg.Server.Port),
 	)
+	outboxRelay := outbox.NewRelay(log, outboxRepo, imageProducer)
 	imageProcessorWorkerHandler := image.NewProcessorHandler(log, imageConsumer, imageUC)
-	imageHttpHandler := handler.New(log, imageUC, vo.NewBaseURL(cfg.Server.Host, cfg.Server.Port))
+	retryWorkerHandlers := make([]defaultWorker.Handler, 0, len(retryTiers))
+	for i, tier := range retryTiers {
+		retryConsumer := consumer.New(
+			log,
+			brokerConn.RetryConsumers[i],
+			brokerConn.RetryProducer,
+			brokerConn.DeadLetterProducer,
+			tier.Topic,
+			retryTiers,
+			cfg.Broker.Workers,
+		)
+		retryWorkerHandlers = append(retryWorkerHandlers, image.NewProcessorHandler(log, retryConsumer, imageUC))
+	}
+	deadLetterWorkerHandler := image.NewDeadLetterHandler(log, deadLetterConsumer, imageUC)
+	webhookDeliveryWorkerHandler := imagePoller.NewWebhookDeliveryHandler(log, imageUC)
+	reconciliationWorkerHandler := imagePoller.NewReconciliationHandler(log, imageUC, cfg.Reconciler)
+	imageHttpHandler := handler.New(log, imageUC, imageFetcher, vo.NewBaseURL(cfg.Server.Host, cfg.Server.Port), cfg.Server.Origins())
 
 	worker := defaultWorker.New(
 		log,
-		imageProcessorWorkerHandler,
+		cfg.Worker.DrainTimeout,
+		append([]defaultWorker.Handler{
+			imageProcessorWorkerHandler,
+			deadLetterWorkerHandler,
+			webhookDeliveryWorkerHandler,
+			reconciliationWorkerHandler,
+		}, retryWorkerHandlers...)...,
 	)
 	httpServer := http.NewServer(
 		log,
@@ -74,9 +132,12 @@ func main() {
 
 	app := loadApp.NewApp(
 		log,
+		cfg.Worker.DrainTimeout,
 		brokerConn,
 		storageConn,
 		s3Conn,
+		statusBroker,
+		outboxRelay,
 		httpServer,
 		worker,
 	)
@@ -15,6 +15,7 @@ require (
 	github.com/segmentio/kafka-go v0.4.49
 	github.com/wb-go/wbf v0.0.7
 	golang.org/x/image v0.32.0
+	golang.org/x/net v0.42.0
 	golang.org/x/sync v0.17.0
 )
 
@@ -54,7 +55,6 @@ require (
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/arch v0.18.0 // indirect
 	golang.org/x/crypto v0.40.0 // indirect
-	golang.org/x/net v0.42.0 // indirect
 	golang.org/x/sys v0.34.0 // indirect
 	golang.org/x/text v0.30.0 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
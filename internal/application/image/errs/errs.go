@@ -0,0 +1,22 @@
+package errs
+
+import "errors"
+
+var (
+	ErrDuplicateImage = errors.New("duplicate image")
+	ErrUnknownSlot    = errors.New("unknown template slot")
+
+	ErrOffsetMismatch     = errors.New("upload offset mismatch")
+	ErrUploadExpired      = errors.New("upload expired")
+	ErrChunkExceedsLength = errors.New("chunk exceeds upload length")
+	ErrWrongUploadMethod  = errors.New("operation is not supported by the upload method")
+	ErrObjectNotUploaded  = errors.New("object is not uploaded")
+	ErrUploadSizeMismatch = errors.New("uploaded size does not match declared size")
+	ErrInvalidImage       = errors.New("invalid image")
+
+	ErrAlreadyReplayed = errors.New("dead letter is already replayed")
+	ErrUndecodableTask = errors.New("task cannot be decoded")
+	ErrNotReplayable   = errors.New("image is not failed")
+
+	ErrNotCancellable = errors.New("image is already processed")
+)
@@ -1,14 +1,104 @@
 package input
 
 import (
+	"io"
+	"time"
+
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/port"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
 )
 
 type UploadImageInput struct {
-	ImageData []byte
-	Filename  string
-	Options   model.ProcessingOptions
-	// CallbackURL string
+	Image           io.Reader
+	Size            int64 // -1 when unknown
+	Filename        string
+	Options         model.ProcessingOptions
+	DuplicatePolicy vo.DuplicatePolicy
+	CallbackURL     string // notified when the image is completed or failed
+}
+
+type UploadBatchInput struct {
+	Items           []UploadBatchItem
+	DuplicatePolicy vo.DuplicatePolicy
+	CallbackURL     string // notified for each image of the batch
+}
+
+// UploadBatchItem is a file of a batch. Items are opened one at a time, so
+// the batch never holds more than one open file.
+type UploadBatchItem struct {
+	Filename string
+	Size     int64
+	Options  model.ProcessingOptions
+	Open     func() (io.ReadCloser, error)
+	Rejected error // validation error; the item is reported as failed without being uploaded
+}
+
+type GetBatchInput struct {
+	BatchID string
+}
+
+type CreateUploadSessionInput struct {
+	Filename        string
+	Size            int64
+	Options         model.ProcessingOptions
+	DuplicatePolicy vo.DuplicatePolicy
+}
+
+type GetUploadSessionInput struct {
+	UploadID string
+}
+
+type AppendUploadChunkInput struct {
+	UploadID string
+	Offset   int64
+	Chunk    io.Reader
+	Size     int64
+}
+
+type CreatePresignedUploadInput struct {
+	Filename        string
+	Size            int64
+	Options         model.ProcessingOptions
+	DuplicatePolicy vo.DuplicatePolicy
+}
+
+type CompletePresignedUploadInput struct {
+	UploadID string
+}
+
+type CancelUploadSessionInput struct {
+	UploadID string
+}
+
+type CreateContactSheetInput struct {
+	Spec model.ContactSheetSpec
+}
+
+type CreateCardTemplateInput struct {
+	Name       string
+	Width      int
+	Height     int
+	Background string
+	ImageSlots []model.CardImageSlot
+	TextSlots  []model.CardTextSlot
+}
+
+type GetCardTemplateInput struct {
+	TemplateID string
+}
+
+type ListCardTemplatesInput struct {
+	Limit  int32
+	Offset int32
+}
+
+type DeleteCardTemplateInput struct {
+	TemplateID string
+}
+
+type RenderCardInput struct {
+	Spec model.CardRenderSpec
 }
 
 type GetImageInput struct {
@@ -19,10 +109,77 @@ type GetImageStatusInput struct {
 	ImageID string
 }
 
+type ListImagesInput struct {
+	Status string
+	Format string
+	Color  string
+	Limit  int32
+	Offset int32
+}
+
+type FindSimilarImagesInput struct {
+	ImageID     string
+	MaxDistance int
+	Limit       int32
+}
+
+type CompareImagesInput struct {
+	ImageID      string
+	Variant      vo.Variant
+	OtherImageID string
+	OtherVariant vo.Variant
+	Diff         bool
+}
+
+type GetImageHistogramInput struct {
+	ImageID string
+	Variant vo.Variant
+}
+
 type DeleteImageInput struct {
 	ImageID string
 }
 
+type CancelImageInput struct {
+	ImageID string
+}
+
 type ProcessImageSyncInput struct {
 	ImageID string
 }
+
+type ListWebhookDeliveriesInput struct {
+	ImageID string
+}
+
+type RedeliverWebhookInput struct {
+	DeliveryID string
+}
+
+type ListDeadLettersInput struct {
+	Limit  int32
+	Offset int32
+}
+
+type ReplayDeadLetterInput struct {
+	DeadLetterID string
+}
+
+type SubscribeStatusInput struct {
+	ImageIDs []string
+}
+
+type WatchStatusInput struct {
+	Subscription port.StatusSubscription
+	ImageIDs     []string
+}
+
+type ReconcileStuckImagesInput struct {
+	Timeout     time.Duration
+	MaxRequeues int
+}
+
+type ReconcileOrphanOriginalsInput struct {
+	MinAge time.Duration
+	Delete bool // otherwise the orphans are only reported
+}
@@ -1,12 +1,106 @@
 package output
 
-import "github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+import (
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/port"
+)
 
 type UploadImageOutput struct {
 	ImageID   string `json:"image_id"`
 	Status    string `json:"status"`
 	Message   string `json:"message"`
 	ResultURL string `json:"result_url"`
+	Duplicate bool   `json:"duplicate,omitempty"`
+}
+
+type UploadBatchOutput struct {
+	BatchID  string                  `json:"batch_id"`
+	Total    int                     `json:"total"`
+	Accepted int                     `json:"accepted"`
+	Failed   int                     `json:"failed"`
+	Items    []UploadBatchItemOutput `json:"items"`
+}
+
+type UploadBatchItemOutput struct {
+	Index     int    `json:"index"`
+	Filename  string `json:"filename"`
+	ImageID   string `json:"image_id,omitempty"`
+	Status    string `json:"status"`
+	ResultURL string `json:"result_url,omitempty"`
+	Duplicate bool   `json:"duplicate,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type BatchOutput struct {
+	BatchID     string               `json:"batch_id"`
+	Status      string               `json:"status"`
+	Total       int                  `json:"total"`
+	Counts      map[string]int       `json:"counts"`
+	Progress    int                  `json:"progress"` // percentage of finished items
+	Failures    []BatchFailureOutput `json:"failures"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty"`
+}
+
+type BatchFailureOutput struct {
+	Index    int    `json:"index"`
+	Filename string `json:"filename"`
+	ImageID  string `json:"image_id,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+type UploadSessionOutput struct {
+	UploadID  string    `json:"upload_id"`
+	Method    string    `json:"method"`
+	Offset    int64     `json:"offset"`
+	Length    int64     `json:"length"`
+	ExpiresAt time.Time `json:"expires_at"`
+	ImageID   string    `json:"image_id,omitempty"` // set once the upload is completed
+}
+
+type AppendUploadChunkOutput struct {
+	Offset int64              `json:"offset"`
+	Upload *UploadImageOutput `json:"upload,omitempty"` // set when the chunk completed the upload
+}
+
+type PresignedUploadOutput struct {
+	UploadID  string    `json:"upload_id"`
+	ImageID   string    `json:"image_id"`
+	UploadURL string    `json:"upload_url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type CancelUploadSessionOutput struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+type CreateContactSheetOutput struct {
+	ImageID   string `json:"image_id"`
+	Status    string `json:"status"`
+	ResultURL string `json:"result_url"`
+}
+
+type CardTemplateOutput struct {
+	Template *model.CardTemplate `json:"template"`
+}
+
+type ListCardTemplatesOutput struct {
+	Templates []model.CardTemplate `json:"templates"`
+}
+
+type DeleteCardTemplateOutput struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+type RenderCardOutput struct {
+	ImageID   string `json:"image_id"`
+	Status    string `json:"status"`
+	ResultURL string `json:"result_url"`
 }
 
 type GetImageOutput struct {
@@ -15,7 +109,30 @@ type GetImageOutput struct {
 }
 
 type GetImageStatusOutput struct {
-	Status string `json:"status"`
+	Status   string `json:"status"`
+	BlurHash string `json:"blur_hash,omitempty"`
+	LQIP     string `json:"lqip,omitempty"`
+}
+
+type ListImagesOutput struct {
+	Images []model.ImageMetadata `json:"images"`
+}
+
+type FindSimilarImagesOutput struct {
+	Images []model.SimilarImage `json:"images"`
+}
+
+type CompareImagesOutput struct {
+	PSNR         float64 `json:"psnr"`
+	SSIM         float64 `json:"ssim"`
+	HashDistance int     `json:"hash_distance"`
+	DiffImage    []byte  `json:"diff_image,omitempty"`
+}
+
+type GetImageHistogramOutput struct {
+	ImageID    string                 `json:"image_id"`
+	Variant    string                 `json:"variant"`
+	Statistics *model.ImageStatistics `json:"statistics"`
 }
 
 type DeleteImageOutput struct {
@@ -23,7 +140,51 @@ type DeleteImageOutput struct {
 	Message string `json:"message"`
 }
 
+type CancelImageOutput struct {
+	ImageID string `json:"image_id"`
+	Status  string `json:"status"`
+}
+
 type ProcessImageSyncOutput struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 }
+
+type ListWebhookDeliveriesOutput struct {
+	Deliveries []model.WebhookDelivery `json:"deliveries"`
+}
+
+type RedeliverWebhookOutput struct {
+	Delivery *model.WebhookDelivery `json:"delivery"`
+}
+
+type ListDeadLettersOutput struct {
+	DeadLetters []model.DeadLetter `json:"dead_letters"`
+}
+
+type ReplayDeadLetterOutput struct {
+	DeadLetter *model.DeadLetter `json:"dead_letter"`
+}
+
+type SubscribeStatusOutput struct {
+	Subscription port.StatusSubscription
+	Current      []model.StatusEvent // current status of the watched images
+}
+
+type WatchStatusOutput struct {
+	Current []model.StatusEvent
+}
+
+type ReconcileStuckImagesOutput struct {
+	Claimed   int `json:"claimed"`
+	Completed int `json:"completed"`
+	Requeued  int `json:"requeued"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	Errors    int `json:"errors"`
+}
+
+type ReconcileOrphanOriginalsOutput struct {
+	Orphans []string `json:"orphans"` // image IDs of the originals with no image
+	Deleted int      `json:"deleted"`
+}
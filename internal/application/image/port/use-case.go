@@ -10,9 +10,39 @@ import (
 
 type UseCase interface {
 	Upload(ctx context.Context, in input.UploadImageInput) (*output.UploadImageOutput, error)
+	UploadBatch(ctx context.Context, in input.UploadBatchInput) (*output.UploadBatchOutput, error)
+	GetBatch(ctx context.Context, in input.GetBatchInput) (*output.BatchOutput, error)
+	CreateUploadSession(ctx context.Context, in input.CreateUploadSessionInput) (*output.UploadSessionOutput, error)
+	GetUploadSession(ctx context.Context, in input.GetUploadSessionInput) (*output.UploadSessionOutput, error)
+	AppendUploadChunk(ctx context.Context, in input.AppendUploadChunkInput) (*output.AppendUploadChunkOutput, error)
+	CreatePresignedUpload(ctx context.Context, in input.CreatePresignedUploadInput) (*output.PresignedUploadOutput, error)
+	CompletePresignedUpload(ctx context.Context, in input.CompletePresignedUploadInput) (*output.UploadImageOutput, error)
+	CancelUploadSession(ctx context.Context, in input.CancelUploadSessionInput) (*output.CancelUploadSessionOutput, error)
+	CreateContactSheet(ctx context.Context, in input.CreateContactSheetInput) (*output.CreateContactSheetOutput, error)
+	CreateCardTemplate(ctx context.Context, in input.CreateCardTemplateInput) (*output.CardTemplateOutput, error)
+	GetCardTemplate(ctx context.Context, in input.GetCardTemplateInput) (*output.CardTemplateOutput, error)
+	ListCardTemplates(ctx context.Context, in input.ListCardTemplatesInput) (*output.ListCardTemplatesOutput, error)
+	DeleteCardTemplate(ctx context.Context, in input.DeleteCardTemplateInput) (*output.DeleteCardTemplateOutput, error)
+	RenderCard(ctx context.Context, in input.RenderCardInput) (*output.RenderCardOutput, error)
 	Process(ctx context.Context, image *model.ProcessingImage) error
+	DeliverWebhooks(ctx context.Context) (int, error)
+	ListWebhookDeliveries(ctx context.Context, in input.ListWebhookDeliveriesInput) (*output.ListWebhookDeliveriesOutput, error)
+	RedeliverWebhook(ctx context.Context, in input.RedeliverWebhookInput) (*output.RedeliverWebhookOutput, error)
+	SaveDeadLetter(ctx context.Context, letter *model.DeadLetter) error
+	ListDeadLetters(ctx context.Context, in input.ListDeadLettersInput) (*output.ListDeadLettersOutput, error)
+	ReplayDeadLetter(ctx context.Context, in input.ReplayDeadLetterInput) (*output.ReplayDeadLetterOutput, error)
+	ReconcileStuckImages(ctx context.Context, in input.ReconcileStuckImagesInput) (*output.ReconcileStuckImagesOutput, error)
+	ReconcileOrphanOriginals(ctx context.Context, in input.ReconcileOrphanOriginalsInput) (*output.ReconcileOrphanOriginalsOutput, error)
 	Get(ctx context.Context, in input.GetImageInput) (*output.GetImageOutput, error)
 	GetStatus(ctx context.Context, in input.GetImageStatusInput) (*output.GetImageStatusOutput, error)
+	SubscribeStatus(ctx context.Context, in input.SubscribeStatusInput) (*output.SubscribeStatusOutput, error)
+	WatchStatus(ctx context.Context, in input.WatchStatusInput) (*output.WatchStatusOutput, error)
+	UnwatchStatus(ctx context.Context, in input.WatchStatusInput) error
+	List(ctx context.Context, in input.ListImagesInput) (*output.ListImagesOutput, error)
+	FindSimilar(ctx context.Context, in input.FindSimilarImagesInput) (*output.FindSimilarImagesOutput, error)
+	Compare(ctx context.Context, in input.CompareImagesInput) (*output.CompareImagesOutput, error)
+	GetHistogram(ctx context.Context, in input.GetImageHistogramInput) (*output.GetImageHistogramOutput, error)
 	Delete(ctx context.Context, in input.DeleteImageInput) (*output.DeleteImageOutput, error)
+	Cancel(ctx context.Context, in input.CancelImageInput) (*output.CancelImageOutput, error)
 	ProcessSync(ctx context.Context, in input.ProcessImageSyncInput) (*output.ProcessImageSyncOutput, error)
 }
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/output"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// UploadBatch uploads the items one by one. A failed item does not stop the
+// batch, its error is reported in the item result and stored with the batch.
+func (uc *UseCase) UploadBatch(ctx context.Context, in input.UploadBatchInput) (*output.UploadBatchOutput, error) {
+	const op = "image.UseCase.UploadBatch"
+	batchID := uuid.New()
+	logFields := logger.WithFields("operation", op, "batch_id", batchID.String())
+
+	uc.log.Info("Uploading batch...", logFields("items", len(in.Items))...)
+
+	if err := uc.batches.Save(ctx, options.BatchCreateParams{
+		ID:        batchID,
+		Total:     len(in.Items),
+		CreatedAt: time.Now(),
+	}); err != nil {
+		uc.log.Error("Failed to save batch", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := &output.UploadBatchOutput{
+		BatchID: batchID.String(),
+		Total:   len(in.Items),
+		Items:   make([]output.UploadBatchItemOutput, 0, len(in.Items)),
+	}
+	// the items left after the request is cancelled are saved as failed,
+	// so that the batch can still complete
+	saveCtx := context.WithoutCancel(ctx)
+	for i, item := range in.Items {
+		itemResult := output.UploadBatchItemOutput{
+			Index:    i,
+			Filename: item.Filename,
+		}
+
+		itemParams := options.BatchItemCreateParams{
+			BatchID:  batchID,
+			Index:    i,
+			Filename: item.Filename,
+		}
+
+		upload, err := uc.uploadBatchItem(ctx, item, in.DuplicatePolicy, in.CallbackURL)
+		if err != nil {
+			uc.log.Error("Failed to upload batch item", logFields("error", err, "index", i, "filename", item.Filename)...)
+			itemResult.Status = vo.StatusFailed.String()
+			itemResult.Error = err.Error()
+			itemParams.Error = itemResult.Error
+			result.Failed++
+		} else {
+			itemResult.ImageID = upload.ImageID
+			itemResult.Status = upload.Status
+			itemResult.ResultURL = upload.ResultURL
+			itemResult.Duplicate = upload.Duplicate
+			if imageID, err := uuid.Parse(upload.ImageID); err == nil {
+				itemParams.ImageID = &imageID
+			}
+			result.Accepted++
+		}
+		result.Items = append(result.Items, itemResult)
+
+		if err = uc.batches.SaveItem(saveCtx, itemParams); err != nil {
+			uc.log.Error("Failed to save batch item", logFields("error", err, "index", i)...)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// the images may have been processed before the last item was saved
+	if err := uc.batches.Refresh(saveCtx, batchID, time.Now()); err != nil {
+		uc.log.Error("Failed to refresh batch", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully uploaded batch", logFields(
+		"accepted", result.Accepted,
+		"failed", result.Failed,
+	)...)
+
+	return result, nil
+}
+
+func (uc *UseCase) GetBatch(ctx context.Context, in input.GetBatchInput) (*output.BatchOutput, error) {
+	const op = "image.UseCase.GetBatch"
+	logFields := logger.WithFields("operation", op, "batch_id", in.BatchID)
+
+	uc.log.Info("Attempting to get batch", logFields()...)
+
+	batchID, err := uuid.Parse(in.BatchID)
+	if err != nil {
+		uc.log.Error("Failed to parse batch UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	batch, err := uc.batches.Get(ctx, batchID)
+	if err != nil {
+		uc.log.Error("Batch not found", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: batch not found: %w", op, err)
+	}
+
+	// an image deleted while being processed never reports its result
+	if batch.CompletedAt == nil && batch.IsSettled() {
+		if err = uc.batches.Refresh(ctx, batchID, time.Now()); err != nil {
+			uc.log.Error("Failed to refresh batch", logFields("error", err)...)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if batch, err = uc.batches.Get(ctx, batchID); err != nil {
+			uc.log.Error("Failed to get batch", logFields("error", err)...)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	uc.log.Info("Successfully retrieved batch", logFields()...)
+
+	counts := make(map[string]int)
+	for status, count := range batch.Counts() {
+		counts[status.String()] = count
+	}
+	failures := make([]output.BatchFailureOutput, 0)
+	for _, item := range batch.Failures() {
+		failure := output.BatchFailureOutput{
+			Index:    item.Index,
+			Filename: item.Filename,
+			Reason:   item.Error,
+		}
+		if item.ImageID != nil {
+			failure.ImageID = item.ImageID.String()
+		}
+		failures = append(failures, failure)
+	}
+
+	return &output.BatchOutput{
+		BatchID:     batch.ID.String(),
+		Status:      batch.Status().String(),
+		Total:       batch.Total,
+		Counts:      counts,
+		Progress:    batch.Progress(),
+		Failures:    failures,
+		CreatedAt:   batch.CreatedAt,
+		UpdatedAt:   batch.UpdatedAt,
+		CompletedAt: batch.CompletedAt,
+	}, nil
+}
+
+func (uc *UseCase) uploadBatchItem(
+	ctx context.Context,
+	item input.UploadBatchItem,
+	duplicatePolicy vo.DuplicatePolicy,
+	callbackURL string,
+) (*output.UploadImageOutput, error) {
+	if item.Rejected != nil {
+		return nil, item.Rejected
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("upload interrupted: %w", err)
+	}
+
+	file, err := item.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return uc.Upload(ctx, input.UploadImageInput{
+		Image:           file,
+		Size:            item.Size,
+		Filename:        item.Filename,
+		Options:         item.Options,
+		DuplicatePolicy: duplicatePolicy,
+		CallbackURL:     callbackURL,
+	})
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/output"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// Cancel stops the processing of an image. The task stays in the queue, the
+// worker skips it once it sees the image cancelled.
+func (uc *UseCase) Cancel(ctx context.Context, in input.CancelImageInput) (*output.CancelImageOutput, error) {
+	const op = "image.UseCase.Cancel"
+	logFields := logger.WithFields("operation", op, "image_id", in.ImageID)
+
+	uc.log.Info("Attempting to cancel image processing", logFields()...)
+
+	imageID, err := uuid.Parse(in.ImageID)
+	if err != nil {
+		uc.log.Error("Failed to parse image UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	image, err := uc.repo.Get(ctx, imageID)
+	if err != nil {
+		uc.log.Error("Image not found", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: image not found: %w", op, err)
+	}
+	if !image.Status.IsCancellable() {
+		return nil, fmt.Errorf("%s: %w: %s", op, errs.ErrNotCancellable, image.Status)
+	}
+
+	// the worker may finish the image meanwhile
+	cancelled, err := uc.repo.Cancel(ctx, imageID)
+	if err != nil {
+		uc.log.Error("Failed to cancel image", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("%s: %w", op, errs.ErrNotCancellable)
+	}
+
+	if err = uc.batches.RefreshByImage(ctx, imageID, time.Now()); err != nil {
+		uc.log.Error("Failed to refresh image batches", logFields("error", err)...)
+	}
+
+	uc.log.Info("Successfully cancelled image processing", logFields()...)
+
+	return &output.CancelImageOutput{
+		ImageID: imageID.String(),
+		Status:  vo.StatusCancelled.String(),
+	}, nil
+}
+
+// isCancelled reports whether the processing of the image is cancelled. In
+// a transaction the image is locked, so that it cannot be cancelled until the
+// result is saved.
+func (uc *UseCase) isCancelled(ctx context.Context, imageID uuid.UUID) (bool, error) {
+	image, err := uc.repo.GetForUpdate(ctx, imageID)
+	if err != nil {
+		return false, fmt.Errorf("get image: %w", err)
+	}
+	return image.Status == vo.StatusCancelled, nil
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/output"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+	"github.com/google/uuid"
+)
+
+func (uc *UseCase) CreateCardTemplate(ctx context.Context, in input.CreateCardTemplateInput) (*output.CardTemplateOutput, error) {
+	const op = "image.UseCase.CreateCardTemplate"
+	logFields := logger.WithFields("operation", op, "name", in.Name)
+
+	uc.log.Info("Creating card template...", logFields()...)
+
+	template, err := uc.templates.Save(ctx, options.CardTemplateCreateParams{
+		ID:         uuid.New(),
+		Name:       in.Name,
+		Width:      in.Width,
+		Height:     in.Height,
+		Background: in.Background,
+		ImageSlots: in.ImageSlots,
+		TextSlots:  in.TextSlots,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		uc.log.Error("Failed to save card template", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully created card template", logFields("template_id", template.ID.String())...)
+
+	return &output.CardTemplateOutput{Template: template}, nil
+}
+
+func (uc *UseCase) GetCardTemplate(ctx context.Context, in input.GetCardTemplateInput) (*output.CardTemplateOutput, error) {
+	const op = "image.UseCase.GetCardTemplate"
+	logFields := logger.WithFields("operation", op, "template_id", in.TemplateID)
+
+	uc.log.Info("Attempting to get card template", logFields()...)
+
+	templateID, err := uuid.Parse(in.TemplateID)
+	if err != nil {
+		uc.log.Error("Failed to parse template UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	template, err := uc.templates.Get(ctx, templateID)
+	if err != nil {
+		uc.log.Error("Card template not found", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: template not found: %w", op, err)
+	}
+
+	return &output.CardTemplateOutput{Template: template}, nil
+}
+
+func (uc *UseCase) ListCardTemplates(ctx context.Context, in input.ListCardTemplatesInput) (*output.ListCardTemplatesOutput, error) {
+	const op = "image.UseCase.ListCardTemplates"
+	logFields := logger.WithFields("operation", op)
+
+	uc.log.Info("Attempting to list card templates", logFields()...)
+
+	templates, err := uc.templates.List(ctx, options.PaginationParams{
+		Limit:  in.Limit,
+		Offset: in.Offset,
+	})
+	if err != nil {
+		uc.log.Error("Failed to list card templates", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully listed card templates", logFields("count", len(templates))...)
+
+	return &output.ListCardTemplatesOutput{Templates: templates}, nil
+}
+
+func (uc *UseCase) DeleteCardTemplate(ctx context.Context, in input.DeleteCardTemplateInput) (*output.DeleteCardTemplateOutput, error) {
+	const op = "image.UseCase.DeleteCardTemplate"
+	logFields := logger.WithFields("operation", op, "template_id", in.TemplateID)
+
+	uc.log.Info("Attempting to delete card template", logFields()...)
+
+	templateID, err := uuid.Parse(in.TemplateID)
+	if err != nil {
+		uc.log.Error("Failed to parse template UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = uc.templates.Delete(ctx, templateID); err != nil {
+		uc.log.Error("Failed to delete card template", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: template not found: %w", op, err)
+	}
+
+	uc.log.Info("Successfully deleted card template", logFields()...)
+
+	return &output.DeleteCardTemplateOutput{
+		Success: true,
+		Message: "Successfully deleted card template",
+	}, nil
+}
+
+func (uc *UseCase) RenderCard(ctx context.Context, in input.RenderCardInput) (*output.RenderCardOutput, error) {
+	const op = "image.UseCase.RenderCard"
+	logFields := logger.WithFields("operation", op, "template_id", in.Spec.TemplateID)
+
+	uc.log.Info("Creating card...", logFields()...)
+
+	templateID, err := uuid.Parse(in.Spec.TemplateID)
+	if err != nil {
+		uc.log.Error("Failed to parse template UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	template, err := uc.templates.Get(ctx, templateID)
+	if err != nil {
+		uc.log.Error("Card template not found", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: template not found: %w", op, err)
+	}
+
+	for slot := range in.Spec.Texts {
+		if !slices.ContainsFunc(template.TextSlots, func(s model.CardTextSlot) bool { return s.Name == slot }) {
+			uc.log.Error("Unknown text slot", logFields("slot", slot)...)
+			return nil, fmt.Errorf("%s: %w: %s", op, errs.ErrUnknownSlot, slot)
+		}
+	}
+
+	for slot, rawID := range in.Spec.Images {
+		if !slices.ContainsFunc(template.ImageSlots, func(s model.CardImageSlot) bool { return s.Name == slot }) {
+			uc.log.Error("Unknown image slot", logFields("slot", slot)...)
+			return nil, fmt.Errorf("%s: %w: %s", op, errs.ErrUnknownSlot, slot)
+		}
+
+		sourceID, err := uuid.Parse(rawID)
+		if err != nil {
+			uc.log.Error("Failed to parse source image UUID", logFields("error", err, "slot", slot)...)
+			return nil, fmt.Errorf("%s: slot %s: %w", op, slot, err)
+		}
+		if _, err = uc.repo.Get(ctx, sourceID); err != nil {
+			uc.log.Error("Source image not found", logFields("error", err, "slot", slot)...)
+			return nil, fmt.Errorf("%s: image %s not found: %w", op, rawID, err)
+		}
+	}
+
+	format := in.Spec.Format
+	if format == "" {
+		format = cardFormat
+	}
+
+	spec := in.Spec
+	imageMetadata, err := uc.enqueueGenerated(ctx, fmt.Sprintf("%s.%s", template.Name, format), format, &model.ProcessingImage{
+		Card: &spec,
+	})
+	if err != nil {
+		uc.log.Error("Failed to enqueue card", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully created card task", logFields("image_id", imageMetadata.ID.String())...)
+
+	return &output.RenderCardOutput{
+		ImageID:   imageMetadata.ID.String(),
+		Status:    imageMetadata.Status.String(),
+		ResultURL: imageMetadata.ResultURL.String(),
+	}, nil
+}
+
+func (uc *UseCase) renderCard(ctx context.Context, spec *model.CardRenderSpec) (*model.ProcessingResult, error) {
+	templateID, err := uuid.Parse(spec.TemplateID)
+	if err != nil {
+		return nil, permanent(err)
+	}
+
+	template, err := uc.templates.Get(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("template %s not found: %w", templateID, err)
+	}
+
+	content := model.CardContent{
+		Images: make(map[string][]byte, len(spec.Images)),
+		Texts:  spec.Texts,
+	}
+	for slot, rawID := range spec.Images {
+		if _, content.Images[slot], err = uc.getSource(ctx, rawID); err != nil {
+			return nil, err
+		}
+	}
+
+	format := spec.Format
+	if format == "" {
+		format = cardFormat
+	}
+
+	result, err := uc.processor.RenderCard(*template, content, format, spec.Quality)
+	if err != nil {
+		return nil, permanent(err)
+	}
+	return result, nil
+}
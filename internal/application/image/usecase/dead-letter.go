@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/output"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// SaveDeadLetter stores a task that ran out of retries and marks its image
+// failed, in one transaction, so that a letter is never stored for an image
+// left processing. A letter delivered again is ignored; an error is returned
+// so that the letter is consumed again.
+func (uc *UseCase) SaveDeadLetter(ctx context.Context, letter *model.DeadLetter) error {
+	const op = "image.UseCase.SaveDeadLetter"
+	logFields := logger.WithFields("operation", op, "key", letter.Key)
+
+	task, imageID, decodeErr := decodeTask(letter.Payload)
+
+	var failed bool
+	err := uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+		created, innerErr := uc.letters.Save(ctx, options.DeadLetterCreateParams{
+			ID:        uuid.New(),
+			Topic:     letter.Topic,
+			Partition: letter.Partition,
+			Offset:    letter.Offset,
+			Key:       letter.Key,
+			Payload:   letter.Payload,
+			Error:     letter.Error,
+			Attempts:  letter.Attempts,
+			FailedAt:  letter.FailedAt,
+			CreatedAt: time.Now(),
+		})
+		if innerErr != nil {
+			return fmt.Errorf("save dead letter: %w", innerErr)
+		}
+		if !created {
+			uc.log.Debug("Dead letter is already stored", logFields()...)
+			return nil
+		}
+
+		uc.log.Warn("Task dead-lettered", logFields(
+			"error", letter.Error,
+			"attempts", letter.Attempts,
+		)...)
+
+		if decodeErr != nil {
+			// nothing to mark, the message does not name an image
+			uc.log.Warn("Dead letter has no valid task", logFields("error", decodeErr)...)
+			return nil
+		}
+		cancelled, innerErr := uc.isCancelled(ctx, imageID)
+		if innerErr != nil {
+			return fmt.Errorf("check cancellation: %w", innerErr)
+		}
+		if cancelled {
+			// the user stopped the processing, the failure is not reported
+			uc.log.Info("Dead-lettered image is cancelled", logFields("image_id", imageID.String())...)
+			return nil
+		}
+
+		failed = true
+		return uc.recordResult(ctx, imageID, task.CallbackURL, errors.New(letter.Error))
+	})
+	if err != nil {
+		uc.log.Error("Failed to save dead letter", logFields("error", err)...)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if failed {
+		uc.refreshBatches(ctx, imageID)
+	}
+
+	return nil
+}
+
+func (uc *UseCase) ListDeadLetters(ctx context.Context, in input.ListDeadLettersInput) (*output.ListDeadLettersOutput, error) {
+	const op = "image.UseCase.ListDeadLetters"
+	logFields := logger.WithFields("operation", op)
+
+	uc.log.Info("Attempting to list dead letters", logFields("limit", in.Limit, "offset", in.Offset)...)
+
+	letters, err := uc.letters.List(ctx, options.PaginationParams{
+		Limit:  in.Limit,
+		Offset: in.Offset,
+	})
+	if err != nil {
+		uc.log.Error("Failed to list dead letters", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully listed dead letters", logFields("count", len(letters))...)
+
+	return &output.ListDeadLettersOutput{DeadLetters: letters}, nil
+}
+
+// ReplayDeadLetter puts the task of a dead letter back to the queue and
+// returns its image to processing. Only a failed image is replayed. A letter
+// is replayed once; a task that fails again is dead-lettered as a new letter.
+func (uc *UseCase) ReplayDeadLetter(ctx context.Context, in input.ReplayDeadLetterInput) (*output.ReplayDeadLetterOutput, error) {
+	const op = "image.UseCase.ReplayDeadLetter"
+	logFields := logger.WithFields("operation", op, "dead_letter_id", in.DeadLetterID)
+
+	uc.log.Info("Attempting to replay dead letter", logFields()...)
+
+	letterID, err := uuid.Parse(in.DeadLetterID)
+	if err != nil {
+		uc.log.Error("Failed to parse dead letter UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	letter, err := uc.letters.Get(ctx, letterID)
+	if err != nil {
+		uc.log.Error("Dead letter not found", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: dead letter not found: %w", op, err)
+	}
+	if letter.ReplayedAt != nil {
+		return nil, fmt.Errorf("%s: %w", op, errs.ErrAlreadyReplayed)
+	}
+
+	task, imageID, err := decodeTask(letter.Payload)
+	if err != nil {
+		uc.log.Error("Failed to decode dead letter task", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w: %w", op, errs.ErrUndecodableTask, err)
+	}
+
+	if _, err = uc.repo.Get(ctx, imageID); err != nil {
+		uc.log.Error("Image not found", logFields("error", err, "image_id", imageID.String())...)
+		return nil, fmt.Errorf("%s: image not found: %w", op, err)
+	}
+
+	replayedAt := time.Now()
+	txErr := uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+		// a cancelled or completed image is not processed again
+		image, innerErr := uc.repo.GetForUpdate(ctx, imageID)
+		if innerErr != nil {
+			return fmt.Errorf("get image: %w", innerErr)
+		}
+		if image.Status != vo.StatusFailed {
+			return fmt.Errorf("%w: %s", errs.ErrNotReplayable, image.Status)
+		}
+
+		replayed, innerErr := uc.letters.MarkReplayed(ctx, letterID, replayedAt)
+		if innerErr != nil {
+			return fmt.Errorf("mark replayed: %w", innerErr)
+		}
+		if !replayed {
+			return errs.ErrAlreadyReplayed
+		}
+
+		if innerErr = uc.repo.UpdateStatus(ctx, options.ImageUpdateParams{
+			ImageID: imageID,
+			Status:  vo.StatusProcessing,
+		}); innerErr != nil {
+			return fmt.Errorf("update status: %w", innerErr)
+		}
+
+		task.Timestamp = replayedAt
+		if innerErr = uc.saveTask(ctx, task); innerErr != nil {
+			return fmt.Errorf("enqueue image task: %w", innerErr)
+		}
+
+		return nil
+	})
+	if txErr != nil {
+		uc.log.Error("Failed to replay dead letter", logFields("error", txErr)...)
+		return nil, fmt.Errorf("%s: %w", op, txErr)
+	}
+
+	letter.ReplayedAt = &replayedAt
+	uc.log.Info("Successfully replayed dead letter", logFields("image_id", imageID.String())...)
+
+	return &output.ReplayDeadLetterOutput{DeadLetter: letter}, nil
+}
+
+func decodeTask(payload string) (*model.ProcessingImage, uuid.UUID, error) {
+	var task model.ProcessingImage
+	if err := json.Unmarshal([]byte(payload), &task); err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	imageID, err := uuid.Parse(task.ImageID)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("parse image UUID: %w", err)
+	}
+
+	return &task, imageID, nil
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/output"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// CreatePresignedUpload reserves an image ID and returns a URL the client
+// puts the original to, bypassing the API.
+func (uc *UseCase) CreatePresignedUpload(ctx context.Context, in input.CreatePresignedUploadInput) (*output.PresignedUploadOutput, error) {
+	const op = "image.UseCase.CreatePresignedUpload"
+	logFields := logger.WithFields("operation", op, "filename", in.Filename)
+
+	uc.log.Info("Creating presigned upload...", logFields("size", in.Size)...)
+
+	sessionID := uuid.New()
+	uploadURL, err := uc.s3.PresignOriginalUpload(ctx, sessionID.String(), options.S3PresignedDuration)
+	if err != nil {
+		uc.log.Error("Failed to presign upload", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	session, err := uc.uploads.Save(ctx, options.UploadSessionCreateParams{
+		ID:              sessionID,
+		Method:          vo.UploadMethodPresigned,
+		Filename:        in.Filename,
+		Size:            in.Size,
+		Options:         in.Options,
+		DuplicatePolicy: in.DuplicatePolicy,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(options.S3PresignedDuration),
+	})
+	if err != nil {
+		uc.log.Error("Failed to save upload session", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully created presigned upload", logFields("upload_id", session.ID.String())...)
+
+	return &output.PresignedUploadOutput{
+		UploadID:  session.ID.String(),
+		ImageID:   session.ID.String(),
+		UploadURL: uploadURL,
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// CompletePresignedUpload validates the object put by the client and
+// registers it as an image. Completing an upload again returns the same image.
+func (uc *UseCase) CompletePresignedUpload(ctx context.Context, in input.CompletePresignedUploadInput) (*output.UploadImageOutput, error) {
+	const op = "image.UseCase.CompletePresignedUpload"
+	logFields := logger.WithFields("operation", op, "upload_id", in.UploadID)
+
+	uc.log.Info("Completing presigned upload...", logFields()...)
+
+	sessionID, err := uuid.Parse(in.UploadID)
+	if err != nil {
+		uc.log.Error("Failed to parse upload UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		result    *output.UploadImageOutput
+		uploadErr error
+	)
+	txErr := uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+		session, err := uc.uploads.GetForUpdate(ctx, sessionID)
+		if err != nil {
+			return fmt.Errorf("upload not found: %w", err)
+		}
+
+		switch {
+		case session.Method != vo.UploadMethodPresigned:
+			return fmt.Errorf("%w: %s", errs.ErrWrongUploadMethod, session.Method)
+		case session.IsCompleted():
+			completed, err := uc.completedUpload(ctx, session)
+			if err != nil {
+				return err
+			}
+			result = completed.Upload
+			return nil
+		case session.IsExpired(time.Now()):
+			return errs.ErrUploadExpired
+		}
+
+		result, uploadErr = uc.completePresigned(ctx, session)
+		if errors.Is(uploadErr, errs.ErrDuplicateImage) {
+			// the rejected original is already removed
+			return uc.uploads.Delete(ctx, sessionID)
+		}
+		return uploadErr
+	})
+	if txErr != nil {
+		uc.log.Error("Failed to complete presigned upload", logFields("error", txErr)...)
+		return nil, fmt.Errorf("%s: %w", op, txErr)
+	}
+	if uploadErr != nil {
+		uc.log.Error("Failed to register uploaded image", logFields("error", uploadErr)...)
+		return nil, fmt.Errorf("%s: %w", op, uploadErr)
+	}
+
+	uc.log.Info("Successfully completed presigned upload", logFields("image_id", result.ImageID)...)
+
+	return result, nil
+}
+
+// completePresigned checks the size and the format of the stored object and
+// registers it, computing the checksum on the way.
+func (uc *UseCase) completePresigned(ctx context.Context, session *model.UploadSession) (*output.UploadImageOutput, error) {
+	key := vo.NewFilenameOriginal(session.ID.String()).String()
+
+	exists, err := uc.s3.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errs.ErrObjectNotUploaded
+	}
+
+	fileInfo, err := uc.s3.GetFileInfo(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo.Size != session.Size {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", errs.ErrUploadSizeMismatch, session.Size, fileInfo.Size)
+	}
+
+	object, err := uc.s3.OpenOriginal(ctx, session.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = object.Close() }()
+
+	hasher := sha256.New()
+	reader := io.TeeReader(object, hasher)
+
+	info, err := uc.processor.Inspect(reader)
+	if err != nil {
+		uc.deleteOriginal(ctx, session.ID)
+		return nil, fmt.Errorf("%w: %w", errs.ErrInvalidImage, err)
+	}
+	if _, err = io.Copy(io.Discard, reader); err != nil {
+		return nil, fmt.Errorf("read original: %w", err)
+	}
+
+	// the content type is set by the client, the decoded format is trusted instead
+	fileInfo.MimeType = "image/" + info.Format
+
+	upload, err := uc.registerOriginal(ctx, originalUpload{
+		imageID:         session.ID,
+		filename:        session.Filename,
+		fileInfo:        fileInfo,
+		sha256:          hex.EncodeToString(hasher.Sum(nil)),
+		options:         session.Options,
+		duplicatePolicy: session.DuplicatePolicy,
+		keepOnFailure:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = uc.completeSession(ctx, session, upload); err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
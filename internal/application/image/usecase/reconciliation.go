@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/output"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type stuckImageAction int
+
+const (
+	stuckImageSkipped stuckImageAction = iota
+	stuckImageCompleted
+	stuckImageRequeued
+	stuckImageFailed
+)
+
+// ReconcileStuckImages settles the images processing for longer than the
+// timeout; the storage, the database and the queue are not updated
+// atomically, so a task can get lost between them. An image whose result is
+// stored is completed, otherwise its latest task is enqueued again, up to
+// MaxRequeues times, after which the image is failed.
+func (uc *UseCase) ReconcileStuckImages(ctx context.Context, in input.ReconcileStuckImagesInput) (*output.ReconcileStuckImagesOutput, error) {
+	const op = "image.UseCase.ReconcileStuckImages"
+	logFields := logger.WithFields("operation", op)
+
+	stuckImages, err := uc.repo.ClaimStuck(ctx, options.StuckImageClaimParams{
+		Timeout: in.Timeout,
+		Limit:   options.ReconcileBatchSize,
+	})
+	if err != nil {
+		uc.log.Error("Failed to claim stuck images", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := &output.ReconcileStuckImagesOutput{Claimed: len(stuckImages)}
+	for _, stuckImage := range stuckImages {
+		if ctx.Err() != nil {
+			break
+		}
+
+		action, err := uc.reconcileStuckImage(ctx, stuckImage, in.MaxRequeues)
+		if err != nil {
+			uc.log.Error("Failed to reconcile stuck image", logFields(
+				"image_id", stuckImage.ImageID.String(),
+				"error", err,
+			)...)
+			result.Errors++
+			continue
+		}
+
+		switch action {
+		case stuckImageCompleted:
+			result.Completed++
+		case stuckImageRequeued:
+			result.Requeued++
+		case stuckImageFailed:
+			result.Failed++
+		default:
+			result.Skipped++
+		}
+	}
+
+	return result, nil
+}
+
+func (uc *UseCase) reconcileStuckImage(ctx context.Context, stuckImage model.StuckImage, maxRequeues int) (stuckImageAction, error) {
+	const op = "image.UseCase.reconcileStuckImage"
+	imageID := stuckImage.ImageID
+	logFields := logger.WithFields("operation", op, "image_id", imageID.String(), "requeues", stuckImage.Requeues)
+
+	message, err := uc.outbox.FindLatest(ctx, imageID)
+	if err != nil {
+		return stuckImageSkipped, fmt.Errorf("find task: %w", err)
+	}
+	if message != nil && message.SentAt == nil && message.FailedAt == nil {
+		uc.log.Info("Task of stuck image is not published yet", logFields()...)
+		return stuckImageSkipped, nil
+	}
+
+	var callbackURL string
+	if message != nil && message.Task != nil {
+		callbackURL = message.Task.CallbackURL
+	}
+
+	processed, err := uc.s3.Exists(ctx, imageID.String())
+	if err != nil {
+		return stuckImageSkipped, fmt.Errorf("check processed object: %w", err)
+	}
+	if processed && stuckImage.HasProcessedData {
+		if err = uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+			if innerErr := uc.repo.UpdateStatus(ctx, options.ImageUpdateParams{
+				ImageID: imageID,
+				Status:  vo.StatusCompleted,
+			}); innerErr != nil {
+				return fmt.Errorf("update status: %w", innerErr)
+			}
+			return uc.recordResult(ctx, imageID, callbackURL, nil)
+		}); err != nil {
+			return stuckImageSkipped, fmt.Errorf("complete image: %w", err)
+		}
+		uc.refreshBatches(ctx, imageID)
+		uc.log.Warn("Completed stuck image, its result is stored", logFields()...)
+		return stuckImageCompleted, nil
+	}
+
+	var reason string
+	switch {
+	case message == nil:
+		reason = "processing task is lost"
+	case message.FailedAt != nil || message.Task == nil:
+		reason = "processing task could not be published"
+	case stuckImage.Requeues >= maxRequeues:
+		reason = fmt.Sprintf("processing did not finish after %d requeues", stuckImage.Requeues)
+	case message.Task.ContactSheet == nil && message.Task.Card == nil:
+		original, err := uc.s3.Exists(ctx, vo.NewFilenameOriginal(imageID.String()).String())
+		if err != nil {
+			return stuckImageSkipped, fmt.Errorf("check original object: %w", err)
+		}
+		if !original {
+			reason = "original image is missing"
+		}
+	}
+	if reason != "" {
+		if err = uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+			return uc.recordResult(ctx, imageID, callbackURL, errors.New(reason))
+		}); err != nil {
+			return stuckImageSkipped, fmt.Errorf("fail image: %w", err)
+		}
+		uc.refreshBatches(ctx, imageID)
+		uc.log.Warn("Failed stuck image", logFields("reason", reason)...)
+		return stuckImageFailed, nil
+	}
+
+	task := *message.Task
+	task.Timestamp = time.Now()
+	requeued := false
+	if err = uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+		var innerErr error
+		if requeued, innerErr = uc.repo.MarkRequeued(ctx, imageID); innerErr != nil || !requeued {
+			return innerErr
+		}
+		// the processed object is lost, the data is saved again with it
+		if stuckImage.HasProcessedData {
+			if innerErr = uc.repo.DeleteProcessed(ctx, imageID); innerErr != nil {
+				return fmt.Errorf("delete processed data: %w", innerErr)
+			}
+		}
+		return uc.saveTask(ctx, &task)
+	}); err != nil {
+		return stuckImageSkipped, fmt.Errorf("requeue task: %w", err)
+	}
+	if !requeued {
+		// the image left the processing status meanwhile
+		return stuckImageSkipped, nil
+	}
+
+	uc.log.Warn("Requeued task of stuck image", logFields()...)
+	return stuckImageRequeued, nil
+}
+
+// ReconcileOrphanOriginals finds the originals that belong to no image, left
+// behind when the cleanup after a failed upload fails as well. The originals
+// of uploads that can still be completed are never reported.
+func (uc *UseCase) ReconcileOrphanOriginals(ctx context.Context, in input.ReconcileOrphanOriginalsInput) (*output.ReconcileOrphanOriginalsOutput, error) {
+	const op = "image.UseCase.ReconcileOrphanOriginals"
+	logFields := logger.WithFields("operation", op)
+
+	prefix := vo.NewFilenameOriginal("").String()
+	cutoff := time.Now().Add(-max(in.MinAge, options.S3PresignedDuration, options.UploadSessionTTL))
+	result := &output.ReconcileOrphanOriginalsOutput{Orphans: []string{}}
+
+	// the originals are listed page by page, each page is looked up at once
+	var startAfter string
+	for ctx.Err() == nil {
+		files, err := uc.s3.ListFiles(ctx, prefix, startAfter, options.ReconcileBatchSize)
+		if err != nil {
+			uc.log.Error("Failed to list originals", logFields("error", err)...)
+			return nil, fmt.Errorf("%s: list originals: %w", op, err)
+		}
+		if len(files) == 0 {
+			break
+		}
+		startAfter = files[len(files)-1].Path
+
+		candidates := make([]uuid.UUID, 0, len(files))
+		for _, file := range files {
+			imageID, err := uuid.Parse(strings.TrimPrefix(file.Path, prefix))
+			if err != nil || file.ModifiedAt.After(cutoff) {
+				continue
+			}
+			candidates = append(candidates, imageID)
+		}
+
+		if err = uc.reconcileOrphanCandidates(ctx, candidates, in.Delete, result); err != nil {
+			uc.log.Error("Failed to look up images", logFields("error", err)...)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(files) < options.ReconcileBatchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// reconcileOrphanCandidates reports, and deletes if asked, the originals of
+// the candidates that have no image.
+func (uc *UseCase) reconcileOrphanCandidates(
+	ctx context.Context,
+	candidates []uuid.UUID,
+	deleteOrphans bool,
+	result *output.ReconcileOrphanOriginalsOutput,
+) error {
+	const op = "image.UseCase.reconcileOrphanCandidates"
+	logFields := logger.WithFields("operation", op)
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	existing, err := uc.repo.ListExistingIDs(ctx, candidates)
+	if err != nil {
+		return fmt.Errorf("list images: %w", err)
+	}
+	known := make(map[uuid.UUID]struct{}, len(existing))
+	for _, imageID := range existing {
+		known[imageID] = struct{}{}
+	}
+
+	for _, imageID := range candidates {
+		if _, ok := known[imageID]; ok {
+			continue
+		}
+
+		result.Orphans = append(result.Orphans, imageID.String())
+		uc.log.Warn("Original has no image", logFields("image_id", imageID.String())...)
+		if !deleteOrphans {
+			continue
+		}
+		if err = uc.s3.DeleteOriginal(ctx, imageID.String()); err != nil {
+			uc.log.Error("Failed to delete orphaned original", logFields("image_id", imageID.String(), "error", err)...)
+			continue
+		}
+		result.Deleted++
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/output"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// SubscribeStatus opens a subscription to the status changes of the images.
+// The caller closes the subscription.
+func (uc *UseCase) SubscribeStatus(ctx context.Context, in input.SubscribeStatusInput) (*output.SubscribeStatusOutput, error) {
+	const op = "image.UseCase.SubscribeStatus"
+
+	subscription := uc.statuses.Subscribe()
+	watched, err := uc.WatchStatus(ctx, input.WatchStatusInput{
+		Subscription: subscription,
+		ImageIDs:     in.ImageIDs,
+	})
+	if err != nil {
+		subscription.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &output.SubscribeStatusOutput{
+		Subscription: subscription,
+		Current:      watched.Current,
+	}, nil
+}
+
+// WatchStatus adds the images to the subscription and returns their current
+// status. The images are watched before the status is read, so that no
+// transition in between is missed. On error none of them is watched.
+func (uc *UseCase) WatchStatus(ctx context.Context, in input.WatchStatusInput) (*output.WatchStatusOutput, error) {
+	const op = "image.UseCase.WatchStatus"
+	logFields := logger.WithFields("operation", op)
+
+	imageIDs, err := parseImageIDs(in.ImageIDs)
+	if err != nil {
+		uc.log.Error("Failed to parse image UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	in.Subscription.Watch(imageIDs...)
+
+	current := make([]model.StatusEvent, 0, len(imageIDs))
+	for _, imageID := range imageIDs {
+		image, err := uc.repo.Get(ctx, imageID)
+		if err != nil {
+			in.Subscription.Unwatch(imageIDs...)
+			uc.log.Error("Image not found", logFields("error", err, "image_id", imageID.String())...)
+			return nil, fmt.Errorf("%s: image %s not found: %w", op, imageID, err)
+		}
+		current = append(current, model.StatusEvent{
+			ImageID:   image.ID.String(),
+			Status:    image.Status.String(),
+			Timestamp: time.Now(),
+		})
+	}
+
+	return &output.WatchStatusOutput{Current: current}, nil
+}
+
+func (uc *UseCase) UnwatchStatus(_ context.Context, in input.WatchStatusInput) error {
+	const op = "image.UseCase.UnwatchStatus"
+
+	imageIDs, err := parseImageIDs(in.ImageIDs)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	in.Subscription.Unwatch(imageIDs...)
+
+	return nil
+}
+
+func parseImageIDs(rawIDs []string) ([]uuid.UUID, error) {
+	imageIDs := make([]uuid.UUID, 0, len(rawIDs))
+	for _, rawID := range rawIDs {
+		imageID, err := uuid.Parse(rawID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image ID %q: %w", rawID, err)
+		}
+		imageIDs = append(imageIDs, imageID)
+	}
+	return imageIDs, nil
+}
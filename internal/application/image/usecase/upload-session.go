@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/output"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// uploadPartSize is the smallest part S3 accepts except for the last one.
+// Smaller chunks are kept in the session until a part can be uploaded.
+const uploadPartSize = 5 << 20
+
+func (uc *UseCase) CreateUploadSession(ctx context.Context, in input.CreateUploadSessionInput) (*output.UploadSessionOutput, error) {
+	const op = "image.UseCase.CreateUploadSession"
+	logFields := logger.WithFields("operation", op, "filename", in.Filename)
+
+	uc.log.Info("Creating upload session...", logFields("size", in.Size)...)
+
+	sessionID := uuid.New()
+	uploadID, err := uc.s3.CreateOriginalUpload(ctx, sessionID.String())
+	if err != nil {
+		uc.log.Error("Failed to create multipart upload", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hashState, err := marshalHash(sha256.New())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	session, err := uc.uploads.Save(ctx, options.UploadSessionCreateParams{
+		ID:              sessionID,
+		Method:          vo.UploadMethodChunked,
+		Filename:        in.Filename,
+		Size:            in.Size,
+		Options:         in.Options,
+		DuplicatePolicy: in.DuplicatePolicy,
+		S3UploadID:      uploadID,
+		HashState:       hashState,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(options.UploadSessionTTL),
+	})
+	if err != nil {
+		uc.log.Error("Failed to save upload session", logFields("error", err)...)
+		if abortErr := uc.s3.AbortOriginalUpload(ctx, sessionID.String(), uploadID); abortErr != nil {
+			uc.log.Error("Failed to abort multipart upload", logFields("error", abortErr)...)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully created upload session", logFields("upload_id", session.ID.String())...)
+
+	return uploadSessionOutput(session), nil
+}
+
+func (uc *UseCase) GetUploadSession(ctx context.Context, in input.GetUploadSessionInput) (*output.UploadSessionOutput, error) {
+	const op = "image.UseCase.GetUploadSession"
+	logFields := logger.WithFields("operation", op, "upload_id", in.UploadID)
+
+	sessionID, err := uuid.Parse(in.UploadID)
+	if err != nil {
+		uc.log.Error("Failed to parse upload UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	session, err := uc.uploads.Get(ctx, sessionID)
+	if err != nil {
+		uc.log.Error("Upload session not found", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: upload not found: %w", op, err)
+	}
+	if session.IsExpired(time.Now()) {
+		return nil, fmt.Errorf("%s: %w", op, errs.ErrUploadExpired)
+	}
+
+	return uploadSessionOutput(session), nil
+}
+
+// AppendUploadChunk writes the chunk at the offset of the session and
+// completes the upload with its last byte. When the chunk is cut off, the
+// received part of it is kept, so the client resumes from the new offset.
+func (uc *UseCase) AppendUploadChunk(ctx context.Context, in input.AppendUploadChunkInput) (*output.AppendUploadChunkOutput, error) {
+	const op = "image.UseCase.AppendUploadChunk"
+	logFields := logger.WithFields("operation", op, "upload_id", in.UploadID, "offset", in.Offset)
+
+	sessionID, err := uuid.Parse(in.UploadID)
+	if err != nil {
+		uc.log.Error("Failed to parse upload UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the chunk is checked before it is read and once again under the lock
+	session, err := uc.uploads.Get(ctx, sessionID)
+	if err != nil {
+		uc.log.Error("Upload session not found", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: upload not found: %w", op, err)
+	}
+	if err = checkChunk(session, in.Offset, in.Size); err != nil {
+		uc.log.Error("Chunk rejected", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if session.IsCompleted() {
+		return uc.completedUpload(ctx, session)
+	}
+
+	chunk := make([]byte, in.Size)
+	n, readErr := io.ReadFull(in.Chunk, chunk)
+	chunk = chunk[:n]
+	if readErr != nil {
+		uc.log.Warn("Chunk interrupted", logFields("error", readErr, "received", n)...)
+		// the request context is canceled together with the connection
+		ctx = context.WithoutCancel(ctx)
+	}
+
+	var (
+		result    *output.AppendUploadChunkOutput
+		uploadErr error
+	)
+	txErr := uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+		session, err := uc.uploads.GetForUpdate(ctx, sessionID)
+		if err != nil {
+			return fmt.Errorf("upload not found: %w", err)
+		}
+		if err = checkChunk(session, in.Offset, int64(len(chunk))); err != nil {
+			return err
+		}
+		if session.IsCompleted() {
+			result, uploadErr = uc.completedUpload(ctx, session)
+			return uploadErr
+		}
+
+		result, uploadErr = uc.appendChunk(ctx, session, chunk)
+		if errors.Is(uploadErr, errs.ErrDuplicateImage) {
+			// the rejected original is already removed
+			return uc.uploads.Delete(ctx, sessionID)
+		}
+		return uploadErr
+	})
+	if txErr != nil {
+		uc.log.Error("Failed to append chunk", logFields("error", txErr)...)
+		return nil, fmt.Errorf("%s: %w", op, txErr)
+	}
+	if uploadErr != nil {
+		uc.log.Error("Failed to complete upload", logFields("error", uploadErr)...)
+		return nil, fmt.Errorf("%s: %w", op, uploadErr)
+	}
+	if readErr != nil {
+		return nil, fmt.Errorf("%s: chunk interrupted at offset %d: %w", op, result.Offset, readErr)
+	}
+
+	uc.log.Info("Successfully appended chunk", logFields("new_offset", result.Offset)...)
+
+	return result, nil
+}
+
+func (uc *UseCase) CancelUploadSession(ctx context.Context, in input.CancelUploadSessionInput) (*output.CancelUploadSessionOutput, error) {
+	const op = "image.UseCase.CancelUploadSession"
+	logFields := logger.WithFields("operation", op, "upload_id", in.UploadID)
+
+	uc.log.Info("Attempting to cancel upload", logFields()...)
+
+	sessionID, err := uuid.Parse(in.UploadID)
+	if err != nil {
+		uc.log.Error("Failed to parse upload UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if txErr := uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+		session, err := uc.uploads.GetForUpdate(ctx, sessionID)
+		if err != nil {
+			return fmt.Errorf("upload not found: %w", err)
+		}
+
+		switch {
+		case session.IsCompleted():
+		case session.Method == vo.UploadMethodChunked:
+			if err = uc.s3.AbortOriginalUpload(ctx, session.ID.String(), session.S3UploadID); err != nil {
+				return fmt.Errorf("abort upload: %w", err)
+			}
+		case session.Method == vo.UploadMethodPresigned:
+			// the client may have put the object already
+			uc.deleteOriginal(ctx, session.ID)
+		}
+
+		return uc.uploads.Delete(ctx, sessionID)
+	}); txErr != nil {
+		uc.log.Error("Failed to cancel upload", logFields("error", txErr)...)
+		return nil, fmt.Errorf("%s: %w", op, txErr)
+	}
+
+	uc.log.Info("Successfully cancelled upload", logFields()...)
+
+	return &output.CancelUploadSessionOutput{
+		Success: true,
+		Message: "Successfully cancelled upload",
+	}, nil
+}
+
+// appendChunk stores the chunk and, when it is the last one, assembles the
+// original and registers it as an image with the ID of the session.
+func (uc *UseCase) appendChunk(
+	ctx context.Context,
+	session *model.UploadSession,
+	chunk []byte,
+) (*output.AppendUploadChunkOutput, error) {
+	hasher, err := restoreHash(session.HashState)
+	if err != nil {
+		return nil, err
+	}
+	hasher.Write(chunk)
+	hashState, err := marshalHash(hasher)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := session.Offset + int64(len(chunk))
+	pending := append(session.Pending, chunk...)
+	parts := session.Parts
+	if len(pending) >= uploadPartSize || (offset == session.Size && len(pending) > 0) {
+		part, err := uc.s3.PutOriginalPart(
+			ctx,
+			session.ID.String(),
+			session.S3UploadID,
+			len(parts)+1,
+			bytes.NewReader(pending),
+			int64(len(pending)),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("upload part: %w", err)
+		}
+		parts = append(parts, *part)
+		pending = nil
+	}
+
+	if offset < session.Size {
+		if err = uc.uploads.UpdateProgress(ctx, options.UploadSessionProgressParams{
+			ID:        session.ID,
+			Offset:    offset,
+			Parts:     parts,
+			Pending:   pending,
+			HashState: hashState,
+			UpdatedAt: time.Now(),
+		}); err != nil {
+			return nil, fmt.Errorf("update upload progress: %w", err)
+		}
+		return &output.AppendUploadChunkOutput{Offset: offset}, nil
+	}
+
+	fileInfo, err := uc.s3.CompleteOriginalUpload(ctx, session.ID.String(), session.S3UploadID, parts)
+	if err != nil {
+		// a previous attempt may have assembled the original and failed later
+		var statErr error
+		if fileInfo, statErr = uc.s3.GetFileInfo(ctx, vo.NewFilenameOriginal(session.ID.String()).String()); statErr != nil {
+			return nil, fmt.Errorf("complete upload: %w", err)
+		}
+	}
+
+	upload, err := uc.registerOriginal(ctx, originalUpload{
+		imageID:         session.ID,
+		filename:        session.Filename,
+		fileInfo:        fileInfo,
+		sha256:          hex.EncodeToString(hasher.Sum(nil)),
+		options:         session.Options,
+		duplicatePolicy: session.DuplicatePolicy,
+		keepOnFailure:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = uc.completeSession(ctx, session, upload); err != nil {
+		return nil, err
+	}
+
+	return &output.AppendUploadChunkOutput{
+		Offset: offset,
+		Upload: upload,
+	}, nil
+}
+
+// completeSession links the session to the image its original became.
+func (uc *UseCase) completeSession(ctx context.Context, session *model.UploadSession, upload *output.UploadImageOutput) error {
+	imageID, err := uuid.Parse(upload.ImageID)
+	if err != nil {
+		return err
+	}
+	if err = uc.uploads.Complete(ctx, options.UploadSessionCompleteParams{
+		ID:        session.ID,
+		ImageID:   imageID,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("complete upload session: %w", err)
+	}
+	return nil
+}
+
+// completedUpload repeats the result of the request that completed the upload.
+func (uc *UseCase) completedUpload(ctx context.Context, session *model.UploadSession) (*output.AppendUploadChunkOutput, error) {
+	image, err := uc.repo.Get(ctx, *session.ImageID)
+	if err != nil {
+		return nil, fmt.Errorf("image %s not found: %w", session.ImageID, err)
+	}
+
+	upload := &output.UploadImageOutput{
+		ImageID:   image.ID.String(),
+		Status:    image.Status.String(),
+		ResultURL: image.ResultURL.String(),
+		Duplicate: image.ID != session.ID,
+	}
+	if upload.Duplicate {
+		upload.Message = "Image is a duplicate of an existing image"
+	}
+
+	return &output.AppendUploadChunkOutput{
+		Offset: session.Size,
+		Upload: upload,
+	}, nil
+}
+
+func checkChunk(session *model.UploadSession, offset, size int64) error {
+	switch {
+	case session.Method != vo.UploadMethodChunked:
+		return fmt.Errorf("%w: %s", errs.ErrWrongUploadMethod, session.Method)
+	case session.IsExpired(time.Now()):
+		return errs.ErrUploadExpired
+	case session.IsCompleted():
+		if offset != session.Size {
+			return fmt.Errorf("%w: upload is completed", errs.ErrOffsetMismatch)
+		}
+	case offset != session.Offset:
+		return fmt.Errorf("%w: expected %d, got %d", errs.ErrOffsetMismatch, session.Offset, offset)
+	case offset+size > session.Size:
+		return fmt.Errorf("%w: %d bytes at offset %d of %d", errs.ErrChunkExceedsLength, size, offset, session.Size)
+	}
+	return nil
+}
+
+func uploadSessionOutput(session *model.UploadSession) *output.UploadSessionOutput {
+	out := &output.UploadSessionOutput{
+		UploadID:  session.ID.String(),
+		Method:    session.Method.String(),
+		Offset:    session.Offset,
+		Length:    session.Size,
+		ExpiresAt: session.ExpiresAt,
+	}
+	if session.IsCompleted() {
+		out.ImageID = session.ImageID.String()
+	}
+	return out
+}
+
+// marshalHash saves the state of the checksum, so that it can be continued
+// with the next chunk.
+func marshalHash(h hash.Hash) ([]byte, error) {
+	marshaler, ok := h.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, errors.New("hash state cannot be saved")
+	}
+	return marshaler.MarshalBinary()
+}
+
+func restoreHash(state []byte) (hash.Hash, error) {
+	h := sha256.New()
+	unmarshaler, ok := h.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return nil, errors.New("hash state cannot be restored")
+	}
+	if err := unmarshaler.UnmarshalBinary(state); err != nil {
+		return nil, fmt.Errorf("restore hash state: %w", err)
+	}
+	return h, nil
+}
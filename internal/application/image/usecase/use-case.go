@@ -2,9 +2,13 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"time"
 
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
 	"github.com/D1sordxr/image-processor/internal/application/image/input"
 	"github.com/D1sordxr/image-processor/internal/application/image/output"
 	appPorts "github.com/D1sordxr/image-processor/internal/domain/app/port"
@@ -17,12 +21,24 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	contactSheetFormat = "png"
+	cardFormat         = "png"
+)
+
 type UseCase struct {
 	log       appPorts.Logger
 	txManager appPorts.TxManager
 	repo      port.Repository
+	templates port.CardTemplateRepository
+	uploads   port.UploadSessionRepository
+	batches   port.BatchRepository
+	webhooks  port.WebhookRepository
+	letters   port.DeadLetterRepository
+	sender    port.WebhookSender
+	statuses  port.StatusBroker
 	s3        port.S3Repository
-	queue     port.Queue
+	outbox    port.OutboxRepository
 	processor port.ImageProcessor
 	baseURL   sharedVO.BaseURL
 }
@@ -31,8 +47,15 @@ func New(
 	log appPorts.Logger,
 	txManager appPorts.TxManager,
 	repo port.Repository,
+	templates port.CardTemplateRepository,
+	uploads port.UploadSessionRepository,
+	batches port.BatchRepository,
+	webhooks port.WebhookRepository,
+	letters port.DeadLetterRepository,
+	sender port.WebhookSender,
+	statuses port.StatusBroker,
 	s3 port.S3Repository,
-	queue port.Queue,
+	outbox port.OutboxRepository,
 	processor port.ImageProcessor,
 	baseURL sharedVO.BaseURL,
 
@@ -41,8 +64,15 @@ func New(
 		log:       log,
 		txManager: txManager,
 		repo:      repo,
+		templates: templates,
+		uploads:   uploads,
+		batches:   batches,
+		webhooks:  webhooks,
+		letters:   letters,
+		sender:    sender,
+		statuses:  statuses,
 		s3:        s3,
-		queue:     queue,
+		outbox:    outbox,
 		processor: processor,
 		baseURL:   baseURL,
 	}
@@ -57,40 +87,112 @@ func (uc *UseCase) Upload(ctx context.Context, in input.UploadImageInput) (*outp
 	)...)
 
 	imageID := uuid.New()
-	filename := vo.NewFilenameOriginal(in.Filename)
-	resultURL := vo.NewResultUrl(uc.baseURL, imageID.String())
 
-	fileInfo, err := uc.s3.SaveOriginal(ctx, in.ImageData, imageID.String())
+	// the checksum is computed while streaming, so duplicates are detected
+	// only after the original is stored
+	hasher := sha256.New()
+	fileInfo, err := uc.s3.SaveOriginal(ctx, io.TeeReader(in.Image, hasher), in.Size, imageID.String())
 	if err != nil {
 		uc.log.Error("Failed to upload image", logFields("error", err)...)
 		return nil, fmt.Errorf("%s: failed to save original image: %w", op, err)
 	}
+	imageSHA256 := hex.EncodeToString(hasher.Sum(nil))
+
+	return uc.registerOriginal(ctx, originalUpload{
+		imageID:         imageID,
+		filename:        in.Filename,
+		fileInfo:        fileInfo,
+		sha256:          imageSHA256,
+		options:         in.Options,
+		duplicatePolicy: in.DuplicatePolicy,
+		callbackURL:     in.CallbackURL,
+	})
+}
+
+// originalUpload is an original that is already stored under the image ID.
+type originalUpload struct {
+	imageID         uuid.UUID
+	filename        string
+	fileInfo        *model.FileInfo
+	sha256          string
+	options         model.ProcessingOptions
+	duplicatePolicy vo.DuplicatePolicy
+	callbackURL     string
+	// keepOnFailure leaves the original in place when registration fails,
+	// so that it can be retried
+	keepOnFailure bool
+}
+
+// registerOriginal applies the duplicate policy to a stored original, then
+// saves the image together with its processing task.
+func (uc *UseCase) registerOriginal(ctx context.Context, in originalUpload) (*output.UploadImageOutput, error) {
+	const op = "image.UseCase.registerOriginal"
+	logFields := logger.WithFields("operation", op, "image_id", in.imageID.String())
+
+	imageID := in.imageID
+	imageSHA256 := in.sha256
+	fileInfo := in.fileInfo
+	filename := vo.NewFilenameOriginal(in.filename)
+	resultURL := vo.NewResultUrl(uc.baseURL, imageID.String())
+
+	if in.duplicatePolicy == vo.DuplicatePolicyReject || in.duplicatePolicy == vo.DuplicatePolicyDedupe {
+		duplicates, err := uc.repo.FindBySHA256(ctx, imageSHA256)
+		if err != nil {
+			uc.log.Error("Failed to look up duplicates", logFields("error", err)...)
+			if !in.keepOnFailure {
+				uc.deleteOriginal(ctx, imageID)
+			}
+			return nil, fmt.Errorf("%s: find duplicates: %w", op, err)
+		}
+
+		if len(duplicates) > 0 {
+			original := duplicates[0]
+			uc.log.Info("Duplicate image uploaded", logFields(
+				"original_id", original.ID.String(),
+				"policy", in.duplicatePolicy.String(),
+			)...)
+			uc.deleteOriginal(ctx, imageID)
+
+			if in.duplicatePolicy == vo.DuplicatePolicyReject {
+				return nil, fmt.Errorf("%s: %w of %s", op, errs.ErrDuplicateImage, original.ID)
+			}
+			return &output.UploadImageOutput{
+				ImageID:   original.ID.String(),
+				Status:    original.Status.String(),
+				Message:   "Image is a duplicate of an existing image",
+				ResultURL: original.ResultURL.String(),
+				Duplicate: true,
+			}, nil
+		}
+	}
 
 	var imageMetadata *model.ImageMetadata
 	txErr := uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
 		var innerErr error
 		imageMetadata, innerErr = uc.repo.Save(ctx, options.ImageCreateParams{
 			ID:           imageID,
-			OriginalName: in.Filename,
+			OriginalName: in.filename,
 			FileName:     filename,
 			Status:       vo.StatusProcessing,
 			ResultURL:    resultURL,
 			Size:         fileInfo.Size,
 			Format:       fileInfo.MimeType,
 			UploadedAt:   time.Now(),
+			SHA256:       imageSHA256,
 		})
 		if innerErr != nil {
 			uc.log.Error("Failed to save image metadata", logFields("error", innerErr)...)
 			return fmt.Errorf("save image metadata: %w", innerErr)
 		}
 
-		if innerErr = uc.queue.Publish(ctx, &model.ProcessingImage{
-			ImageID:   imageID.String(),
-			Options:   in.Options,
-			Timestamp: time.Now(),
+		if innerErr = uc.saveTask(ctx, &model.ProcessingImage{
+			ImageID:     imageID.String(),
+			Options:     in.options,
+			CallbackURL: in.callbackURL,
+			Timestamp:   time.Now(),
 		}); innerErr != nil {
-			uc.log.Error("Failed to publish image task", logFields("error", innerErr)...)
-			return fmt.Errorf("publish image task: %w", innerErr)
+			uc.log.Error("Failed to enqueue image task", logFields("error", innerErr)...)
+			return fmt.Errorf("enqueue image task: %w", innerErr)
 		}
 
 		return nil
@@ -98,20 +200,13 @@ func (uc *UseCase) Upload(ctx context.Context, in input.UploadImageInput) (*outp
 
 	if txErr != nil {
 		uc.log.Error("Failed to perform transaction", logFields("error", txErr)...)
-
-		if delErr := uc.s3.DeleteOriginal(ctx, imageID.String()); delErr != nil {
-			uc.log.Error("Failed to delete original image after transaction failure",
-				logFields("error", delErr, "image_id", imageID.String())...)
-		} else {
-			uc.log.Info("Successfully cleaned up original image after transaction failure",
-				logFields("image_id", imageID.String())...)
+		if !in.keepOnFailure {
+			uc.deleteOriginal(ctx, imageID)
 		}
-
 		return nil, fmt.Errorf("%s: %w", op, txErr)
 	}
 
-	uc.log.Info("Successfully uploaded image", logFields(
-		"image_id", imageID.String(),
+	uc.log.Info("Successfully registered image", logFields(
 		"status", imageMetadata.Status.String(),
 	)...)
 
@@ -122,6 +217,109 @@ func (uc *UseCase) Upload(ctx context.Context, in input.UploadImageInput) (*outp
 	}, nil
 }
 
+// deleteOriginal removes an original that is not referenced by any image.
+func (uc *UseCase) deleteOriginal(ctx context.Context, imageID uuid.UUID) {
+	const op = "image.UseCase.deleteOriginal"
+	logFields := logger.WithFields("operation", op, "image_id", imageID.String())
+
+	if err := uc.s3.DeleteOriginal(ctx, imageID.String()); err != nil {
+		uc.log.Error("Failed to clean up original image", logFields("error", err)...)
+		return
+	}
+	uc.log.Info("Successfully cleaned up original image", logFields()...)
+}
+
+func (uc *UseCase) CreateContactSheet(ctx context.Context, in input.CreateContactSheetInput) (*output.CreateContactSheetOutput, error) {
+	const op = "image.UseCase.CreateContactSheet"
+	logFields := logger.WithFields("operation", op)
+
+	uc.log.Info("Creating contact sheet...", logFields("images", len(in.Spec.ImageIDs))...)
+
+	for _, rawID := range in.Spec.ImageIDs {
+		sourceID, err := uuid.Parse(rawID)
+		if err != nil {
+			uc.log.Error("Failed to parse source image UUID", logFields("error", err, "source_id", rawID)...)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if _, err = uc.repo.Get(ctx, sourceID); err != nil {
+			uc.log.Error("Source image not found", logFields("error", err, "source_id", rawID)...)
+			return nil, fmt.Errorf("%s: image %s not found: %w", op, rawID, err)
+		}
+	}
+
+	format := in.Spec.Format
+	if format == "" {
+		format = contactSheetFormat
+	}
+
+	spec := in.Spec
+	imageMetadata, err := uc.enqueueGenerated(ctx, fmt.Sprintf("contact-sheet.%s", format), format, &model.ProcessingImage{
+		ContactSheet: &spec,
+	})
+	if err != nil {
+		uc.log.Error("Failed to enqueue contact sheet", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully created contact sheet task", logFields("image_id", imageMetadata.ID.String())...)
+
+	return &output.CreateContactSheetOutput{
+		ImageID:   imageMetadata.ID.String(),
+		Status:    imageMetadata.Status.String(),
+		ResultURL: imageMetadata.ResultURL.String(),
+	}, nil
+}
+
+// enqueueGenerated creates an image that has no original and enqueues the
+// task that produces it.
+func (uc *UseCase) enqueueGenerated(
+	ctx context.Context,
+	name, format string,
+	task *model.ProcessingImage,
+) (*model.ImageMetadata, error) {
+	imageID := uuid.New()
+	resultURL := vo.NewResultUrl(uc.baseURL, imageID.String())
+
+	var imageMetadata *model.ImageMetadata
+	if txErr := uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+		var innerErr error
+		imageMetadata, innerErr = uc.repo.Save(ctx, options.ImageCreateParams{
+			ID:           imageID,
+			OriginalName: name,
+			FileName:     vo.NewFilenameProcessed(imageID.String()),
+			Status:       vo.StatusProcessing,
+			ResultURL:    resultURL,
+			Format:       format,
+			UploadedAt:   time.Now(),
+		})
+		if innerErr != nil {
+			return fmt.Errorf("save image metadata: %w", innerErr)
+		}
+
+		task.ImageID = imageID.String()
+		task.Timestamp = time.Now()
+		if innerErr = uc.saveTask(ctx, task); innerErr != nil {
+			return fmt.Errorf("enqueue image task: %w", innerErr)
+		}
+
+		return nil
+	}); txErr != nil {
+		return nil, txErr
+	}
+
+	return imageMetadata, nil
+}
+
+// saveTask writes the task to the outbox. It must run in the transaction
+// that saves the image, so the task is published only if the image exists.
+func (uc *UseCase) saveTask(ctx context.Context, task *model.ProcessingImage) error {
+	return uc.outbox.Save(ctx, options.OutboxMessageCreateParams{
+		ID:        uuid.New(),
+		Task:      task,
+		CreatedAt: time.Now(),
+	})
+}
+
 func (uc *UseCase) Process(ctx context.Context, image *model.ProcessingImage) error {
 	const op = "image.UseCase.Process"
 	logFields := logger.WithFields("operation", op, "image_id", image.ImageID)
@@ -131,19 +329,59 @@ func (uc *UseCase) Process(ctx context.Context, image *model.ProcessingImage) er
 	imageUUID, err := uuid.Parse(image.ImageID)
 	if err != nil {
 		uc.log.Error("Failed to parse image UUID", logFields("error", err)...)
+		return fmt.Errorf("%s: %w", op, permanent(err))
+	}
+	cancelled, err := uc.isCancelled(ctx, imageUUID)
+	if err != nil {
+		uc.log.Error("Failed to check image cancellation", logFields("error", err)...)
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	if cancelled {
+		uc.log.Info("Image processing is cancelled, skipping task", logFields()...)
+		return nil
+	}
 
-	data, err := uc.s3.GetOriginal(ctx, imageUUID.String())
-	if err != nil {
-		uc.log.Error("Failed to get original image from S3", logFields("error", err)...)
-		return fmt.Errorf("%s: get original: %w", op, err)
+	var result *model.ProcessingResult
+	var phash *uint64
+	switch {
+	case image.ContactSheet != nil:
+		if result, err = uc.composeContactSheet(ctx, image.ContactSheet); err != nil {
+			uc.log.Error("Failed to compose contact sheet", logFields("error", err)...)
+			return fmt.Errorf("%s: compose contact sheet: %w", op, err)
+		}
+	case image.Card != nil:
+		if result, err = uc.renderCard(ctx, image.Card); err != nil {
+			uc.log.Error("Failed to render card", logFields("error", err)...)
+			return fmt.Errorf("%s: render card: %w", op, err)
+		}
+	default:
+		data, err := uc.s3.GetOriginal(ctx, imageUUID.String())
+		if err != nil {
+			uc.log.Error("Failed to get original image from S3", logFields("error", err)...)
+			return fmt.Errorf("%s: get original: %w", op, err)
+		}
+
+		if result, err = uc.processor.ProcessImage(data, image.Options); err != nil {
+			uc.log.Error("Failed to process image", logFields("error", err)...)
+			return fmt.Errorf("%s: process image: %w", op, permanent(err))
+		}
+
+		hash, err := uc.processor.PerceptualHash(data)
+		if err != nil {
+			uc.log.Error("Failed to compute perceptual hash", logFields("error", err)...)
+			return fmt.Errorf("%s: perceptual hash: %w", op, permanent(err))
+		}
+		phash = &hash
 	}
 
-	result, err := uc.processor.ProcessImage(data, image.Options)
-	if err != nil {
-		uc.log.Error("Failed to process image", logFields("error", err)...)
-		return fmt.Errorf("%s: process image: %w", op, err)
+	// the image may be cancelled while it is processed
+	if cancelled, err = uc.isCancelled(ctx, imageUUID); err != nil {
+		uc.log.Error("Failed to check image cancellation", logFields("error", err)...)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cancelled {
+		uc.log.Info("Image processing is cancelled, discarding result", logFields()...)
+		return nil
 	}
 
 	if _, err = uc.s3.Save(ctx, result.ProcessedData, imageUUID.String()); err != nil {
@@ -151,19 +389,11 @@ func (uc *UseCase) Process(ctx context.Context, image *model.ProcessingImage) er
 		return fmt.Errorf("%s: save processed: %w", op, err)
 	}
 
-	var txErr error
-	defer func() {
-		if txErr != nil {
-			if innerErr := uc.repo.UpdateStatus(ctx, options.ImageUpdateParams{
-				ImageID: imageUUID,
-				Status:  vo.StatusFailed,
-			}); innerErr != nil {
-				uc.log.Error("Failed to update image status to failed", logFields("error", innerErr)...)
-			}
+	txErr := uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+		if cancelled, err = uc.isCancelled(ctx, imageUUID); err != nil || cancelled {
+			return err
 		}
-	}()
 
-	txErr = uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
 		if err = uc.repo.UpdateStatus(ctx, options.ImageUpdateParams{
 			ImageID: imageUUID,
 			Status:  vo.StatusCompleted,
@@ -171,15 +401,35 @@ func (uc *UseCase) Process(ctx context.Context, image *model.ProcessingImage) er
 			return fmt.Errorf("update status: %w", err)
 		}
 
+		if phash != nil {
+			if err = uc.repo.UpdatePHash(ctx, options.ImagePHashUpdateParams{
+				ImageID: imageUUID,
+				PHash:   *phash,
+			}); err != nil {
+				return fmt.Errorf("update perceptual hash: %w", err)
+			}
+		}
+
 		if err = uc.repo.SaveProcessed(ctx, options.ProcessedImageCreateParams{
-			ImageID:     imageUUID,
-			Width:       result.Width,
-			Height:      result.Height,
-			ProcessedAt: time.Now(),
+			ImageID:       imageUUID,
+			Width:         result.Width,
+			Height:        result.Height,
+			BlurHash:      result.BlurHash,
+			LQIP:          result.LQIP,
+			DominantColor: result.DominantColor,
+			ColorName:     vo.Color(result.DominantColor).Name(),
+			Palette:       result.Palette,
+			ProcessedAt:   time.Now(),
 		}); err != nil {
 			return fmt.Errorf("save processed data: %w", err)
 		}
 
+		// a failure is recorded when the task is dead-lettered, the consumer
+		// retries it before
+		if err = uc.recordResult(ctx, imageUUID, image.CallbackURL, nil); err != nil {
+			return fmt.Errorf("record result: %w", err)
+		}
+
 		return nil
 	})
 
@@ -187,11 +437,95 @@ func (uc *UseCase) Process(ctx context.Context, image *model.ProcessingImage) er
 		uc.log.Error("Failed to update image metadata", logFields("error", txErr)...)
 		return fmt.Errorf("%s: update metadata: %w", op, txErr)
 	}
+	if cancelled {
+		// cancelled after the check above, the stored result is not used
+		uc.log.Info("Image processing is cancelled, discarding result", logFields()...)
+		if err = uc.s3.Delete(ctx, imageUUID.String()); err != nil {
+			uc.log.Error("Failed to delete discarded result", logFields("error", err)...)
+		}
+		return nil
+	}
 
-	uc.log.Info("Successfully processed image", logFields()...)
+	uc.refreshBatches(ctx, imageUUID)
+
+	uc.log.Info("Successfully processed image", logFields(
+		"size", result.Size,
+		"quality", result.Quality,
+	)...)
 	return nil
 }
 
+// recordResult saves the final state of the image: the failed status and
+// the batch item errors if it could not be processed, and the delivery that
+// notifies the callback URL. It runs in the transaction that finishes the
+// image, so that none of them is lost. A failure is recorded once, when the
+// task is dead-lettered.
+func (uc *UseCase) recordResult(ctx context.Context, imageID uuid.UUID, callbackURL string, processErr error) error {
+	var reason string
+	if processErr != nil {
+		reason = processErr.Error()
+		if err := uc.repo.UpdateStatus(ctx, options.ImageUpdateParams{
+			ImageID: imageID,
+			Status:  vo.StatusFailed,
+		}); err != nil {
+			return fmt.Errorf("update status: %w", err)
+		}
+	}
+
+	if err := uc.batches.UpdateItemsError(ctx, options.BatchItemsErrorParams{
+		ImageID: imageID,
+		Error:   reason,
+	}); err != nil {
+		return fmt.Errorf("update batch items: %w", err)
+	}
+
+	if callbackURL != "" {
+		if err := uc.enqueueWebhook(ctx, imageID, callbackURL, reason); err != nil {
+			return fmt.Errorf("enqueue webhook: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// refreshBatches completes the batches the image belongs to. It runs after
+// the status is committed, so that concurrent workers see each other's
+// results; a batch left unrefreshed is still reported settled by GetBatch.
+func (uc *UseCase) refreshBatches(ctx context.Context, imageID uuid.UUID) {
+	const op = "image.UseCase.refreshBatches"
+
+	if err := uc.batches.RefreshByImage(ctx, imageID, time.Now()); err != nil {
+		uc.log.Error("Failed to refresh image batches", "operation", op, "image_id", imageID.String(), "error", err)
+	}
+}
+
+func (uc *UseCase) composeContactSheet(ctx context.Context, spec *model.ContactSheetSpec) (*model.ProcessingResult, error) {
+	cells := make([]model.ContactSheetCell, 0, len(spec.ImageIDs))
+	for _, rawID := range spec.ImageIDs {
+		source, data, err := uc.getSource(ctx, rawID)
+		if err != nil {
+			return nil, err
+		}
+
+		cells = append(cells, model.ContactSheetCell{
+			ImageData: data,
+			Caption:   source.OriginalName,
+		})
+	}
+
+	result, err := uc.processor.ComposeContactSheet(cells, *spec)
+	if err != nil {
+		return nil, permanent(err)
+	}
+	return result, nil
+}
+
+// permanent marks an error that retrying the task cannot fix, e.g. an image
+// that cannot be decoded. Storage errors are left transient.
+func permanent(err error) error {
+	return fmt.Errorf("%w: %w", port.ErrPermanent, err)
+}
+
 func (uc *UseCase) Get(ctx context.Context, in input.GetImageInput) (*output.GetImageOutput, error) {
 	const op = "image.UseCase.Get"
 	logFields := logger.WithFields("operation", op, "image_id", in.ImageID)
@@ -236,7 +570,7 @@ func (uc *UseCase) GetStatus(ctx context.Context, in input.GetImageStatusInput)
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	metadata, err := uc.repo.Get(ctx, imageID)
+	metadata, err := uc.repo.GetWithProcessedData(ctx, imageID)
 	if err != nil {
 		uc.log.Error("Failed to get image metadata", logFields("error", err)...)
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -244,7 +578,217 @@ func (uc *UseCase) GetStatus(ctx context.Context, in input.GetImageStatusInput)
 
 	uc.log.Info("Successfully get image status", logFields()...)
 
-	return &output.GetImageStatusOutput{Status: metadata.Status.String()}, nil
+	out := &output.GetImageStatusOutput{Status: metadata.Status.String()}
+	if metadata.ProcessedData != nil {
+		out.BlurHash = metadata.ProcessedData.BlurHash
+		out.LQIP = metadata.ProcessedData.LQIP
+	}
+	return out, nil
+}
+
+func (uc *UseCase) List(ctx context.Context, in input.ListImagesInput) (*output.ListImagesOutput, error) {
+	const op = "image.UseCase.List"
+	logFields := logger.WithFields("operation", op)
+
+	uc.log.Info("Attempting to list images", logFields(
+		"status", in.Status,
+		"format", in.Format,
+		"color", in.Color,
+	)...)
+
+	params := options.ImageListParams{
+		Limit:  &in.Limit,
+		Offset: &in.Offset,
+	}
+	if in.Status != "" {
+		status := vo.NewStatus(in.Status)
+		if status == vo.StatusUnknown {
+			uc.log.Error("Invalid status filter", logFields("status", in.Status)...)
+			return nil, fmt.Errorf("%s: invalid status: %s", op, in.Status)
+		}
+		params.Status = &status
+	}
+	if in.Format != "" {
+		params.Format = &in.Format
+	}
+	if in.Color != "" {
+		params.Color = &in.Color
+	}
+
+	images, err := uc.repo.List(ctx, params)
+	if err != nil {
+		uc.log.Error("Failed to list images", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully listed images", logFields("count", len(images))...)
+
+	return &output.ListImagesOutput{Images: images}, nil
+}
+
+func (uc *UseCase) FindSimilar(ctx context.Context, in input.FindSimilarImagesInput) (*output.FindSimilarImagesOutput, error) {
+	const op = "image.UseCase.FindSimilar"
+	logFields := logger.WithFields("operation", op, "image_id", in.ImageID)
+
+	uc.log.Info("Attempting to find similar images", logFields("max_distance", in.MaxDistance)...)
+
+	imageID, err := uuid.Parse(in.ImageID)
+	if err != nil {
+		uc.log.Error("Failed to parse image UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	image, err := uc.repo.Get(ctx, imageID)
+	if err != nil {
+		uc.log.Error("Image not found", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: image not found: %w", op, err)
+	}
+
+	// the hash is computed by the worker, so unprocessed images have nothing to compare
+	if image.PHash == nil {
+		uc.log.Info("Image has no perceptual hash", logFields()...)
+		return &output.FindSimilarImagesOutput{Images: []model.SimilarImage{}}, nil
+	}
+
+	similar, err := uc.repo.ListSimilar(ctx, options.SimilarImagesParams{
+		ImageID:     image.ID,
+		PHash:       *image.PHash,
+		MaxDistance: in.MaxDistance,
+		Limit:       in.Limit,
+	})
+	if err != nil {
+		uc.log.Error("Failed to list similar images", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully found similar images", logFields("count", len(similar))...)
+
+	return &output.FindSimilarImagesOutput{Images: similar}, nil
+}
+
+func (uc *UseCase) Compare(ctx context.Context, in input.CompareImagesInput) (*output.CompareImagesOutput, error) {
+	const op = "image.UseCase.Compare"
+	logFields := logger.WithFields(
+		"operation", op,
+		"image_id", in.ImageID,
+		"variant", in.Variant.String(),
+		"other_image_id", in.OtherImageID,
+		"other_variant", in.OtherVariant.String(),
+	)
+
+	uc.log.Info("Attempting to compare images", logFields()...)
+
+	data, err := uc.getVariant(ctx, in.ImageID, in.Variant)
+	if err != nil {
+		uc.log.Error("Failed to get image", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	otherData, err := uc.getVariant(ctx, in.OtherImageID, in.OtherVariant)
+	if err != nil {
+		uc.log.Error("Failed to get other image", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result, err := uc.processor.Compare(data, otherData, in.Diff)
+	if err != nil {
+		uc.log.Error("Failed to compare images", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: compare: %w", op, err)
+	}
+
+	uc.log.Info("Successfully compared images", logFields(
+		"psnr", result.PSNR,
+		"ssim", result.SSIM,
+		"hash_distance", result.HashDistance,
+	)...)
+
+	return &output.CompareImagesOutput{
+		PSNR:         result.PSNR,
+		SSIM:         result.SSIM,
+		HashDistance: result.HashDistance,
+		DiffImage:    result.DiffImage,
+	}, nil
+}
+
+func (uc *UseCase) GetHistogram(ctx context.Context, in input.GetImageHistogramInput) (*output.GetImageHistogramOutput, error) {
+	const op = "image.UseCase.GetHistogram"
+	logFields := logger.WithFields("operation", op, "image_id", in.ImageID, "variant", in.Variant.String())
+
+	uc.log.Info("Attempting to get image histogram", logFields()...)
+
+	data, err := uc.getVariant(ctx, in.ImageID, in.Variant)
+	if err != nil {
+		uc.log.Error("Failed to get image", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats, err := uc.processor.Histogram(data)
+	if err != nil {
+		uc.log.Error("Failed to compute histogram", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: histogram: %w", op, err)
+	}
+
+	uc.log.Info("Successfully computed image histogram", logFields("exposure", stats.ExposureLabel)...)
+
+	return &output.GetImageHistogramOutput{
+		ImageID:    in.ImageID,
+		Variant:    in.Variant.String(),
+		Statistics: stats,
+	}, nil
+}
+
+func (uc *UseCase) getVariant(ctx context.Context, rawImageID string, variant vo.Variant) ([]byte, error) {
+	imageID, err := uuid.Parse(rawImageID)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := uc.repo.Get(ctx, imageID)
+	if err != nil {
+		return nil, fmt.Errorf("image %s not found: %w", imageID, err)
+	}
+
+	if variant == vo.VariantOriginal {
+		data, err := uc.s3.GetOriginal(ctx, image.ID.String())
+		if err != nil {
+			return nil, fmt.Errorf("get original %s: %w", imageID, err)
+		}
+		return data, nil
+	}
+
+	if image.Status != vo.StatusCompleted {
+		return nil, fmt.Errorf("image %s is not processed yet: %s", imageID, image.Status)
+	}
+	data, err := uc.s3.Get(ctx, image.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("get processed %s: %w", imageID, err)
+	}
+	return data, nil
+}
+
+// getSource returns the processed variant of an image used to compose another
+// one when it is ready and the original one otherwise.
+func (uc *UseCase) getSource(ctx context.Context, rawImageID string) (*model.ImageMetadata, []byte, error) {
+	imageID, err := uuid.Parse(rawImageID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	source, err := uc.repo.Get(ctx, imageID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("image %s not found: %w", imageID, err)
+	}
+
+	variant := vo.VariantOriginal
+	if source.Status == vo.StatusCompleted {
+		variant = vo.VariantProcessed
+	}
+	data, err := uc.getVariant(ctx, rawImageID, variant)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return source, data, nil
 }
 
 func (uc *UseCase) Delete(ctx context.Context, in input.DeleteImageInput) (*output.DeleteImageOutput, error) {
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/output"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// enqueueWebhook stores the delivery of the final state of the image. It is
+// sent by DeliverWebhooks, so a slow callback never holds up the worker.
+func (uc *UseCase) enqueueWebhook(ctx context.Context, imageID uuid.UUID, callbackURL, reason string) error {
+	metadata, err := uc.repo.GetWithProcessedData(ctx, imageID)
+	if err != nil {
+		return fmt.Errorf("get image: %w", err)
+	}
+
+	payload := model.WebhookPayload{
+		Event:     model.WebhookEventCompleted,
+		ImageID:   imageID.String(),
+		Status:    vo.StatusCompleted.String(),
+		ResultURL: metadata.ResultURL.String(),
+		Timestamp: time.Now(),
+	}
+	if reason != "" {
+		payload.Event = model.WebhookEventFailed
+		payload.Status = vo.StatusFailed.String()
+		payload.ResultURL = ""
+		payload.Error = reason
+	} else if metadata.ProcessedData != nil {
+		payload.Width = metadata.ProcessedData.Width
+		payload.Height = metadata.ProcessedData.Height
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	if _, err = uc.webhooks.Save(ctx, options.WebhookDeliveryCreateParams{
+		ID:        uuid.New(),
+		ImageID:   imageID,
+		URL:       callbackURL,
+		Event:     payload.Event,
+		Payload:   body,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("save delivery: %w", err)
+	}
+
+	return nil
+}
+
+// DeliverWebhooks sends the due deliveries and returns how many were
+// attempted. Failed deliveries are retried with exponential backoff until
+// options.WebhookMaxAttempts is reached.
+func (uc *UseCase) DeliverWebhooks(ctx context.Context) (int, error) {
+	const op = "image.UseCase.DeliverWebhooks"
+
+	now := time.Now()
+	deliveries, err := uc.webhooks.ClaimDue(ctx, options.WebhookClaimParams{
+		Now:        now,
+		LeaseUntil: now.Add(options.WebhookLease),
+		Limit:      options.WebhookBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: claim deliveries: %w", op, err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *model.WebhookDelivery) {
+			defer wg.Done()
+			uc.deliverWebhook(ctx, delivery)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (uc *UseCase) deliverWebhook(ctx context.Context, delivery *model.WebhookDelivery) {
+	const op = "image.UseCase.deliverWebhook"
+	logFields := logger.WithFields(
+		"operation", op,
+		"delivery_id", delivery.ID.String(),
+		"image_id", delivery.ImageID.String(),
+	)
+
+	startedAt := time.Now()
+	statusCode, sendErr := uc.sender.Send(ctx, delivery)
+	if ctx.Err() != nil {
+		// the delivery is claimed again when the lease expires
+		return
+	}
+	now := time.Now()
+
+	attempt := delivery.Attempts + 1
+	result := options.WebhookDeliveryResultParams{
+		ID:            delivery.ID,
+		Status:        vo.DeliveryStatusDelivered,
+		Attempts:      attempt,
+		NextAttemptAt: now,
+		UpdatedAt:     now,
+	}
+	var attemptErr string
+	switch {
+	case sendErr == nil:
+		result.DeliveredAt = &now
+	case attempt >= options.WebhookMaxAttempts:
+		attemptErr = sendErr.Error()
+		result.Status = vo.DeliveryStatusFailed
+		result.LastError = attemptErr
+	default:
+		attemptErr = sendErr.Error()
+		result.Status = vo.DeliveryStatusPending
+		result.LastError = attemptErr
+		result.NextAttemptAt = now.Add(webhookBackoff(attempt))
+	}
+
+	if err := uc.txManager.WithTransaction(ctx, nil, func(ctx context.Context) error {
+		if err := uc.webhooks.SaveAttempt(ctx, options.WebhookAttemptCreateParams{
+			DeliveryID:  delivery.ID,
+			Attempt:     attempt,
+			StatusCode:  statusCode,
+			Error:       attemptErr,
+			Duration:    now.Sub(startedAt),
+			AttemptedAt: startedAt,
+		}); err != nil {
+			return fmt.Errorf("save attempt: %w", err)
+		}
+		return uc.webhooks.UpdateResult(ctx, result)
+	}); err != nil {
+		uc.log.Error("Failed to record webhook attempt", logFields("error", err)...)
+		return
+	}
+
+	if sendErr != nil {
+		uc.log.Error("Webhook delivery attempt failed", logFields(
+			"error", sendErr,
+			"attempt", attempt,
+			"status", result.Status.String(),
+		)...)
+		return
+	}
+	uc.log.Info("Webhook delivered", logFields("attempt", attempt, "status_code", statusCode)...)
+}
+
+// webhookBackoff doubles the delay after every failed attempt.
+func webhookBackoff(attempt int) time.Duration {
+	backoff := options.WebhookBaseBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > options.WebhookMaxBackoff {
+		return options.WebhookMaxBackoff
+	}
+	return backoff
+}
+
+func (uc *UseCase) ListWebhookDeliveries(ctx context.Context, in input.ListWebhookDeliveriesInput) (*output.ListWebhookDeliveriesOutput, error) {
+	const op = "image.UseCase.ListWebhookDeliveries"
+	logFields := logger.WithFields("operation", op, "image_id", in.ImageID)
+
+	uc.log.Info("Attempting to list webhook deliveries", logFields()...)
+
+	imageID, err := uuid.Parse(in.ImageID)
+	if err != nil {
+		uc.log.Error("Failed to parse image UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = uc.repo.Get(ctx, imageID); err != nil {
+		uc.log.Error("Image not found", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: image not found: %w", op, err)
+	}
+
+	deliveries, err := uc.webhooks.ListByImage(ctx, imageID)
+	if err != nil {
+		uc.log.Error("Failed to list webhook deliveries", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully listed webhook deliveries", logFields("count", len(deliveries))...)
+
+	return &output.ListWebhookDeliveriesOutput{Deliveries: deliveries}, nil
+}
+
+// RedeliverWebhook enqueues a copy of a delivery with a fresh attempt
+// budget. The original delivery is kept as it is, so its attempts stay in
+// the log.
+func (uc *UseCase) RedeliverWebhook(ctx context.Context, in input.RedeliverWebhookInput) (*output.RedeliverWebhookOutput, error) {
+	const op = "image.UseCase.RedeliverWebhook"
+	logFields := logger.WithFields("operation", op, "delivery_id", in.DeliveryID)
+
+	uc.log.Info("Attempting to redeliver webhook", logFields()...)
+
+	deliveryID, err := uuid.Parse(in.DeliveryID)
+	if err != nil {
+		uc.log.Error("Failed to parse delivery UUID", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	original, err := uc.webhooks.Get(ctx, deliveryID)
+	if err != nil {
+		uc.log.Error("Webhook delivery not found", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: delivery not found: %w", op, err)
+	}
+
+	delivery, err := uc.webhooks.Save(ctx, options.WebhookDeliveryCreateParams{
+		ID:        uuid.New(),
+		ImageID:   original.ImageID,
+		URL:       original.URL,
+		Event:     original.Event,
+		Payload:   original.Payload,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		uc.log.Error("Failed to save webhook delivery", logFields("error", err)...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.Info("Successfully enqueued webhook redelivery", logFields("new_delivery_id", delivery.ID.String())...)
+
+	return &output.RedeliverWebhookOutput{Delivery: delivery}, nil
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/google/uuid"
+)
+
+// Batch groups the images uploaded with a single request. It is completed
+// once all of its items are stored and none of them is still processed.
+type Batch struct {
+	ID          uuid.UUID
+	Total       int
+	Items       []BatchItem
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// BatchItem is a file of a batch. An item rejected on upload has no image
+// and is failed with the validation error.
+type BatchItem struct {
+	Index    int
+	Filename string
+	ImageID  *uuid.UUID
+	Status   vo.Status
+	Error    string
+}
+
+func (b *Batch) Counts() map[vo.Status]int {
+	counts := map[vo.Status]int{
+		vo.StatusUploaded:   0,
+		vo.StatusProcessing: 0,
+		vo.StatusCompleted:  0,
+		vo.StatusFailed:     0,
+		vo.StatusCancelled:  0,
+	}
+	for _, item := range b.Items {
+		counts[item.Status]++
+	}
+	return counts
+}
+
+func (b *Batch) Failures() []BatchItem {
+	var failures []BatchItem
+	for _, item := range b.Items {
+		if item.Status == vo.StatusFailed {
+			failures = append(failures, item)
+		}
+	}
+	return failures
+}
+
+// Progress is the percentage of the finished items.
+func (b *Batch) Progress() int {
+	if b.Total == 0 {
+		return 100
+	}
+	counts := b.Counts()
+	return (counts[vo.StatusCompleted] + counts[vo.StatusFailed] + counts[vo.StatusCancelled]) * 100 / b.Total
+}
+
+// Status is cancelled when every item is cancelled and failed when none of
+// the items that were not cancelled is completed.
+func (b *Batch) Status() vo.Status {
+	if b.CompletedAt == nil {
+		return vo.StatusProcessing
+	}
+
+	counts := b.Counts()
+	switch cancelled := counts[vo.StatusCancelled]; {
+	case cancelled == b.Total:
+		return vo.StatusCancelled
+	case counts[vo.StatusFailed] == b.Total-cancelled:
+		return vo.StatusFailed
+	default:
+		return vo.StatusCompleted
+	}
+}
+
+// IsSettled reports whether all items are stored and finished, even if the
+// batch is not marked completed yet.
+func (b *Batch) IsSettled() bool {
+	return len(b.Items) == b.Total && b.Progress() == 100
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+)
+
+func TestBatchStatus(t *testing.T) {
+	completedAt := time.Now()
+
+	tests := []struct {
+		name     string
+		statuses []vo.Status
+		want     vo.Status
+	}{
+		{"all cancelled", []vo.Status{vo.StatusCancelled, vo.StatusCancelled}, vo.StatusCancelled},
+		{"failed and cancelled", []vo.Status{vo.StatusFailed, vo.StatusCancelled}, vo.StatusFailed},
+		{"all failed", []vo.Status{vo.StatusFailed, vo.StatusFailed}, vo.StatusFailed},
+		{"completed and cancelled", []vo.Status{vo.StatusCompleted, vo.StatusCancelled}, vo.StatusCompleted},
+		{"completed and failed", []vo.Status{vo.StatusCompleted, vo.StatusFailed}, vo.StatusCompleted},
+	}
+	for _, tt := range tests {
+		batch := &Batch{Total: len(tt.statuses), CompletedAt: &completedAt}
+		for i, status := range tt.statuses {
+			batch.Items = append(batch.Items, BatchItem{Index: i, Status: status})
+		}
+
+		if got := batch.Status(); got != tt.want {
+			t.Errorf("%s: status = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBatchStatusNotCompleted(t *testing.T) {
+	batch := &Batch{Total: 1, Items: []BatchItem{{Status: vo.StatusCancelled}}}
+	if got := batch.Status(); got != vo.StatusProcessing {
+		t.Errorf("status = %s, want %s", got, vo.StatusProcessing)
+	}
+}
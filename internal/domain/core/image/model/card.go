@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CardTemplate struct {
+	ID         uuid.UUID       `json:"id"`
+	Name       string          `json:"name"`
+	Width      int             `json:"width"`
+	Height     int             `json:"height"`
+	Background string          `json:"background"` // "#RRGGBB" or "#RRGGBBAA"
+	ImageSlots []CardImageSlot `json:"image_slots"`
+	TextSlots  []CardTextSlot  `json:"text_slots"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// CardImageSlot is a rectangle filled with one of the uploaded images.
+type CardImageSlot struct {
+	Name         string `json:"name"`
+	X            int    `json:"x"`
+	Y            int    `json:"y"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Fit          string `json:"fit,omitempty"` // "cover" (default), "contain"
+	CornerRadius int    `json:"corner_radius,omitempty"`
+}
+
+// CardTextSlot is a text box: lines are wrapped at Width and cut at MaxLines.
+type CardTextSlot struct {
+	Name       string  `json:"name"`
+	X          int     `json:"x"`
+	Y          int     `json:"y"`
+	Width      int     `json:"width"`
+	MaxLines   int     `json:"max_lines,omitempty"`
+	Font       string  `json:"font,omitempty"` // "regular" (default), "bold", "italic", "mono"
+	FontSize   float64 `json:"font_size"`
+	LineHeight float64 `json:"line_height,omitempty"` // multiplier of the font size
+	Color      string  `json:"color,omitempty"`
+	Align      string  `json:"align,omitempty"` // "left" (default), "center", "right"
+	Default    string  `json:"default,omitempty"`
+}
+
+// CardRenderSpec fills the slots of a template by their names.
+type CardRenderSpec struct {
+	TemplateID string            `json:"template_id"`
+	Images     map[string]string `json:"images,omitempty"` // slot name -> image ID
+	Texts      map[string]string `json:"texts,omitempty"`
+	Format     string            `json:"format,omitempty"`
+	Quality    int               `json:"quality,omitempty"`
+}
+
+type CardContent struct {
+	Images map[string][]byte
+	Texts  map[string]string
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetter is a task that could not be processed within the retry budget.
+// Topic, Partition and Offset point to the original message.
+type DeadLetter struct {
+	ID         uuid.UUID  `json:"id"`
+	Topic      string     `json:"topic"`
+	Partition  int        `json:"partition"`
+	Offset     int64      `json:"offset"`
+	Key        string     `json:"key"`
+	Payload    string     `json:"payload"`
+	Error      string     `json:"error"`
+	Attempts   int        `json:"attempts"`
+	FailedAt   time.Time  `json:"failed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReplayedAt *time.Time `json:"replayed_at,omitempty"`
+}
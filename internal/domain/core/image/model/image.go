@@ -8,10 +8,11 @@ import (
 )
 
 type FileInfo struct {
-	Path     string `json:"path"`
-	Size     int64  `json:"size"`
-	MimeType string `json:"mime_type"`
-	ETag     string `json:"etag"`
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	MimeType   string    `json:"mime_type"`
+	ETag       string    `json:"etag"`
+	ModifiedAt time.Time `json:"modified_at"` // set by listings only
 }
 
 type ImageMetadata struct {
@@ -20,21 +21,36 @@ type ImageMetadata struct {
 	Format        string         `json:"format"`
 	Size          int64          `json:"size"`
 	FileName      vo.Filename    `json:"file_name"`
-	Status        vo.Status      `json:"status"` // "uploaded", "processing", "completed", "failed"
+	Status        vo.Status      `json:"status"` // "uploaded", "processing", "completed", "failed", "cancelled"
 	ResultURL     vo.ResultUrl   `json:"result_url,omitempty"`
 	UploadedAt    time.Time      `json:"uploaded_at"`
+	SHA256        string         `json:"sha256,omitempty"`
+	PHash         *uint64        `json:"phash,string,omitempty"`
 	ProcessedData *ProcessedData `json:"processed_data"`
 }
 
+type SimilarImage struct {
+	Image    ImageMetadata `json:"image"`
+	Distance int           `json:"distance"`
+}
+
 type ProcessedData struct {
 	Width         int       `json:"width"`
 	Height        int       `json:"height"`
 	ProcessedName string    `json:"processed_name"`
+	BlurHash      string    `json:"blur_hash,omitempty"`
+	LQIP          string    `json:"lqip,omitempty"`
+	DominantColor string    `json:"dominant_color,omitempty"`
+	ColorName     string    `json:"color_name,omitempty"`
+	Palette       []string  `json:"palette,omitempty"`
 	ProcessedAt   time.Time `json:"processed_at"`
 }
 
 type ProcessingImage struct {
-	ImageID   string            `json:"image_id"`
-	Options   ProcessingOptions `json:"options"`
-	Timestamp time.Time         `json:"timestamp"`
+	ImageID      string            `json:"image_id"`
+	Options      ProcessingOptions `json:"options"`
+	ContactSheet *ContactSheetSpec `json:"contact_sheet,omitempty"`
+	Card         *CardRenderSpec   `json:"card,omitempty"`
+	CallbackURL  string            `json:"callback_url,omitempty"`
+	Timestamp    time.Time         `json:"timestamp"`
 }
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxMessage is a processing task saved in the transaction that creates
+// the image. It is published to the queue by the outbox relay, or given up
+// once it runs out of attempts. Task is nil if the payload cannot be decoded.
+type OutboxMessage struct {
+	ID        uuid.UUID
+	Task      *ProcessingImage
+	Attempts  int
+	CreatedAt time.Time
+	SentAt    *time.Time
+	FailedAt  *time.Time
+}
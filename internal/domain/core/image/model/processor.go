@@ -3,12 +3,26 @@ package model
 import "time"
 
 type ProcessingOptions struct {
-	Width         int    `json:"width,omitempty"`
-	Height        int    `json:"height,omitempty"`
-	Quality       int    `json:"quality,omitempty"`
-	Format        string `json:"format,omitempty"`
-	Thumbnail     bool   `json:"thumbnail,omitempty"`
-	WatermarkText string `json:"watermark_text,omitempty"`
+	Width            int    `json:"width,omitempty"`
+	Height           int    `json:"height,omitempty"`
+	Quality          int    `json:"quality,omitempty"`
+	Format           string `json:"format,omitempty"`
+	Thumbnail        bool   `json:"thumbnail,omitempty"`
+	WatermarkText    string `json:"watermark_text,omitempty"`
+	CornerRadius     int    `json:"corner_radius,omitempty"`
+	Mask             string `json:"mask,omitempty"` // "circle", "ellipse"
+	BorderWidth      int    `json:"border_width,omitempty"`
+	BorderColor      string `json:"border_color,omitempty"` // "#RRGGBB" or "#RRGGBBAA"
+	AutoLevel        bool   `json:"auto_level,omitempty"`
+	AutoWhiteBalance bool   `json:"auto_white_balance,omitempty"`
+	MaxBytes         int    `json:"max_bytes,omitempty"`
+}
+
+// ImageInfo is what is known about an image from its header.
+type ImageInfo struct {
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 
 type ProcessingResult struct {
@@ -17,5 +31,58 @@ type ProcessingResult struct {
 	Width          int
 	Height         int
 	Size           int64
+	Quality        int // JPEG quality the data was encoded with
+	BlurHash       string
+	LQIP           string
+	DominantColor  string
+	Palette        []string
 	ProcessingTime time.Duration
 }
+
+type ContactSheetSpec struct {
+	ImageIDs   []string `json:"image_ids"`
+	Columns    int      `json:"columns"`
+	CellWidth  int      `json:"cell_width"`
+	CellHeight int      `json:"cell_height"`
+	Spacing    int      `json:"spacing,omitempty"`
+	Background string   `json:"background,omitempty"` // "#RRGGBB" or "#RRGGBBAA"
+	Captions   bool     `json:"captions,omitempty"`
+	Format     string   `json:"format,omitempty"`
+	Quality    int      `json:"quality,omitempty"`
+}
+
+type ContactSheetCell struct {
+	ImageData []byte
+	Caption   string
+}
+
+type ComparisonResult struct {
+	PSNR         float64
+	SSIM         float64
+	HashDistance int
+	DiffImage    []byte
+}
+
+const (
+	ExposureUnder  = "underexposed"
+	ExposureNormal = "normal"
+	ExposureOver   = "overexposed"
+)
+
+type ChannelStatistics struct {
+	Histogram         []int   `json:"histogram"`
+	Mean              float64 `json:"mean"`
+	StdDev            float64 `json:"stddev"`
+	ClippedShadows    float64 `json:"clipped_shadows"`    // percent of pixels at 0
+	ClippedHighlights float64 `json:"clipped_highlights"` // percent of pixels at 255
+}
+
+type ImageStatistics struct {
+	Pixels        int               `json:"pixels"`
+	Red           ChannelStatistics `json:"red"`
+	Green         ChannelStatistics `json:"green"`
+	Blue          ChannelStatistics `json:"blue"`
+	Luminance     ChannelStatistics `json:"luminance"`
+	Exposure      float64           `json:"exposure"` // EV offset from middle gray
+	ExposureLabel string            `json:"exposure_label"`
+}
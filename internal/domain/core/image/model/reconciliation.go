@@ -0,0 +1,11 @@
+package model
+
+import "github.com/google/uuid"
+
+// StuckImage is an image that has been processing for longer than a task
+// should take, its task may have been lost.
+type StuckImage struct {
+	ImageID          uuid.UUID
+	Requeues         int  // times its task was enqueued again
+	HasProcessedData bool // the processed data is saved in the database
+}
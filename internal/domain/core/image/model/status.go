@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// StatusEvent is a status transition of an image. The first event of a
+// subscription carries the current status and no previous one.
+type StatusEvent struct {
+	ImageID        string    `json:"image_id"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
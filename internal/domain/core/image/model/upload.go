@@ -0,0 +1,55 @@
+package model
+
+import (
+	"io"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/google/uuid"
+)
+
+// UploadSession is an upload of an original that is not sent with a single
+// request: either resumable, assembled with a multipart upload, or put by the
+// client directly to the storage with a presigned URL. The original is stored
+// under the key of the image the session becomes, so the session ID is also
+// the ID of that image.
+type UploadSession struct {
+	ID              uuid.UUID
+	Method          vo.UploadMethod
+	Filename        string
+	Size            int64 // declared length of the whole file
+	Offset          int64 // bytes received so far
+	Options         ProcessingOptions
+	DuplicatePolicy vo.DuplicatePolicy
+	S3UploadID      string // multipart upload of a chunked session
+	Parts           []UploadPart
+	Pending         []byte     // received bytes not yet uploaded as a part
+	HashState       []byte     // SHA256 state of the first Offset bytes
+	ImageID         *uuid.UUID // set once the upload is completed
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ExpiresAt       time.Time
+}
+
+func (s *UploadSession) IsCompleted() bool {
+	return s.ImageID != nil
+}
+
+func (s *UploadSession) IsExpired(now time.Time) bool {
+	return !s.IsCompleted() && now.After(s.ExpiresAt)
+}
+
+type UploadPart struct {
+	Number int    `json:"number"`
+	ETag   string `json:"etag"`
+	Size   int64  `json:"size"`
+}
+
+// RemoteFile is a file downloaded from a URL. Size is -1 when the server
+// does not report the length.
+type RemoteFile struct {
+	Body        io.ReadCloser
+	Size        int64
+	Filename    string
+	ContentType string
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookEventCompleted = "image.completed"
+	WebhookEventFailed    = "image.failed"
+)
+
+// WebhookPayload is the body posted to the callback URL of an image.
+type WebhookPayload struct {
+	Event     string    `json:"event"`
+	ImageID   string    `json:"image_id"`
+	Status    string    `json:"status"`
+	ResultURL string    `json:"result_url,omitempty"`
+	Width     int       `json:"width,omitempty"`
+	Height    int       `json:"height,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookDelivery is a payload to be posted to a callback URL. It is
+// retried until the callback answers with 2xx or the attempts run out.
+type WebhookDelivery struct {
+	ID            uuid.UUID         `json:"id"`
+	ImageID       uuid.UUID         `json:"image_id"`
+	URL           string            `json:"url"`
+	Event         string            `json:"event"`
+	Payload       json.RawMessage   `json:"payload"`
+	Status        vo.DeliveryStatus `json:"status"`
+	Attempts      int               `json:"attempts"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	LastError     string            `json:"last_error,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	DeliveredAt   *time.Time        `json:"delivered_at,omitempty"`
+	AttemptLog    []WebhookAttempt  `json:"attempt_log,omitempty"`
+}
+
+type WebhookAttempt struct {
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMS  int64     `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
@@ -0,0 +1,11 @@
+package options
+
+import "time"
+
+// ConsumerQueueSize is how many messages may wait for a busy worker before
+// the consumer stops fetching
+const ConsumerQueueSize = 4
+
+// ConsumerHandoverTimeout bounds a commit or a requeue made while the
+// consumer is stopping
+const ConsumerHandoverTimeout = 5 * time.Second
@@ -0,0 +1,19 @@
+package options
+
+import "time"
+
+const (
+	OutboxPollInterval = time.Second
+	OutboxBatchSize    = 100
+	OutboxBaseBackoff  = time.Second
+	OutboxMaxBackoff   = time.Minute
+	// OutboxMaxAttempts is how many times a message is published before it
+	// is given up
+	OutboxMaxAttempts = 20
+	// OutboxLease is how long a claimed message is hidden from the other
+	// relays while it is published
+	OutboxLease = time.Minute
+	// OutboxRetention is how long sent messages are kept for troubleshooting
+	OutboxRetention       = 24 * time.Hour
+	OutboxCleanupInterval = time.Hour
+)
@@ -0,0 +1,5 @@
+package options
+
+// ReconcileBatchSize is how many stuck images are claimed, or originals are
+// looked up, at once
+const ReconcileBatchSize = 100
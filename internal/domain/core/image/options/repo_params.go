@@ -3,6 +3,7 @@ package options
 import (
 	"time"
 
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
 	"github.com/google/uuid"
 )
@@ -10,6 +11,7 @@ import (
 type ImageListParams struct {
 	Status   *vo.Status
 	Format   *string
+	Color    *string
 	FromDate *time.Time
 	ToDate   *time.Time
 	Limit    *int32
@@ -21,11 +23,21 @@ type ImageUpdateParams struct {
 	Status  vo.Status
 }
 
+type ImagePHashUpdateParams struct {
+	ImageID uuid.UUID
+	PHash   uint64
+}
+
 type ProcessedImageCreateParams struct {
 	ImageID       uuid.UUID
 	Width         int
 	Height        int
 	ProcessedName string
+	BlurHash      string
+	LQIP          string
+	DominantColor string
+	ColorName     string
+	Palette       []string
 	ProcessedAt   time.Time
 }
 
@@ -50,9 +62,151 @@ type ImageCreateParams struct {
 	Size         int64
 	Format       string
 	UploadedAt   time.Time
+	SHA256       string
+	PHash        *uint64 // computed by the worker when nil
+}
+
+type SimilarImagesParams struct {
+	ImageID     uuid.UUID
+	PHash       uint64
+	MaxDistance int
+	Limit       int32
+}
+
+type CardTemplateCreateParams struct {
+	ID         uuid.UUID
+	Name       string
+	Width      int
+	Height     int
+	Background string
+	ImageSlots []model.CardImageSlot
+	TextSlots  []model.CardTextSlot
+	CreatedAt  time.Time
 }
 
 type RecentProcessedImagesParams struct {
 	Since time.Time
 	Limit int32
 }
+
+type BatchCreateParams struct {
+	ID        uuid.UUID
+	Total     int
+	CreatedAt time.Time
+}
+
+type BatchItemCreateParams struct {
+	BatchID  uuid.UUID
+	Index    int
+	Filename string
+	ImageID  *uuid.UUID
+	Error    string
+}
+
+type BatchItemsErrorParams struct {
+	ImageID uuid.UUID
+	Error   string // empty when the image is processed
+}
+
+type WebhookDeliveryCreateParams struct {
+	ID        uuid.UUID
+	ImageID   uuid.UUID
+	URL       string
+	Event     string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+type WebhookClaimParams struct {
+	Now        time.Time
+	LeaseUntil time.Time
+	Limit      int
+}
+
+type WebhookAttemptCreateParams struct {
+	DeliveryID  uuid.UUID
+	Attempt     int
+	StatusCode  int
+	Error       string
+	Duration    time.Duration
+	AttemptedAt time.Time
+}
+
+type WebhookDeliveryResultParams struct {
+	ID            uuid.UUID
+	Status        vo.DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	UpdatedAt     time.Time
+	DeliveredAt   *time.Time
+}
+
+type OutboxMessageCreateParams struct {
+	ID        uuid.UUID
+	Task      *model.ProcessingImage
+	CreatedAt time.Time
+}
+
+type OutboxClaimParams struct {
+	Now        time.Time
+	LeaseUntil time.Time
+	Limit      int
+}
+
+// StuckImageClaimParams select the images processing for longer than the
+// timeout.
+type StuckImageClaimParams struct {
+	Timeout time.Duration
+	Limit   int
+}
+
+// OutboxFailureParams schedule the next attempt; FailedAt gives the message
+// up.
+type OutboxFailureParams struct {
+	ID            uuid.UUID
+	Error         string
+	NextAttemptAt time.Time
+	FailedAt      *time.Time
+}
+
+type DeadLetterCreateParams struct {
+	ID        uuid.UUID
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       string
+	Payload   string
+	Error     string
+	Attempts  int
+	FailedAt  time.Time
+	CreatedAt time.Time
+}
+
+type UploadSessionCreateParams struct {
+	ID              uuid.UUID
+	Method          vo.UploadMethod
+	Filename        string
+	Size            int64
+	Options         model.ProcessingOptions
+	DuplicatePolicy vo.DuplicatePolicy
+	S3UploadID      string
+	HashState       []byte
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+}
+
+type UploadSessionProgressParams struct {
+	ID        uuid.UUID
+	Offset    int64
+	Parts     []model.UploadPart
+	Pending   []byte
+	HashState []byte
+	UpdatedAt time.Time
+}
+
+type UploadSessionCompleteParams struct {
+	ID        uuid.UUID
+	ImageID   uuid.UUID
+	UpdatedAt time.Time
+}
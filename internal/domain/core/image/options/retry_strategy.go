@@ -12,3 +12,8 @@ var (
 	DefaultStrategy = RetryStrategy{Attempts: 3, Delay: time.Second}
 	BrokerStrategy  = RetryStrategy{Attempts: 5, Delay: 3 * time.Second}
 )
+
+// RetryDelays are the delays of the retry topics. A task that fails with a
+// transient error is moved to the next one, after the last it is
+// dead-lettered.
+var RetryDelays = []time.Duration{10 * time.Second, time.Minute, 10 * time.Minute}
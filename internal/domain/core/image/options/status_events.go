@@ -0,0 +1,13 @@
+package options
+
+import "time"
+
+const (
+	// StatusEventsChannel is the Postgres channel the status changes of
+	// images are notified on
+	StatusEventsChannel = "image_status"
+
+	StatusSubscriptionBuffer = 64
+	StatusEventsHeartbeat    = 15 * time.Second
+	MaxWatchedImages         = 100
+)
@@ -0,0 +1,6 @@
+package options
+
+import "time"
+
+// UploadSessionTTL is how long an unfinished resumable upload can be continued.
+const UploadSessionTTL = 24 * time.Hour
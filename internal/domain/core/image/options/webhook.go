@@ -0,0 +1,14 @@
+package options
+
+import "time"
+
+const (
+	WebhookPollInterval = 2 * time.Second
+	WebhookBatchSize    = 20
+	// WebhookLease is how long a claimed delivery is hidden from the other
+	// workers; it has to outlast a send
+	WebhookLease       = time.Minute
+	WebhookMaxAttempts = 8
+	WebhookBaseBackoff = 10 * time.Second
+	WebhookMaxBackoff  = time.Hour
+)
@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+)
+
+type Fetcher interface {
+	// Fetch downloads the file at the URL. The body fails once more than
+	// maxSize bytes are read.
+	Fetch(ctx context.Context, rawURL string, maxSize int64) (*model.RemoteFile, error)
+}
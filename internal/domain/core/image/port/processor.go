@@ -1,7 +1,17 @@
 package port
 
-import "github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+import (
+	"io"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+)
 
 type ImageProcessor interface {
+	Inspect(reader io.Reader) (*model.ImageInfo, error)
 	ProcessImage(imageData []byte, options model.ProcessingOptions) (*model.ProcessingResult, error)
+	PerceptualHash(imageData []byte) (uint64, error)
+	Compare(imageData, otherImageData []byte, withDiff bool) (*model.ComparisonResult, error)
+	Histogram(imageData []byte) (*model.ImageStatistics, error)
+	ComposeContactSheet(cells []model.ContactSheetCell, spec model.ContactSheetSpec) (*model.ProcessingResult, error)
+	RenderCard(template model.CardTemplate, content model.CardContent, format string, quality int) (*model.ProcessingResult, error)
 }
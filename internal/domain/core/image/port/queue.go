@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"errors"
 
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
 )
@@ -10,9 +11,21 @@ type Queue interface {
 	Publish(ctx context.Context, task *model.ProcessingImage) error
 }
 
+// ErrPermanent marks a processing error that retrying cannot fix, e.g. an
+// image that cannot be decoded. Such a task is dead-lettered at once.
+var ErrPermanent = errors.New("permanent failure")
+
 type Consumer interface {
 	StartProcessing(
 		ctx context.Context,
 		processor func(context.Context, *model.ProcessingImage) error,
 	) error
+	Stop(ctx context.Context) error
+}
+
+type DeadLetterConsumer interface {
+	StartConsuming(
+		ctx context.Context,
+		handler func(context.Context, *model.DeadLetter) error,
+	) error
 }
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
@@ -12,8 +13,90 @@ type Repository interface {
 	Save(ctx context.Context, params options.ImageCreateParams) (*model.ImageMetadata, error)
 	SaveProcessed(ctx context.Context, p options.ProcessedImageCreateParams) error
 	UpdateStatus(ctx context.Context, p options.ImageUpdateParams) error
+	UpdatePHash(ctx context.Context, p options.ImagePHashUpdateParams) error
 	Get(ctx context.Context, imageID uuid.UUID) (*model.ImageMetadata, error)
+	// GetForUpdate locks the image until the end of the transaction.
+	GetForUpdate(ctx context.Context, imageID uuid.UUID) (*model.ImageMetadata, error)
 	GetWithProcessedData(ctx context.Context, imageID uuid.UUID) (*model.ImageMetadata, error)
+	List(ctx context.Context, p options.ImageListParams) ([]model.ImageMetadata, error)
+	FindBySHA256(ctx context.Context, sha256 string) ([]model.ImageMetadata, error)
+	ListSimilar(ctx context.Context, p options.SimilarImagesParams) ([]model.SimilarImage, error)
 	Delete(ctx context.Context, imageID uuid.UUID) error
 	DeleteProcessed(ctx context.Context, imageID uuid.UUID) error
+	// ListExistingIDs returns the IDs that belong to an image.
+	ListExistingIDs(ctx context.Context, imageIDs []uuid.UUID) ([]uuid.UUID, error)
+	// ClaimStuck returns the images processing for longer than the timeout
+	// and restarts their timeout, so that other instances skip them.
+	ClaimStuck(ctx context.Context, p options.StuckImageClaimParams) ([]model.StuckImage, error)
+	// MarkRequeued counts a requeue of the task; it reports false if the
+	// image is no longer processing.
+	MarkRequeued(ctx context.Context, imageID uuid.UUID) (bool, error)
+	// Cancel marks a pending or processing image cancelled; it reports false
+	// if the image is already finished.
+	Cancel(ctx context.Context, imageID uuid.UUID) (bool, error)
+}
+
+type CardTemplateRepository interface {
+	Save(ctx context.Context, p options.CardTemplateCreateParams) (*model.CardTemplate, error)
+	Get(ctx context.Context, templateID uuid.UUID) (*model.CardTemplate, error)
+	List(ctx context.Context, p options.PaginationParams) ([]model.CardTemplate, error)
+	Delete(ctx context.Context, templateID uuid.UUID) error
+}
+
+type BatchRepository interface {
+	Save(ctx context.Context, p options.BatchCreateParams) error
+	SaveItem(ctx context.Context, p options.BatchItemCreateParams) error
+	Get(ctx context.Context, batchID uuid.UUID) (*model.Batch, error)
+	UpdateItemsError(ctx context.Context, p options.BatchItemsErrorParams) error
+	// Refresh marks the batch completed once all of its items are finished.
+	Refresh(ctx context.Context, batchID uuid.UUID, now time.Time) error
+	// RefreshByImage refreshes the batches the image belongs to.
+	RefreshByImage(ctx context.Context, imageID uuid.UUID, now time.Time) error
+}
+
+type WebhookRepository interface {
+	Save(ctx context.Context, p options.WebhookDeliveryCreateParams) (*model.WebhookDelivery, error)
+	Get(ctx context.Context, deliveryID uuid.UUID) (*model.WebhookDelivery, error)
+	// ListByImage returns the deliveries of the image with their attempts.
+	ListByImage(ctx context.Context, imageID uuid.UUID) ([]model.WebhookDelivery, error)
+	// ClaimDue leases the due deliveries, so that other workers skip them
+	// until the lease expires.
+	ClaimDue(ctx context.Context, p options.WebhookClaimParams) ([]model.WebhookDelivery, error)
+	SaveAttempt(ctx context.Context, p options.WebhookAttemptCreateParams) error
+	UpdateResult(ctx context.Context, p options.WebhookDeliveryResultParams) error
+}
+
+type OutboxRepository interface {
+	Save(ctx context.Context, p options.OutboxMessageCreateParams) error
+	// ClaimPending leases the due messages, so that other relays skip them
+	// until the lease expires. Only the earliest unsent message of an image
+	// is claimed, so that its tasks are published in order. A message that
+	// cannot be decoded is returned without a task.
+	ClaimPending(ctx context.Context, p options.OutboxClaimParams) ([]model.OutboxMessage, error)
+	MarkSent(ctx context.Context, messageID uuid.UUID, sentAt time.Time) error
+	MarkFailed(ctx context.Context, p options.OutboxFailureParams) error
+	DeleteSent(ctx context.Context, before time.Time) (int64, error)
+	// FindLatest returns the latest task of the image, or nil if there is
+	// none left.
+	FindLatest(ctx context.Context, imageID uuid.UUID) (*model.OutboxMessage, error)
+}
+
+type DeadLetterRepository interface {
+	// Save reports false for a letter that is already stored, as the
+	// dead-letter topic may deliver it more than once.
+	Save(ctx context.Context, p options.DeadLetterCreateParams) (bool, error)
+	Get(ctx context.Context, letterID uuid.UUID) (*model.DeadLetter, error)
+	List(ctx context.Context, p options.PaginationParams) ([]model.DeadLetter, error)
+	// MarkReplayed reports false if the letter has already been replayed.
+	MarkReplayed(ctx context.Context, letterID uuid.UUID, replayedAt time.Time) (bool, error)
+}
+
+type UploadSessionRepository interface {
+	Save(ctx context.Context, p options.UploadSessionCreateParams) (*model.UploadSession, error)
+	Get(ctx context.Context, sessionID uuid.UUID) (*model.UploadSession, error)
+	// GetForUpdate locks the session until the end of the transaction.
+	GetForUpdate(ctx context.Context, sessionID uuid.UUID) (*model.UploadSession, error)
+	UpdateProgress(ctx context.Context, p options.UploadSessionProgressParams) error
+	Complete(ctx context.Context, p options.UploadSessionCompleteParams) error
+	Delete(ctx context.Context, sessionID uuid.UUID) error
 }
@@ -2,13 +2,25 @@ package port
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
 )
 
 type S3Repository interface {
 	Save(ctx context.Context, data []byte, filename string) (*model.FileInfo, error)
-	SaveOriginal(ctx context.Context, data []byte, filename string) (*model.FileInfo, error)
+	// SaveOriginal streams the reader to the storage. Size is -1 when unknown.
+	SaveOriginal(ctx context.Context, reader io.Reader, size int64, filename string) (*model.FileInfo, error)
+	// CreateOriginalUpload starts a multipart upload of an original.
+	CreateOriginalUpload(ctx context.Context, filename string) (string, error)
+	// PutOriginalPart uploads a part; all but the last must be at least 5MiB.
+	PutOriginalPart(ctx context.Context, filename, uploadID string, number int, reader io.Reader, size int64) (*model.UploadPart, error)
+	CompleteOriginalUpload(ctx context.Context, filename, uploadID string, parts []model.UploadPart) (*model.FileInfo, error)
+	AbortOriginalUpload(ctx context.Context, filename, uploadID string) error
+	// PresignOriginalUpload returns a URL the original can be PUT to directly.
+	PresignOriginalUpload(ctx context.Context, filename string, expiry time.Duration) (string, error)
+	OpenOriginal(ctx context.Context, filename string) (io.ReadCloser, error)
 	Get(ctx context.Context, filename string) ([]byte, error)
 	GetOriginal(ctx context.Context, filename string) ([]byte, error)
 	Delete(ctx context.Context, filename string) error
@@ -16,6 +28,7 @@ type S3Repository interface {
 	Exists(ctx context.Context, filename string) (bool, error)
 	GetURL(ctx context.Context, filename string) (string, error)
 	GetFileInfo(ctx context.Context, filename string) (*model.FileInfo, error)
-	ListFiles(ctx context.Context, prefix string) ([]model.FileInfo, error)
+	// ListFiles returns up to limit files in key order, after the startAfter key.
+	ListFiles(ctx context.Context, prefix, startAfter string, limit int) ([]model.FileInfo, error)
 	CreateFolder(ctx context.Context, path string) error
 }
@@ -0,0 +1,21 @@
+package port
+
+import (
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/google/uuid"
+)
+
+type StatusBroker interface {
+	Subscribe() StatusSubscription
+}
+
+// StatusSubscription receives the status changes of the watched images. A
+// subscriber that does not keep up loses events rather than slowing down
+// the others.
+type StatusSubscription interface {
+	// Events is closed when the subscription is closed.
+	Events() <-chan model.StatusEvent
+	Watch(imageIDs ...uuid.UUID)
+	Unwatch(imageIDs ...uuid.UUID)
+	Close()
+}
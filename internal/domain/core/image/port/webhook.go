@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+)
+
+type WebhookSender interface {
+	// Send posts the signed payload of the delivery and returns the status
+	// code of the response; a non-2xx response is an error.
+	Send(ctx context.Context, delivery *model.WebhookDelivery) (int, error)
+}
@@ -0,0 +1,139 @@
+package vo
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type Color string // "#RRGGBB" or "#RRGGBBAA"
+
+func (c Color) String() string {
+	return string(c)
+}
+
+func (c Color) IsValid() bool {
+	hex := strings.TrimPrefix(c.String(), "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return false
+	}
+	_, err := strconv.ParseUint(hex, 16, 32)
+	return err == nil
+}
+
+func (c Color) NRGBA() color.NRGBA {
+	hex := strings.TrimPrefix(c.String(), "#")
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, _ := strconv.ParseUint(hex, 16, 32)
+	return color.NRGBA{
+		R: uint8(v >> 24),
+		G: uint8(v >> 16),
+		B: uint8(v >> 8),
+		A: uint8(v),
+	}
+}
+
+func NewValidColor(s string) (Color, error) {
+	c := Color(strings.ToLower(s))
+	if !c.IsValid() {
+		return "", fmt.Errorf("invalid color: %s", s)
+	}
+	return c, nil
+}
+
+func NewColorFromNRGBA(c color.NRGBA) Color {
+	if c.A == 255 {
+		return Color(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+	}
+	return Color(fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A))
+}
+
+const (
+	ColorNameBlack  = "black"
+	ColorNameWhite  = "white"
+	ColorNameGray   = "gray"
+	ColorNameRed    = "red"
+	ColorNameOrange = "orange"
+	ColorNameBrown  = "brown"
+	ColorNameYellow = "yellow"
+	ColorNameGreen  = "green"
+	ColorNameCyan   = "cyan"
+	ColorNameBlue   = "blue"
+	ColorNamePurple = "purple"
+	ColorNamePink   = "pink"
+)
+
+var ColorNames = []string{
+	ColorNameBlack, ColorNameWhite, ColorNameGray,
+	ColorNameRed, ColorNameOrange, ColorNameBrown, ColorNameYellow,
+	ColorNameGreen, ColorNameCyan, ColorNameBlue, ColorNamePurple, ColorNamePink,
+}
+
+func IsValidColorName(s string) bool {
+	return slices.Contains(ColorNames, s)
+}
+
+// Name buckets the color into one of ColorNames by its hue, saturation and lightness.
+func (c Color) Name() string {
+	if !c.IsValid() {
+		return ""
+	}
+
+	rgb := c.NRGBA()
+	r, g, b := float64(rgb.R)/255, float64(rgb.G)/255, float64(rgb.B)/255
+
+	hi, lo := max(r, g, b), min(r, g, b)
+	lightness := (hi + lo) / 2
+
+	var saturation float64
+	if hi != lo {
+		saturation = (hi - lo) / (1 - math.Abs(2*lightness-1))
+	}
+
+	switch {
+	case lightness < 0.15:
+		return ColorNameBlack
+	case lightness > 0.9:
+		return ColorNameWhite
+	case saturation < 0.15:
+		return ColorNameGray
+	}
+
+	var hue float64
+	switch hi {
+	case r:
+		hue = math.Mod((g-b)/(hi-lo), 6)
+	case g:
+		hue = (b-r)/(hi-lo) + 2
+	default:
+		hue = (r-g)/(hi-lo) + 4
+	}
+	hue = math.Mod(hue*60+360, 360)
+
+	switch {
+	case hue < 15 || hue >= 335:
+		return ColorNameRed
+	case hue < 45:
+		if lightness < 0.4 {
+			return ColorNameBrown
+		}
+		return ColorNameOrange
+	case hue < 70:
+		return ColorNameYellow
+	case hue < 170:
+		return ColorNameGreen
+	case hue < 200:
+		return ColorNameCyan
+	case hue < 260:
+		return ColorNameBlue
+	case hue < 290:
+		return ColorNamePurple
+	default:
+		return ColorNamePink
+	}
+}
@@ -0,0 +1,13 @@
+package vo
+
+type DeliveryStatus string // "pending", "delivered", "failed"
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+func (s DeliveryStatus) String() string {
+	return string(s)
+}
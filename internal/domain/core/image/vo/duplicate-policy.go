@@ -0,0 +1,32 @@
+package vo
+
+import "fmt"
+
+type DuplicatePolicy string // "allow", "reject", "dedupe"
+
+const (
+	DuplicatePolicyAllow  DuplicatePolicy = "allow"
+	DuplicatePolicyReject DuplicatePolicy = "reject"
+	DuplicatePolicyDedupe DuplicatePolicy = "dedupe"
+)
+
+func (d DuplicatePolicy) String() string {
+	return string(d)
+}
+
+func (d DuplicatePolicy) IsValid() bool {
+	switch d {
+	case DuplicatePolicyAllow, DuplicatePolicyReject, DuplicatePolicyDedupe:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewValidDuplicatePolicy(s string) (DuplicatePolicy, error) {
+	policy := DuplicatePolicy(s)
+	if !policy.IsValid() {
+		return "", fmt.Errorf("invalid duplicate policy: %s", s)
+	}
+	return policy, nil
+}
@@ -1,12 +1,13 @@
 package vo
 
-type Status uint // "uploaded", "processing", "completed", "failed"
+type Status uint // "uploaded", "processing", "completed", "failed", "cancelled"
 
 const (
 	StatusUploaded Status = iota
 	StatusProcessing
 	StatusCompleted
 	StatusFailed
+	StatusCancelled
 	StatusUnknown
 )
 
@@ -20,6 +21,8 @@ func NewStatus(s string) Status {
 		return StatusCompleted
 	case "failed":
 		return StatusFailed
+	case "cancelled":
+		return StatusCancelled
 	default:
 		return StatusUnknown
 	}
@@ -35,7 +38,15 @@ func (s Status) String() string {
 		return "completed"
 	case StatusFailed:
 		return "failed"
+	case StatusCancelled:
+		return "cancelled"
 	default:
 		return "unknown"
 	}
 }
+
+// IsCancellable reports whether the processing of an image in the status
+// can still be cancelled.
+func (s Status) IsCancellable() bool {
+	return s == StatusUploaded || s == StatusProcessing
+}
@@ -0,0 +1,31 @@
+package vo
+
+import "fmt"
+
+type UploadMethod string // "chunked", "presigned"
+
+const (
+	UploadMethodChunked   UploadMethod = "chunked"
+	UploadMethodPresigned UploadMethod = "presigned"
+)
+
+func (m UploadMethod) String() string {
+	return string(m)
+}
+
+func (m UploadMethod) IsValid() bool {
+	switch m {
+	case UploadMethodChunked, UploadMethodPresigned:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewValidUploadMethod(s string) (UploadMethod, error) {
+	method := UploadMethod(s)
+	if !method.IsValid() {
+		return "", fmt.Errorf("invalid upload method: %s", s)
+	}
+	return method, nil
+}
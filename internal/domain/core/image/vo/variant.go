@@ -0,0 +1,26 @@
+package vo
+
+import "fmt"
+
+type Variant string // "original", "processed"
+
+const (
+	VariantOriginal  Variant = originalFilename
+	VariantProcessed Variant = processedFilename
+)
+
+func (v Variant) String() string {
+	return string(v)
+}
+
+func (v Variant) IsValid() bool {
+	return v == VariantOriginal || v == VariantProcessed
+}
+
+func NewValidVariant(s string) (Variant, error) {
+	variant := Variant(s)
+	if !variant.IsValid() {
+		return "", fmt.Errorf("invalid variant: %s", s)
+	}
+	return variant, nil
+}
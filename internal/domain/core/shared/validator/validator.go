@@ -3,11 +3,24 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"regexp"
 
 	"github.com/go-playground/validator/v10"
 )
 
-var validate = validator.New()
+var validate = newValidate()
+
+// colorPattern matches the colors vo.Color parses, "#RRGGBB" or "#RRGGBBAA";
+// the built-in hexcolor also allows the short forms.
+var colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+func newValidate() *validator.Validate {
+	v := validator.New()
+	_ = v.RegisterValidation("color", func(fl validator.FieldLevel) bool {
+		return colorPattern.MatchString(fl.Field().String())
+	})
+	return v
+}
 
 type validationError struct {
 	Field   string `json:"field"`
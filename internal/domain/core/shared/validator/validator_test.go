@@ -0,0 +1,28 @@
+package validator
+
+import "testing"
+
+func TestValidateColor(t *testing.T) {
+	type request struct {
+		Color string `validate:"omitempty,color"`
+	}
+
+	tests := []struct {
+		color string
+		valid bool
+	}{
+		{"", true},
+		{"#1a2B3c", true},
+		{"#1a2b3c80", true},
+		{"#abc", false},
+		{"#abcd", false},
+		{"1a2b3c", false},
+		{"#1a2b3g", false},
+	}
+	for _, tt := range tests {
+		err := ValidateStruct(&request{Color: tt.color})
+		if (err == nil) != tt.valid {
+			t.Errorf("%q: error = %v, want valid %v", tt.color, err, tt.valid)
+		}
+	}
+}
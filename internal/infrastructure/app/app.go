@@ -10,18 +10,27 @@ import (
 	"github.com/D1sordxr/image-processor/internal/domain/app/port"
 )
 
+// shutdownTimeout is the time the components get to release their
+// resources on shutdown, on top of the drain timeout
+const shutdownTimeout = 15 * time.Second
+
 type App struct {
-	log        port.Logger
-	components []port.Component
+	log          port.Logger
+	drainTimeout time.Duration
+	components   []port.Component
 }
 
+// NewApp creates the app; drainTimeout is the time the components get to
+// finish their work on shutdown.
 func NewApp(
 	log port.Logger,
+	drainTimeout time.Duration,
 	components ...port.Component,
 ) *App {
 	return &App{
-		log:        log,
-		components: components,
+		log:          log,
+		drainTimeout: drainTimeout,
+		components:   components,
 	}
 }
 
@@ -62,7 +71,7 @@ func (a *App) Run(ctx context.Context) {
 func (a *App) shutdown() {
 	a.log.Info("App shutting down")
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.drainTimeout+shutdownTimeout)
 	defer cancel()
 
 	errs := make([]error, 0, len(a.components))
@@ -0,0 +1,13 @@
+package config
+
+import (
+	"time"
+)
+
+type Fetcher struct {
+	Timeout      time.Duration `yaml:"timeout" env:"FETCHER_TIMEOUT" env-default:"15s"`
+	MaxRedirects int           `yaml:"max_redirects" env:"FETCHER_MAX_REDIRECTS" env-default:"3"`
+	// AllowedNetworks are private networks (CIDR or single IP) that may be
+	// fetched from, all the others are blocked
+	AllowedNetworks []string `yaml:"allowed_networks" env:"FETCHER_ALLOWED_NETWORKS"`
+}
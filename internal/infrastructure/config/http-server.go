@@ -11,7 +11,20 @@ type HTTPServer struct {
 	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" env-default:"60s"`
 	ReleaseMode  bool          `yaml:"release_mode" env:"HTTP_RELEASE_MODE" env-default:"false"`
 	CORS         bool          `yaml:"cors" env:"HTTP_CORS"`
-	AllowOrigins []string      `yaml:"allow_origins" env:"HTTP_ALLOWED_ORIGINS"`
+	AllowOrigins []string      `yaml:"allowed_origins" env:"HTTP_ALLOWED_ORIGINS"`
 	ServeUI      bool          `yaml:"serve_ui" env:"HTTP_SERVE_UI" env-default:"false"`
 	UIPath       string        `yaml:"ui_path" env:"HTTP_UI_PATH" env-default:"/ui/index.html"`
 }
+
+// Origins returns the origins allowed to call the API from a browser: none
+// without CORS, any when CORS is on and no origin is listed.
+func (s *HTTPServer) Origins() []string {
+	switch {
+	case !s.CORS:
+		return nil
+	case len(s.AllowOrigins) == 0:
+		return []string{"*"}
+	default:
+		return s.AllowOrigins
+	}
+}
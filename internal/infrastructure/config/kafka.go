@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"sync"
 	"time"
 )
@@ -10,9 +11,14 @@ type Kafka struct {
 	ImageTopic      string        `yaml:"image_topic" env:"KAFKA_IMAGE_TOPIC"`
 	HealthTopic     string        `yaml:"health_topic" env:"KAFKA_HEALTH_TOPIC"`
 	ProcessorGroup  string        `yaml:"processor_group" env:"KAFKA_SAVER_GROUP"`
+	DeadLetterTopic string        `yaml:"dead_letter_topic" env:"KAFKA_DEAD_LETTER_TOPIC" env-default:"image-processing-tasks-dlq"`
+	DeadLetterGroup string        `yaml:"dead_letter_group" env:"KAFKA_DEAD_LETTER_GROUP" env-default:"dead-letter-group"`
 	CreateTopic     bool          `yaml:"create_topic" env:"KAFKA_CREATE_TOPIC"`
 	SessionTimeout  time.Duration `yaml:"session_timeout" env:"KAFKA_SESSION_TIMEOUT" env-default:"30s"`
 	MaxPollInterval time.Duration `yaml:"max_poll_interval" env:"KAFKA_MAX_POLL_INTERVAL" env-default:"5m"`
+	// Workers is the size of the processing pool of every consumer, 0 uses
+	// the number of CPUs
+	Workers int `yaml:"workers" env:"KAFKA_WORKERS" env-default:"0"`
 }
 
 var (
@@ -33,3 +39,44 @@ func (k *Kafka) PrepWbfConsumer() ([]string, string, string) {
 	})
 	return kafkaBrokers, k.ImageTopic, k.ProcessorGroup
 }
+
+func (k *Kafka) PrepWbfDeadLetterProducer() ([]string, string) {
+	kafkaOnce.Do(func() {
+		kafkaBrokers = []string{k.Address}
+	})
+	return kafkaBrokers, k.DeadLetterTopic
+}
+
+func (k *Kafka) PrepWbfDeadLetterConsumer() ([]string, string, string) {
+	kafkaOnce.Do(func() {
+		kafkaBrokers = []string{k.Address}
+	})
+	return kafkaBrokers, k.DeadLetterTopic, k.DeadLetterGroup
+}
+
+// RetryTopic names the topic of the tasks retried after the delay,
+// e.g. "image-processing-tasks-retry-10s".
+func (k *Kafka) RetryTopic(delay time.Duration) string {
+	return fmt.Sprintf("%s-retry-%s", k.ImageTopic, formatDelay(delay))
+}
+
+func (k *Kafka) PrepWbfRetryConsumer(delay time.Duration) ([]string, string, string) {
+	kafkaOnce.Do(func() {
+		kafkaBrokers = []string{k.Address}
+	})
+	return kafkaBrokers, k.RetryTopic(delay), fmt.Sprintf("%s-retry-%s", k.ProcessorGroup, formatDelay(delay))
+}
+
+func (k *Kafka) Brokers() []string {
+	kafkaOnce.Do(func() {
+		kafkaBrokers = []string{k.Address}
+	})
+	return kafkaBrokers
+}
+
+func formatDelay(delay time.Duration) string {
+	if delay%time.Minute == 0 {
+		return fmt.Sprintf("%dm", int(delay.Minutes()))
+	}
+	return fmt.Sprintf("%ds", int(delay.Seconds()))
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"time"
+)
+
+type Reconciler struct {
+	Interval    time.Duration `yaml:"interval" env:"RECONCILER_INTERVAL" env-default:"1m"`
+	MaxRequeues int           `yaml:"max_requeues" env:"RECONCILER_MAX_REQUEUES" env-default:"3"`
+	// StuckTimeout is how long an image may be processing before its task is
+	// considered lost; it has to outlast the retry delays
+	StuckTimeout   time.Duration `yaml:"stuck_timeout" env:"RECONCILER_STUCK_TIMEOUT" env-default:"30m"`
+	OrphanInterval time.Duration `yaml:"orphan_interval" env:"RECONCILER_ORPHAN_INTERVAL" env-default:"1h"`
+	// OrphanAge is how old an original with no image must be to be reported,
+	// the originals of unfinished uploads are kept for the upload TTL anyway
+	OrphanAge time.Duration `yaml:"orphan_age" env:"RECONCILER_ORPHAN_AGE" env-default:"48h"`
+	// DeleteOrphans removes the orphaned originals, otherwise they are only
+	// reported
+	DeleteOrphans bool `yaml:"delete_orphans" env:"RECONCILER_DELETE_ORPHANS" env-default:"false"`
+}
@@ -0,0 +1,13 @@
+package config
+
+import (
+	"time"
+)
+
+type Webhook struct {
+	Secret  string        `yaml:"secret" env:"WEBHOOK_SECRET"`
+	Timeout time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT" env-default:"10s"`
+	// AllowedNetworks are private networks (CIDR or single IP) callbacks
+	// may be delivered to, all the others are blocked
+	AllowedNetworks []string `yaml:"allowed_networks" env:"WEBHOOK_ALLOWED_NETWORKS"`
+}
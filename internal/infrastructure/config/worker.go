@@ -0,0 +1,11 @@
+package config
+
+import (
+	"time"
+)
+
+type Worker struct {
+	// DrainTimeout is how long the in-flight tasks may run on shutdown,
+	// the unfinished ones are requeued
+	DrainTimeout time.Duration `yaml:"drain_timeout" env:"WORKER_DRAIN_TIMEOUT" env-default:"30s"`
+}
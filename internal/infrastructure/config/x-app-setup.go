@@ -9,10 +9,14 @@ import (
 const basicAppConfigPath = "./configs/app/prod.yaml"
 
 type AppConfig struct {
-	Storage   Postgres   `yaml:"storage"`
-	S3Storage Minio      `yaml:"s3_storage"`
-	Broker    Kafka      `yaml:"broker"`
-	Server    HTTPServer `yaml:"server"`
+	Storage    Postgres   `yaml:"storage"`
+	S3Storage  Minio      `yaml:"s3_storage"`
+	Broker     Kafka      `yaml:"broker"`
+	Server     HTTPServer `yaml:"server"`
+	Fetcher    Fetcher    `yaml:"fetcher"`
+	Webhook    Webhook    `yaml:"webhook"`
+	Worker     Worker     `yaml:"worker"`
+	Reconciler Reconciler `yaml:"reconciler"`
 }
 
 func NewAppConfig() *AppConfig {
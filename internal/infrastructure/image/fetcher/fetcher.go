@@ -0,0 +1,236 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/config"
+)
+
+const (
+	dialTimeout     = 5 * time.Second
+	defaultFilename = "image"
+)
+
+var (
+	ErrUnsupportedScheme = errors.New("unsupported URL scheme: only http and https are allowed")
+	ErrForbiddenAddress  = errors.New("address is not allowed")
+	ErrTooManyRedirects  = errors.New("too many redirects")
+	ErrUnexpectedStatus  = errors.New("unexpected response status")
+	ErrTooLarge          = errors.New("remote file is too large")
+)
+
+// blockedNetworks are the special-purpose ranges not covered by the net.IP
+// methods used in isPublic.
+var blockedNetworks = mustParseNetworks(
+	"0.0.0.0/8",      // "this" network
+	"100.64.0.0/10",  // carrier-grade NAT
+	"192.0.0.0/24",   // IETF protocol assignments
+	"198.18.0.0/15",  // benchmarking
+	"240.0.0.0/4",    // reserved, broadcast
+	"64:ff9b::/96",   // NAT64
+	"64:ff9b:1::/48", // local-use NAT64
+	"2001:db8::/32",  // documentation
+)
+
+// Fetcher downloads images from the internet. Private, loopback and other
+// special-purpose addresses are refused unless allow-listed.
+type Fetcher struct {
+	client *http.Client
+}
+
+func New(cfg config.Fetcher) *Fetcher {
+	return &Fetcher{
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: NewTransport(cfg.AllowedNetworks, cfg.Timeout),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > cfg.MaxRedirects {
+					return ErrTooManyRedirects
+				}
+				return checkScheme(req.URL)
+			},
+		},
+	}
+}
+
+// NewTransport returns a transport that refuses to connect to non-public
+// addresses outside the allowed networks. The check is done on the address
+// being dialed, so a redirect or a DNS record pointing to an internal host
+// is refused as well.
+func NewTransport(allowedNetworks []string, timeout time.Duration) *http.Transport {
+	guard := &addressGuard{
+		allowed: mustParseNetworks(allowedNetworks...),
+	}
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: guard.control,
+	}
+
+	return &http.Transport{
+		Proxy:                 nil, // a proxy would dial the target instead of us
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   dialTimeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+}
+
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string, maxSize int64) (*model.RemoteFile, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse URL: %w", err)
+	}
+	if err = checkScheme(target); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "image/jpeg, image/png, image/gif")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+	if resp.ContentLength > maxSize {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %d bytes", ErrTooLarge, resp.ContentLength)
+	}
+
+	return &model.RemoteFile{
+		Body: &limitedBody{
+			ReadCloser: resp.Body,
+			remaining:  maxSize,
+		},
+		Size:        resp.ContentLength,
+		Filename:    filenameOf(resp),
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+type addressGuard struct {
+	allowed []*net.IPNet
+}
+
+// control is called with the resolved address of every connection.
+func (g *addressGuard) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	for _, network := range g.allowed {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	if !isPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+
+	return nil
+}
+
+func isPublic(ip net.IP) bool {
+	if ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() {
+		return false
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkScheme(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrUnsupportedScheme
+	}
+	return nil
+}
+
+// filenameOf prefers the name from Content-Disposition, then the last
+// segment of the final URL.
+func filenameOf(resp *http.Response) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		if name := path.Base(params["filename"]); name != "." && name != "/" {
+			return name
+		}
+	}
+	if name := path.Base(resp.Request.URL.Path); name != "." && name != "/" {
+		return name
+	}
+	return defaultFilename
+}
+
+// limitedBody fails instead of truncating the file when it exceeds the limit.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrTooLarge
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
+
+func mustParseNetworks(networks ...string) []*net.IPNet {
+	parsed := make([]*net.IPNet, 0, len(networks))
+	for _, network := range networks {
+		if ip := net.ParseIP(network); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			parsed = append(parsed, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			panic("invalid network " + network + ": " + err.Error())
+		}
+		parsed = append(parsed, ipNet)
+	}
+	return parsed
+}
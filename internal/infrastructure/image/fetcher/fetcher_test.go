@@ -0,0 +1,119 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/infrastructure/config"
+)
+
+func TestAddressGuardControl(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:80", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"10.0.0.1:80", false},
+		{"172.16.5.4:80", false},
+		{"192.168.1.1:80", false},
+		{"169.254.169.254:80", false},
+		{"0.0.0.0:80", false},
+		{"100.64.0.1:80", false},
+		{"[fd00::1]:80", false},
+		{"[fe80::1]:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"[::ffff:10.0.0.1]:80", false},
+		{"[64:ff9b::7f00:1]:80", false},
+		{"[64:ff9b::a00:1]:80", false},
+		{"[64:ff9b:1::a00:1]:80", false},
+	}
+
+	guard := &addressGuard{}
+	for _, tt := range tests {
+		err := guard.control("tcp", tt.address, nil)
+		switch {
+		case tt.allowed && err != nil:
+			t.Errorf("%s: unexpected error: %v", tt.address, err)
+		case !tt.allowed && !errors.Is(err, ErrForbiddenAddress):
+			t.Errorf("%s: error = %v, want %v", tt.address, err, ErrForbiddenAddress)
+		}
+	}
+}
+
+func TestAddressGuardAllowedNetworks(t *testing.T) {
+	guard := &addressGuard{allowed: mustParseNetworks("10.1.0.0/16", "127.0.0.1")}
+
+	for _, address := range []string{"10.1.2.3:80", "127.0.0.1:80"} {
+		if err := guard.control("tcp", address, nil); err != nil {
+			t.Errorf("%s: unexpected error: %v", address, err)
+		}
+	}
+	for _, address := range []string{"10.2.0.1:80", "127.0.0.2:80"} {
+		if err := guard.control("tcp", address, nil); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("%s: error = %v, want %v", address, err, ErrForbiddenAddress)
+		}
+	}
+}
+
+func TestFetchRefusesLoopback(t *testing.T) {
+	var hit atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { hit.Store(true) }))
+	defer server.Close()
+
+	f := New(config.Fetcher{Timeout: 5 * time.Second, MaxRedirects: 3})
+	if _, err := f.Fetch(context.Background(), server.URL, 1<<20); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("error = %v, want %v", err, ErrForbiddenAddress)
+	}
+	if hit.Load() {
+		t.Error("the loopback server was reached")
+	}
+}
+
+func TestFetchRefusesRedirectToInternalAddress(t *testing.T) {
+	// the target listens on another loopback address, which is not allowed
+	listener, err := net.Listen("tcp", "127.0.0.2:0")
+	if err != nil {
+		t.Skipf("cannot listen on 127.0.0.2: %v", err)
+	}
+	var hit atomic.Bool
+	internal := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { hit.Store(true) }))
+	internal.Listener = listener
+	internal.Start()
+	defer internal.Close()
+
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL+"/image.png", http.StatusFound)
+	}))
+	defer public.Close()
+
+	// the first server stands for a public host
+	f := New(config.Fetcher{
+		Timeout:         5 * time.Second,
+		MaxRedirects:    3,
+		AllowedNetworks: []string{"127.0.0.1"},
+	})
+	if _, err = f.Fetch(context.Background(), public.URL, 1<<20); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("error = %v, want %v", err, ErrForbiddenAddress)
+	}
+	if hit.Load() {
+		t.Error("the internal server was reached through the redirect")
+	}
+}
+
+func TestFetchRefusesUnsupportedScheme(t *testing.T) {
+	f := New(config.Fetcher{Timeout: time.Second, MaxRedirects: 3})
+	for _, rawURL := range []string{"file:///etc/passwd", "gopher://example.com", "http://"} {
+		if _, err := f.Fetch(context.Background(), rawURL, 1<<20); !errors.Is(err, ErrUnsupportedScheme) {
+			t.Errorf("%s: error = %v, want %v", rawURL, err, ErrUnsupportedScheme)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package processor
+
+import (
+	"fmt"
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// DefaultLevelClipPercent is the share of the darkest and of the brightest
+// pixels per channel ignored when stretching the histogram.
+const DefaultLevelClipPercent = 0.5
+
+// AutoLevel stretches every channel so that the given percentiles of its
+// histogram map to 0 and 255.
+func (p *Processor) AutoLevel(originalImage image.Image, clipPercent float64) (image.Image, error) {
+	const op = opAutoLevel
+
+	originalBounds := originalImage.Bounds()
+	if originalBounds.Dx() <= 0 || originalBounds.Dy() <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+	if clipPercent < 0 || clipPercent >= 50 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidClipPercent)
+	}
+
+	img := toNRGBA(originalImage)
+
+	var histograms [3][256]int
+	var pixels int
+	for i := 0; i < len(img.Pix); i += 4 {
+		if img.Pix[i+3] == 0 {
+			continue
+		}
+		for ch := 0; ch < 3; ch++ {
+			histograms[ch][img.Pix[i+ch]]++
+		}
+		pixels++
+	}
+	if pixels == 0 {
+		return img, nil
+	}
+
+	clip := int(float64(pixels) * clipPercent / 100)
+	var luts [3][256]uint8
+	for ch := 0; ch < 3; ch++ {
+		low, high := percentileBounds(histograms[ch], clip)
+		for v := 0; v < 256; v++ {
+			switch {
+			case high <= low:
+				luts[ch][v] = uint8(v)
+			case v <= low:
+				luts[ch][v] = 0
+			case v >= high:
+				luts[ch][v] = 255
+			default:
+				luts[ch][v] = uint8((v - low) * 255 / (high - low))
+			}
+		}
+	}
+
+	applyLUTs(img, luts)
+	return img, nil
+}
+
+// AutoWhiteBalance removes the color cast using the gray world assumption:
+// channels are scaled so that their means become equal.
+func (p *Processor) AutoWhiteBalance(originalImage image.Image) (image.Image, error) {
+	const op = opAutoWhiteBalance
+
+	originalBounds := originalImage.Bounds()
+	if originalBounds.Dx() <= 0 || originalBounds.Dy() <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+
+	img := toNRGBA(originalImage)
+
+	var sums [3]float64
+	var pixels int
+	for i := 0; i < len(img.Pix); i += 4 {
+		if img.Pix[i+3] == 0 {
+			continue
+		}
+		for ch := 0; ch < 3; ch++ {
+			sums[ch] += float64(img.Pix[i+ch])
+		}
+		pixels++
+	}
+	if pixels == 0 || sums[0] == 0 || sums[1] == 0 || sums[2] == 0 {
+		return img, nil
+	}
+
+	gray := (sums[0] + sums[1] + sums[2]) / 3
+	var luts [3][256]uint8
+	for ch := 0; ch < 3; ch++ {
+		gain := gray / sums[ch]
+		for v := 0; v < 256; v++ {
+			luts[ch][v] = uint8(min(255, float64(v)*gain+0.5))
+		}
+	}
+
+	applyLUTs(img, luts)
+	return img, nil
+}
+
+func percentileBounds(histogram [256]int, clip int) (int, int) {
+	low, count := 0, 0
+	for ; low < 255; low++ {
+		count += histogram[low]
+		if count > clip {
+			break
+		}
+	}
+
+	high, count := 255, 0
+	for ; high > 0; high-- {
+		count += histogram[high]
+		if count > clip {
+			break
+		}
+	}
+
+	return low, high
+}
+
+func applyLUTs(img *image.NRGBA, luts [3][256]uint8) {
+	for i := 0; i < len(img.Pix); i += 4 {
+		for ch := 0; ch < 3; ch++ {
+			img.Pix[i+ch] = luts[ch][img.Pix[i+ch]]
+		}
+	}
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	bounds := img.Bounds()
+	result := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(result, result.Bounds(), img, bounds.Min, draw.Src)
+	return result
+}
@@ -0,0 +1,177 @@
+package processor
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func channelStats(img image.Image) (lows, highs [3]uint8, means [3]float64) {
+	bounds := img.Bounds()
+	lows = [3]uint8{255, 255, 255}
+	var pixels int
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				continue
+			}
+			for ch, v := range [3]uint8{c.R, c.G, c.B} {
+				lows[ch] = min(lows[ch], v)
+				highs[ch] = max(highs[ch], v)
+				means[ch] += float64(v)
+			}
+			pixels++
+		}
+	}
+	for ch := range means {
+		means[ch] /= float64(pixels)
+	}
+	return lows, highs, means
+}
+
+func TestAutoLevelStretches(t *testing.T) {
+	p := New()
+	img := image.NewNRGBA(image.Rect(0, 0, 100, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 100; x++ {
+			v := uint8(80 + x)
+			img.Set(x, y, color.NRGBA{R: v, G: v / 2, B: 255 - v, A: 255})
+		}
+	}
+
+	result, err := p.AutoLevel(img, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lows, highs, _ := channelStats(result)
+	for ch := range lows {
+		if lows[ch] != 0 || highs[ch] != 255 {
+			t.Errorf("channel %d: range [%d, %d], want [0, 255]", ch, lows[ch], highs[ch])
+		}
+	}
+
+	if lows, highs, _ := channelStats(img); lows[0] != 80 || highs[0] != 179 {
+		t.Errorf("original modified: red range [%d, %d]", lows[0], highs[0])
+	}
+}
+
+func TestAutoLevelClipsOutliers(t *testing.T) {
+	p := New()
+	img := image.NewNRGBA(image.Rect(0, 0, 100, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 100; x++ {
+			v := uint8(100 + x/2)
+			img.Set(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	// a couple of pixels outside the range must not hold the stretch back
+	img.Set(0, 0, color.NRGBA{A: 255})
+	img.Set(1, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+
+	result, err := p.AutoLevel(img, DefaultLevelClipPercent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := color.NRGBAModel.Convert(result.At(99, 5)).(color.NRGBA)
+	if got.R != 255 {
+		t.Errorf("brightest regular pixel = %d, want 255", got.R)
+	}
+	got = color.NRGBAModel.Convert(result.At(1, 5)).(color.NRGBA)
+	if got.R != 0 {
+		t.Errorf("darkest regular pixel = %d, want 0", got.R)
+	}
+}
+
+func TestAutoLevelFlatImage(t *testing.T) {
+	p := New()
+	gray := color.NRGBA{R: 120, G: 120, B: 120, A: 255}
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, gray)
+		}
+	}
+
+	result, err := p.AutoLevel(img, DefaultLevelClipPercent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := color.NRGBAModel.Convert(result.At(4, 4)); got != gray {
+		t.Errorf("flat image changed to %v", got)
+	}
+}
+
+func TestAutoLevelInvalid(t *testing.T) {
+	p := New()
+
+	tests := []struct {
+		name        string
+		img         image.Image
+		clipPercent float64
+		wantErr     error
+	}{
+		{name: "negative clip", img: image.NewNRGBA(image.Rect(0, 0, 4, 4)), clipPercent: -1, wantErr: ErrInvalidClipPercent},
+		{name: "half clip", img: image.NewNRGBA(image.Rect(0, 0, 4, 4)), clipPercent: 50, wantErr: ErrInvalidClipPercent},
+		{name: "empty image", img: image.NewNRGBA(image.Rect(0, 0, 0, 0)), clipPercent: 1, wantErr: ErrWrongBounds},
+	}
+
+	for _, tt := range tests {
+		if _, err := p.AutoLevel(tt.img, tt.clipPercent); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAutoWhiteBalance(t *testing.T) {
+	p := New()
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			v := float64(x+y) * 2
+			// a warm cast over a neutral gradient
+			img.Set(x, y, color.NRGBA{R: uint8(v), G: uint8(v * 0.8), B: uint8(v * 0.5), A: 255})
+		}
+	}
+	// transparent pixels do not count toward the means
+	img.Set(0, 0, color.NRGBA{R: 0, G: 0, B: 255, A: 0})
+
+	result, err := p.AutoWhiteBalance(img)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, _, before := channelStats(img)
+	_, _, after := channelStats(result)
+	if before[0]-before[2] < 30 {
+		t.Fatalf("test image has no cast: means %v", before)
+	}
+	for ch := 1; ch < 3; ch++ {
+		if diff := after[ch] - after[0]; diff < -2 || diff > 2 {
+			t.Errorf("channel means %v are not balanced", after)
+			break
+		}
+	}
+}
+
+func TestAutoWhiteBalanceBlackChannel(t *testing.T) {
+	p := New()
+	red := color.NRGBA{R: 200, A: 255}
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, red)
+		}
+	}
+
+	result, err := p.AutoWhiteBalance(img)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := color.NRGBAModel.Convert(result.At(1, 1)); got != red {
+		t.Errorf("image with an empty channel changed to %v", got)
+	}
+}
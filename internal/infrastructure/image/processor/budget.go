@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+const (
+	// MinBudgetQuality is the lowest JPEG quality tried before downscaling.
+	MinBudgetQuality = 30
+	// MinBudgetSize is the smallest side an image is downscaled to.
+	MinBudgetSize = 16
+
+	maxDownscaleSteps = 10
+)
+
+// EncodeWithinBudget encodes the image so that it takes at most maxBytes. For
+// JPEG the highest quality not above the requested one that fits is found by
+// binary search; when even MinBudgetQuality is too large, or the format has no
+// quality, the image is downscaled and the search repeated. It returns the
+// encoded data, the image that was encoded and the chosen quality (0 for
+// formats without one).
+func (p *Processor) EncodeWithinBudget(
+	originalImage image.Image,
+	format string,
+	quality int,
+	maxBytes int,
+) ([]byte, image.Image, int, error) {
+	const op = opEncodeWithinBudget
+
+	if maxBytes <= 0 {
+		return nil, nil, 0, fmt.Errorf("%s: %w", op, ErrInvalidMaxBytes)
+	}
+	if quality <= 0 {
+		quality = DefaultQuality
+	}
+
+	lossy := isJPEG(format)
+	img := originalImage
+	for step := 0; ; step++ {
+		var data []byte
+		var chosen int
+		var err error
+		if lossy {
+			data, chosen, err = p.searchQuality(img, format, min(MinBudgetQuality, quality), quality, maxBytes)
+		} else {
+			data, err = p.ConvertFormat(img, format, quality)
+		}
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		if len(data) <= maxBytes {
+			return data, img, chosen, nil
+		}
+
+		bounds := img.Bounds()
+		if step == maxDownscaleSteps || min(bounds.Dx(), bounds.Dy()) <= MinBudgetSize {
+			return nil, nil, 0, fmt.Errorf("%s: %w: %d bytes at %dx%d", op, ErrBudgetUnreachable, len(data), bounds.Dx(), bounds.Dy())
+		}
+
+		// the encoded size is roughly proportional to the area
+		scale := math.Sqrt(float64(maxBytes)/float64(len(data))) * 0.95
+		scale = math.Max(0.5, math.Min(0.9, scale))
+		width := max(MinBudgetSize, int(float64(bounds.Dx())*scale))
+		height := max(MinBudgetSize, int(float64(bounds.Dy())*scale))
+		if img, err = p.Resize(img, width, height); err != nil {
+			return nil, nil, 0, fmt.Errorf("%s: %w: %w", op, ErrResizeFailed, err)
+		}
+	}
+}
+
+// searchQuality returns the encoding at the highest quality in [low, high]
+// that fits, or the encoding at low when none does.
+func (p *Processor) searchQuality(img image.Image, format string, low, high, maxBytes int) ([]byte, int, error) {
+	best, err := p.ConvertFormat(img, format, low)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(best) > maxBytes {
+		return best, low, nil
+	}
+
+	chosen := low
+	for low < high {
+		mid := (low + high + 1) / 2
+		data, err := p.ConvertFormat(img, format, mid)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(data) <= maxBytes {
+			best, chosen, low = data, mid, mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	return best, chosen, nil
+}
+
+func isJPEG(format string) bool {
+	switch strings.ToLower(format) {
+	case "jpeg", "jpg":
+		return true
+	default:
+		return false
+	}
+}
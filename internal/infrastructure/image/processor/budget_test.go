@@ -0,0 +1,117 @@
+package processor
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+// noiseImage is hard to compress, so that the budget is reached only by
+// lowering the quality or the size.
+func noiseImage(width, height int) *image.NRGBA {
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rng.Intn(256))
+	}
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+	return img
+}
+
+func TestEncodeWithinBudgetQuality(t *testing.T) {
+	p := New()
+	img := noiseImage(256, 256)
+
+	full, err := p.ConvertFormat(img, "jpeg", 90)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	maxBytes := len(full) * 3 / 4
+
+	data, encoded, quality, err := p.EncodeWithinBudget(img, "jpeg", 90, maxBytes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) > maxBytes {
+		t.Errorf("size %d exceeds the budget %d", len(data), maxBytes)
+	}
+	if quality < MinBudgetQuality || quality >= 90 {
+		t.Errorf("quality = %d, want in [%d, 90)", quality, MinBudgetQuality)
+	}
+	if encoded.Bounds() != img.Bounds() {
+		t.Errorf("image resized to %v, the quality alone fits", encoded.Bounds())
+	}
+
+	// the next quality up does not fit, so the search found the highest
+	if quality < 90 {
+		above, err := p.ConvertFormat(img, "jpeg", quality+1)
+		if err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		if len(above) <= maxBytes {
+			t.Errorf("quality %d also fits (%d bytes), want the highest", quality+1, len(above))
+		}
+	}
+}
+
+func TestEncodeWithinBudgetKeepsFittingQuality(t *testing.T) {
+	p := New()
+	img := noiseImage(32, 32)
+
+	data, _, quality, err := p.EncodeWithinBudget(img, "jpeg", 80, 1<<20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quality != 80 {
+		t.Errorf("quality = %d, want the requested 80", quality)
+	}
+	if len(data) > 1<<20 {
+		t.Errorf("size %d exceeds the budget", len(data))
+	}
+}
+
+func TestEncodeWithinBudgetDownscales(t *testing.T) {
+	p := New()
+	img := noiseImage(512, 512)
+
+	for _, format := range []string{"jpeg", "png"} {
+		const maxBytes = 8 << 10
+		data, encoded, _, err := p.EncodeWithinBudget(img, format, 90, maxBytes)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if len(data) > maxBytes {
+			t.Errorf("%s: size %d exceeds the budget %d", format, len(data), maxBytes)
+		}
+		bounds := encoded.Bounds()
+		if bounds.Dx() >= 512 || bounds.Dy() >= 512 {
+			t.Errorf("%s: image not downscaled: %v", format, bounds)
+		}
+		if bounds.Dx() < MinBudgetSize || bounds.Dy() < MinBudgetSize {
+			t.Errorf("%s: image downscaled below %d: %v", format, MinBudgetSize, bounds)
+		}
+	}
+}
+
+func TestEncodeWithinBudgetUnreachable(t *testing.T) {
+	p := New()
+
+	if _, _, _, err := p.EncodeWithinBudget(noiseImage(64, 64), "png", 0, 10); !errors.Is(err, ErrBudgetUnreachable) {
+		t.Errorf("error = %v, want %v", err, ErrBudgetUnreachable)
+	}
+}
+
+func TestEncodeWithinBudgetInvalid(t *testing.T) {
+	p := New()
+	img := image.NewUniform(color.White)
+
+	for _, maxBytes := range []int{0, -1} {
+		if _, _, _, err := p.EncodeWithinBudget(img, "jpeg", 80, maxBytes); !errors.Is(err, ErrInvalidMaxBytes) {
+			t.Errorf("max bytes %d: error = %v, want %v", maxBytes, err, ErrInvalidMaxBytes)
+		}
+	}
+}
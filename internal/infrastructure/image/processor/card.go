@@ -0,0 +1,299 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	FontRegular = "regular"
+	FontBold    = "bold"
+	FontItalic  = "italic"
+	FontMono    = "mono"
+
+	FitCover   = "cover"
+	FitContain = "contain"
+
+	AlignLeft   = "left"
+	AlignCenter = "center"
+	AlignRight  = "right"
+
+	DefaultCardFormat     = "png"
+	DefaultCardTextColor  = vo.Color("#000000")
+	DefaultCardLineHeight = 1.2
+	// MaxCardSize limits each side of the card canvas.
+	MaxCardSize = 4096
+)
+
+var fontData = map[string][]byte{
+	FontRegular: goregular.TTF,
+	FontBold:    gobold.TTF,
+	FontItalic:  goitalic.TTF,
+	FontMono:    gomono.TTF,
+}
+
+var (
+	fontsMu sync.Mutex
+	fonts   = map[string]*opentype.Font{}
+)
+
+// RenderCard draws the template: background, then image slots, then text
+// slots, each in the order they are defined. Slots without content are left empty.
+func (p *Processor) RenderCard(
+	template model.CardTemplate,
+	content model.CardContent,
+	format string,
+	quality int,
+) (*model.ProcessingResult, error) {
+	const op = opRenderCard
+	start := time.Now()
+
+	if template.Width <= 0 || template.Height <= 0 ||
+		template.Width > MaxCardSize || template.Height > MaxCardSize {
+		return nil, fmt.Errorf("%s: %w: %dx%d", op, ErrInvalidCanvas, template.Width, template.Height)
+	}
+
+	background, err := vo.NewValidColor(template.Background)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrInvalidColor, err)
+	}
+
+	card := image.NewNRGBA(image.Rect(0, 0, template.Width, template.Height))
+	draw.Draw(card, card.Bounds(), image.NewUniform(background.NRGBA()), image.Point{}, draw.Src)
+
+	for _, slot := range template.ImageSlots {
+		data, ok := content.Images[slot.Name]
+		if !ok {
+			continue
+		}
+		if err = p.drawImageSlot(card, slot, data); err != nil {
+			return nil, fmt.Errorf("%s: image slot %q: %w", op, slot.Name, err)
+		}
+	}
+
+	for _, slot := range template.TextSlots {
+		text, ok := content.Texts[slot.Name]
+		if !ok {
+			text = slot.Default
+		}
+		if text == "" {
+			continue
+		}
+		if err = drawTextSlot(card, slot, text); err != nil {
+			return nil, fmt.Errorf("%s: text slot %q: %w", op, slot.Name, err)
+		}
+	}
+
+	if format == "" {
+		format = DefaultCardFormat
+	}
+	data, err := p.ConvertFormat(card, format, quality)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrFormatConversionFailed, err)
+	}
+
+	result := &model.ProcessingResult{
+		ProcessedData: data,
+		Format:        format,
+		Width:         template.Width,
+		Height:        template.Height,
+		Size:          int64(len(data)),
+	}
+	if err = p.describe(card, result); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	result.ProcessingTime = time.Since(start)
+
+	return result, nil
+}
+
+func (p *Processor) drawImageSlot(dst *image.NRGBA, slot model.CardImageSlot, data []byte) error {
+	if slot.Width <= 0 || slot.Height <= 0 {
+		return ErrInvalidSlot
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrImageDecodeFailed, err)
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		return ErrWrongBounds
+	}
+
+	slotImage := image.NewNRGBA(image.Rect(0, 0, slot.Width, slot.Height))
+	switch slot.Fit {
+	case "", FitCover:
+		// crop the middle of the source to the aspect ratio of the slot
+		scale := max(float64(slot.Width)/float64(bounds.Dx()), float64(slot.Height)/float64(bounds.Dy()))
+		cropWidth := min(bounds.Dx(), max(1, int(float64(slot.Width)/scale)))
+		cropHeight := min(bounds.Dy(), max(1, int(float64(slot.Height)/scale)))
+		crop := image.Rect(0, 0, cropWidth, cropHeight).Add(image.Pt(
+			bounds.Min.X+(bounds.Dx()-cropWidth)/2,
+			bounds.Min.Y+(bounds.Dy()-cropHeight)/2,
+		))
+		draw.BiLinear.Scale(slotImage, slotImage.Bounds(), img, crop, draw.Src, nil)
+	case FitContain:
+		scale := min(float64(slot.Width)/float64(bounds.Dx()), float64(slot.Height)/float64(bounds.Dy()))
+		fitWidth := max(1, int(float64(bounds.Dx())*scale))
+		fitHeight := max(1, int(float64(bounds.Dy())*scale))
+		offset := image.Pt((slot.Width-fitWidth)/2, (slot.Height-fitHeight)/2)
+		draw.BiLinear.Scale(slotImage, image.Rect(0, 0, fitWidth, fitHeight).Add(offset), img, bounds, draw.Src, nil)
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidFit, slot.Fit)
+	}
+
+	var src image.Image = slotImage
+	if slot.CornerRadius > 0 {
+		src = applyOutline(slotImage, RoundedRect{Bounds: slotImage.Bounds(), Radius: float64(slot.CornerRadius)})
+	}
+
+	target := image.Rect(slot.X, slot.Y, slot.X+slot.Width, slot.Y+slot.Height)
+	draw.Draw(dst, target, src, image.Point{}, draw.Over)
+	return nil
+}
+
+func drawTextSlot(dst *image.NRGBA, slot model.CardTextSlot, text string) error {
+	if slot.Width <= 0 || slot.FontSize <= 0 {
+		return ErrInvalidSlot
+	}
+
+	textColor := DefaultCardTextColor
+	if slot.Color != "" {
+		var err error
+		if textColor, err = vo.NewValidColor(slot.Color); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidColor, err)
+		}
+	}
+
+	face, err := newFace(slot.Font, slot.FontSize)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = face.Close() }()
+
+	drawer := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(textColor.NRGBA()),
+		Face: face,
+	}
+
+	lines := wrapText(drawer, text, slot.Width, slot.MaxLines)
+
+	lineHeight := slot.LineHeight
+	if lineHeight <= 0 {
+		lineHeight = DefaultCardLineHeight
+	}
+	step := fixed.Int26_6(slot.FontSize * lineHeight * 64)
+	baseline := fixed.I(slot.Y) + face.Metrics().Ascent
+
+	for _, line := range lines {
+		x := fixed.I(slot.X)
+		switch slot.Align {
+		case "", AlignLeft:
+		case AlignCenter:
+			x += (fixed.I(slot.Width) - drawer.MeasureString(line)) / 2
+		case AlignRight:
+			x += fixed.I(slot.Width) - drawer.MeasureString(line)
+		default:
+			return fmt.Errorf("%w: %s", ErrInvalidAlign, slot.Align)
+		}
+
+		drawer.Dot = fixed.Point26_6{X: x, Y: baseline}
+		drawer.DrawString(line)
+		baseline += step
+	}
+
+	return nil
+}
+
+// wrapText breaks the text into lines no wider than width. Words longer than
+// a line are broken by characters. The last kept line ends with an ellipsis
+// when the text does not fit into maxLines.
+func wrapText(drawer *font.Drawer, text string, width, maxLines int) []string {
+	limit := fixed.I(width)
+	fits := func(s string) bool { return drawer.MeasureString(s) <= limit }
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if fits(candidate) {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = word
+			for !fits(line) && len([]rune(line)) > 1 {
+				runes := []rune(line)
+				cut := len(runes) - 1
+				for cut > 1 && !fits(string(runes[:cut])) {
+					cut--
+				}
+				lines = append(lines, string(runes[:cut]))
+				line = string(runes[cut:])
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	if maxLines > 0 && len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := []rune(strings.TrimSpace(lines[maxLines-1]))
+		for len(last) > 0 && !fits(string(last)+"…") {
+			last = last[:len(last)-1]
+		}
+		lines[maxLines-1] = strings.TrimSpace(string(last)) + "…"
+	}
+
+	return lines
+}
+
+func newFace(name string, size float64) (font.Face, error) {
+	if name == "" {
+		name = FontRegular
+	}
+
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+
+	f, ok := fonts[name]
+	if !ok {
+		data, known := fontData[name]
+		if !known {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFont, name)
+		}
+		var err error
+		if f, err = opentype.Parse(data); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnknownFont, err)
+		}
+		fonts[name] = f
+	}
+
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
@@ -0,0 +1,174 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"golang.org/x/image/draw"
+)
+
+const (
+	// MaxPSNR is reported for identical images instead of infinity.
+	MaxPSNR = 100.0
+	// DiffAmplification scales pixel differences so that small ones are visible.
+	DiffAmplification = 4
+
+	// compareMaxSize limits the resolution images are compared at.
+	compareMaxSize = 1024
+	ssimWindow     = 8
+	ssimC1         = (0.01 * 255) * (0.01 * 255)
+	ssimC2         = (0.03 * 255) * (0.03 * 255)
+)
+
+// Compare measures how close the second image is to the first one. The second
+// image is scaled to the size of the first one before comparing.
+func (p *Processor) Compare(imageData, otherImageData []byte, withDiff bool) (*model.ComparisonResult, error) {
+	const op = opCompare
+
+	if len(imageData) == 0 || len(otherImageData) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrEmptyImageData)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrImageDecodeFailed, err)
+	}
+	other, _, err := image.Decode(bytes.NewReader(otherImageData))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrImageDecodeFailed, err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 || other.Bounds().Dx() <= 0 || other.Bounds().Dy() <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > compareMaxSize || height > compareMaxSize {
+		scale := float64(compareMaxSize) / float64(max(width, height))
+		width, height = max(1, int(float64(width)*scale)), max(1, int(float64(height)*scale))
+	}
+
+	a := scaleToNRGBA(img, width, height)
+	b := scaleToNRGBA(other, width, height)
+
+	hashA, err := p.DifferenceHash(a)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	hashB, err := p.DifferenceHash(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := &model.ComparisonResult{
+		PSNR:         psnr(a, b),
+		SSIM:         ssim(a, b),
+		HashDistance: HammingDistance(hashA, hashB),
+	}
+
+	if withDiff {
+		var buf bytes.Buffer
+		if err = png.Encode(&buf, diffImage(a, b)); err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", op, ErrImageEncodeFailed, err)
+		}
+		result.DiffImage = buf.Bytes()
+	}
+
+	return result, nil
+}
+
+func scaleToNRGBA(img image.Image, width, height int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	if img.Bounds().Dx() == width && img.Bounds().Dy() == height {
+		draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Src)
+	} else {
+		draw.BiLinear.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+	}
+	return dst
+}
+
+func psnr(a, b *image.NRGBA) float64 {
+	var sum float64
+	for i := 0; i < len(a.Pix); i += 4 {
+		for ch := 0; ch < 3; ch++ {
+			d := float64(a.Pix[i+ch]) - float64(b.Pix[i+ch])
+			sum += d * d
+		}
+	}
+
+	mse := sum / float64(len(a.Pix)/4*3)
+	if mse == 0 {
+		return MaxPSNR
+	}
+	return math.Min(MaxPSNR, 10*math.Log10(255*255/mse))
+}
+
+// ssim averages the structural similarity of luma over non-overlapping windows.
+func ssim(a, b *image.NRGBA) float64 {
+	bounds := a.Bounds()
+	lumaA, lumaB := luma(a), luma(b)
+
+	var total float64
+	var windows int
+	for y0 := 0; y0 < bounds.Dy(); y0 += ssimWindow {
+		for x0 := 0; x0 < bounds.Dx(); x0 += ssimWindow {
+			x1, y1 := min(x0+ssimWindow, bounds.Dx()), min(y0+ssimWindow, bounds.Dy())
+			n := float64((x1 - x0) * (y1 - y0))
+
+			var meanA, meanB float64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					meanA += lumaA[y*bounds.Dx()+x]
+					meanB += lumaB[y*bounds.Dx()+x]
+				}
+			}
+			meanA, meanB = meanA/n, meanB/n
+
+			var varA, varB, cov float64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					da := lumaA[y*bounds.Dx()+x] - meanA
+					db := lumaB[y*bounds.Dx()+x] - meanB
+					varA += da * da
+					varB += db * db
+					cov += da * db
+				}
+			}
+			varA, varB, cov = varA/n, varB/n, cov/n
+
+			total += ((2*meanA*meanB + ssimC1) * (2*cov + ssimC2)) /
+				((meanA*meanA + meanB*meanB + ssimC1) * (varA + varB + ssimC2))
+			windows++
+		}
+	}
+
+	return total / float64(windows)
+}
+
+func luma(img *image.NRGBA) []float64 {
+	result := make([]float64, 0, len(img.Pix)/4)
+	for i := 0; i < len(img.Pix); i += 4 {
+		result = append(result, 0.299*float64(img.Pix[i])+0.587*float64(img.Pix[i+1])+0.114*float64(img.Pix[i+2]))
+	}
+	return result
+}
+
+func diffImage(a, b *image.NRGBA) *image.NRGBA {
+	result := image.NewNRGBA(a.Bounds())
+	for i := 0; i < len(a.Pix); i += 4 {
+		for ch := 0; ch < 3; ch++ {
+			d := int(a.Pix[i+ch]) - int(b.Pix[i+ch])
+			if d < 0 {
+				d = -d
+			}
+			result.Pix[i+ch] = uint8(min(255, d*DiffAmplification))
+		}
+		result.Pix[i+3] = 255
+	}
+	return result
+}
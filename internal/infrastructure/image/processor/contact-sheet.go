@@ -0,0 +1,149 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	DefaultContactSheetBackground = vo.Color("#FFFFFF")
+	DefaultContactSheetFormat     = "png"
+	// MaxContactSheetSize limits each side of the composed image.
+	MaxContactSheetSize = 16384
+
+	captionHeight  = 18
+	captionPadding = 4
+)
+
+// ComposeContactSheet lays the images out on a grid row by row. Every image is
+// scaled to fit its cell keeping the aspect ratio and centered in it.
+func (p *Processor) ComposeContactSheet(cells []model.ContactSheetCell, spec model.ContactSheetSpec) (*model.ProcessingResult, error) {
+	const op = opContactSheet
+	start := time.Now()
+
+	if len(cells) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrNoCells)
+	}
+	if spec.Columns <= 0 || spec.CellWidth <= 0 || spec.CellHeight <= 0 || spec.Spacing < 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidGrid)
+	}
+
+	background := DefaultContactSheetBackground
+	if spec.Background != "" {
+		var err error
+		if background, err = vo.NewValidColor(spec.Background); err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", op, ErrInvalidColor, err)
+		}
+	}
+
+	columns := min(spec.Columns, len(cells))
+	rows := (len(cells) + columns - 1) / columns
+	rowHeight := spec.CellHeight
+	if spec.Captions {
+		rowHeight += captionHeight
+	}
+
+	width := columns*spec.CellWidth + (columns+1)*spec.Spacing
+	height := rows*rowHeight + (rows+1)*spec.Spacing
+	if width > MaxContactSheetSize || height > MaxContactSheetSize {
+		return nil, fmt.Errorf("%s: %w: %dx%d", op, ErrContactSheetTooLarge, width, height)
+	}
+
+	sheet := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(background.NRGBA()), image.Point{}, draw.Src)
+
+	textColor := color.Color(color.Black)
+	if lumaOf(background.NRGBA()) < 128 {
+		textColor = color.White
+	}
+
+	for i, cell := range cells {
+		img, _, err := image.Decode(bytes.NewReader(cell.ImageData))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: cell %d: %w", op, ErrImageDecodeFailed, i, err)
+		}
+		bounds := img.Bounds()
+		if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+			return nil, fmt.Errorf("%s: %w: cell %d", op, ErrWrongBounds, i)
+		}
+
+		cellX := spec.Spacing + (i%columns)*(spec.CellWidth+spec.Spacing)
+		cellY := spec.Spacing + (i/columns)*(rowHeight+spec.Spacing)
+
+		scale := min(float64(spec.CellWidth)/float64(bounds.Dx()), float64(spec.CellHeight)/float64(bounds.Dy()))
+		fitWidth := max(1, int(float64(bounds.Dx())*scale))
+		fitHeight := max(1, int(float64(bounds.Dy())*scale))
+		offsetX := cellX + (spec.CellWidth-fitWidth)/2
+		offsetY := cellY + (spec.CellHeight-fitHeight)/2
+
+		draw.BiLinear.Scale(sheet, image.Rect(offsetX, offsetY, offsetX+fitWidth, offsetY+fitHeight), img, bounds, draw.Over, nil)
+
+		if spec.Captions && cell.Caption != "" {
+			drawCaption(sheet, cell.Caption, textColor, cellX, cellY+spec.CellHeight, spec.CellWidth)
+		}
+	}
+
+	format := spec.Format
+	if format == "" {
+		format = DefaultContactSheetFormat
+	}
+	data, err := p.ConvertFormat(sheet, format, spec.Quality)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrFormatConversionFailed, err)
+	}
+
+	result := &model.ProcessingResult{
+		ProcessedData: data,
+		Format:        format,
+		Width:         width,
+		Height:        height,
+		Size:          int64(len(data)),
+	}
+	if err = p.describe(sheet, result); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	result.ProcessingTime = time.Since(start)
+
+	return result, nil
+}
+
+// drawCaption centers the text under the cell, cutting it to the cell width.
+func drawCaption(dst draw.Image, text string, textColor color.Color, x, y, width int) {
+	face := basicfont.Face7x13
+	maxChars := (width - 2*captionPadding) / face.Advance
+	if maxChars <= 0 {
+		return
+	}
+
+	// basicfont only has ASCII glyphs
+	runes := []rune(text)
+	if len(runes) > maxChars {
+		if maxChars > 3 {
+			text = string(runes[:maxChars-3]) + "..."
+		} else {
+			text = string(runes[:maxChars])
+		}
+	}
+
+	drawer := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(textColor),
+		Face: face,
+	}
+	textWidth := drawer.MeasureString(text).Ceil()
+	drawer.Dot = fixed.Point26_6{
+		X: fixed.I(x + (width-textWidth)/2),
+		Y: fixed.I(y + captionPadding + face.Ascent),
+	}
+	drawer.DrawString(text)
+}
@@ -0,0 +1,173 @@
+package processor
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+)
+
+func solidCell(t *testing.T, width, height int, c color.NRGBA) model.ContactSheetCell {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode cell: %v", err)
+	}
+	return model.ContactSheetCell{ImageData: buf.Bytes()}
+}
+
+func TestComposeContactSheetLayout(t *testing.T) {
+	p := New()
+	red := color.NRGBA{R: 255, A: 255}
+
+	tests := []struct {
+		name       string
+		cells      int
+		spec       model.ContactSheetSpec
+		wantWidth  int
+		wantHeight int
+	}{
+		{
+			name:       "grid with spacing",
+			cells:      3,
+			spec:       model.ContactSheetSpec{Columns: 2, CellWidth: 40, CellHeight: 30, Spacing: 5},
+			wantWidth:  2*40 + 3*5,
+			wantHeight: 2*30 + 3*5,
+		},
+		{
+			name:       "no spacing",
+			cells:      4,
+			spec:       model.ContactSheetSpec{Columns: 2, CellWidth: 40, CellHeight: 30},
+			wantWidth:  80,
+			wantHeight: 60,
+		},
+		{
+			name:       "captions",
+			cells:      3,
+			spec:       model.ContactSheetSpec{Columns: 3, CellWidth: 40, CellHeight: 30, Spacing: 2, Captions: true},
+			wantWidth:  3*40 + 4*2,
+			wantHeight: 30 + captionHeight + 2*2,
+		},
+		{
+			name:       "columns capped by cells",
+			cells:      2,
+			spec:       model.ContactSheetSpec{Columns: 5, CellWidth: 40, CellHeight: 30, Spacing: 5},
+			wantWidth:  2*40 + 3*5,
+			wantHeight: 30 + 2*5,
+		},
+	}
+
+	for _, tt := range tests {
+		cells := make([]model.ContactSheetCell, tt.cells)
+		for i := range cells {
+			cells[i] = solidCell(t, 20, 20, red)
+			cells[i].Caption = "cell"
+		}
+
+		result, err := p.ComposeContactSheet(cells, tt.spec)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if result.Width != tt.wantWidth || result.Height != tt.wantHeight {
+			t.Errorf("%s: size %dx%d, want %dx%d", tt.name, result.Width, result.Height, tt.wantWidth, tt.wantHeight)
+		}
+		if result.Format != DefaultContactSheetFormat {
+			t.Errorf("%s: format = %q, want %q", tt.name, result.Format, DefaultContactSheetFormat)
+		}
+
+		decoded, err := png.Decode(bytes.NewReader(result.ProcessedData))
+		if err != nil {
+			t.Errorf("%s: decode: %v", tt.name, err)
+			continue
+		}
+		if bounds := decoded.Bounds(); bounds.Dx() != tt.wantWidth || bounds.Dy() != tt.wantHeight {
+			t.Errorf("%s: encoded size %v, want %dx%d", tt.name, bounds, tt.wantWidth, tt.wantHeight)
+		}
+	}
+}
+
+func TestComposeContactSheetPlacement(t *testing.T) {
+	p := New()
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+
+	// a wide image in a square cell is centered vertically
+	cells := []model.ContactSheetCell{
+		solidCell(t, 40, 20, red),
+		solidCell(t, 40, 40, blue),
+	}
+	spec := model.ContactSheetSpec{Columns: 2, CellWidth: 40, CellHeight: 40, Spacing: 4, Background: "#00FF00"}
+
+	result, err := p.ComposeContactSheet(cells, spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sheet, err := png.Decode(bytes.NewReader(result.ProcessedData))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	green := color.NRGBA{G: 255, A: 255}
+	tests := []struct {
+		name string
+		x, y int
+		want color.NRGBA
+	}{
+		{name: "outer spacing", x: 1, y: 1, want: green},
+		{name: "gap between cells", x: 45, y: 20, want: green},
+		{name: "letterbox above the wide image", x: 24, y: 8, want: green},
+		{name: "wide image center", x: 24, y: 24, want: red},
+		{name: "second cell center", x: 68, y: 24, want: blue},
+	}
+
+	for _, tt := range tests {
+		if got := color.NRGBAModel.Convert(sheet.At(tt.x, tt.y)); got != tt.want {
+			t.Errorf("%s: pixel (%d, %d) = %v, want %v", tt.name, tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestComposeContactSheetInvalid(t *testing.T) {
+	p := New()
+	cells := []model.ContactSheetCell{solidCell(t, 4, 4, color.NRGBA{A: 255})}
+	valid := model.ContactSheetSpec{Columns: 1, CellWidth: 10, CellHeight: 10}
+
+	withSpec := func(change func(*model.ContactSheetSpec)) model.ContactSheetSpec {
+		spec := valid
+		change(&spec)
+		return spec
+	}
+
+	tests := []struct {
+		name    string
+		cells   []model.ContactSheetCell
+		spec    model.ContactSheetSpec
+		wantErr error
+	}{
+		{name: "no cells", spec: valid, wantErr: ErrNoCells},
+		{name: "zero columns", cells: cells, spec: withSpec(func(s *model.ContactSheetSpec) { s.Columns = 0 }), wantErr: ErrInvalidGrid},
+		{name: "zero cell width", cells: cells, spec: withSpec(func(s *model.ContactSheetSpec) { s.CellWidth = 0 }), wantErr: ErrInvalidGrid},
+		{name: "negative spacing", cells: cells, spec: withSpec(func(s *model.ContactSheetSpec) { s.Spacing = -1 }), wantErr: ErrInvalidGrid},
+		{name: "bad background", cells: cells, spec: withSpec(func(s *model.ContactSheetSpec) { s.Background = "green" }), wantErr: ErrInvalidColor},
+		{name: "too wide", cells: cells, spec: withSpec(func(s *model.ContactSheetSpec) { s.CellWidth = MaxContactSheetSize + 1 }), wantErr: ErrContactSheetTooLarge},
+		{name: "undecodable cell", cells: []model.ContactSheetCell{{ImageData: []byte("nope")}}, spec: valid, wantErr: ErrImageDecodeFailed},
+	}
+
+	for _, tt := range tests {
+		if _, err := p.ComposeContactSheet(tt.cells, tt.spec); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
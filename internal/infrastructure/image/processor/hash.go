@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math/bits"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	dHashWidth  = 9
+	dHashHeight = 8
+)
+
+// PerceptualHash decodes the image and returns its difference hash.
+func (p *Processor) PerceptualHash(imageData []byte) (uint64, error) {
+	const op = opPerceptualHash
+
+	if len(imageData) == 0 {
+		return 0, fmt.Errorf("%s: %w", op, ErrEmptyImageData)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w: %w", op, ErrImageDecodeFailed, err)
+	}
+
+	return p.DifferenceHash(img)
+}
+
+// DifferenceHash computes a 64-bit dHash: the image is shrunk to 9x8 grayscale
+// pixels and every bit tells whether a pixel is brighter than its right neighbour.
+func (p *Processor) DifferenceHash(originalImage image.Image) (uint64, error) {
+	const op = opDifferenceHash
+
+	if originalImage.Bounds().Dx() <= 0 || originalImage.Bounds().Dy() <= 0 {
+		return 0, fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+
+	gray := image.NewGray(image.Rect(0, 0, dHashWidth, dHashHeight))
+	draw.BiLinear.Scale(gray, gray.Bounds(), originalImage, originalImage.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < dHashHeight; y++ {
+		for x := 0; x < dHashWidth-1; x++ {
+			hash <<= 1
+			if gray.GrayAt(x, y).Y > gray.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash, nil
+}
+
+// HammingDistance returns the number of differing bits of two perceptual hashes.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,118 @@
+package processor
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gradientImage is a smooth diagonal gradient, so that small edits keep its
+// structure and the hash close.
+func gradientImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8((x*255/width + y*255/height) / 2)
+			img.Set(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	return img
+}
+
+func TestPerceptualHashDistance(t *testing.T) {
+	p := New()
+	img := gradientImage(128, 96)
+
+	pngData, err := p.ConvertFormat(img, "png", 0)
+	if err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	jpegData, err := p.ConvertFormat(img, "jpeg", 70)
+	if err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	resized, err := p.Resize(img, 64, 48)
+	if err != nil {
+		t.Fatalf("resize: %v", err)
+	}
+	resizedData, err := p.ConvertFormat(resized, "png", 0)
+	if err != nil {
+		t.Fatalf("encode resized: %v", err)
+	}
+	flipped, err := p.ConvertFormat(mirror(img), "png", 0)
+	if err != nil {
+		t.Fatalf("encode flipped: %v", err)
+	}
+
+	base, err := p.PerceptualHash(pngData)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		maxDist int
+		minDist int
+	}{
+		{name: "identical", data: pngData, maxDist: 0},
+		{name: "re-encoded as jpeg", data: jpegData, maxDist: 4},
+		{name: "resized", data: resizedData, maxDist: 4},
+		{name: "mirrored", data: flipped, minDist: 16, maxDist: 64},
+	}
+
+	for _, tt := range tests {
+		hash, err := p.PerceptualHash(tt.data)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		dist := HammingDistance(base, hash)
+		if dist < tt.minDist || dist > tt.maxDist {
+			t.Errorf("%s: distance = %d, want in [%d, %d]", tt.name, dist, tt.minDist, tt.maxDist)
+		}
+	}
+}
+
+func TestPerceptualHashEmpty(t *testing.T) {
+	p := New()
+
+	if _, err := p.PerceptualHash(nil); !errors.Is(err, ErrEmptyImageData) {
+		t.Errorf("error = %v, want %v", err, ErrEmptyImageData)
+	}
+	if _, err := p.PerceptualHash([]byte("not an image")); !errors.Is(err, ErrImageDecodeFailed) {
+		t.Errorf("error = %v, want %v", err, ErrImageDecodeFailed)
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b uint64
+		want int
+	}{
+		{name: "equal", a: 0xdeadbeef, b: 0xdeadbeef, want: 0},
+		{name: "one bit", a: 0, b: 1, want: 1},
+		{name: "high bit", a: 1 << 63, b: 0, want: 1},
+		{name: "all bits", a: 0, b: ^uint64(0), want: 64},
+		{name: "nibble", a: 0xf0, b: 0x0f, want: 8},
+	}
+
+	for _, tt := range tests {
+		if got := HammingDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: distance = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func mirror(img *image.NRGBA) *image.NRGBA {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			out.Set(bounds.Max.X-1-x+bounds.Min.X, y, img.At(x, y))
+		}
+	}
+	return out
+}
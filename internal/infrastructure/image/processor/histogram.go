@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+)
+
+const (
+	// middleGray is the sRGB value of 18% gray used as the exposure reference.
+	middleGray = 118.0
+	// ExposureTolerance is the EV offset beyond which an image is flagged.
+	ExposureTolerance = 1.0
+	// ClippingTolerance is the share of clipped pixels in percent beyond which an image is flagged.
+	ClippingTolerance = 5.0
+)
+
+// Histogram computes per-channel and luminance histograms with basic
+// statistics. Fully transparent pixels are skipped.
+func (p *Processor) Histogram(imageData []byte) (*model.ImageStatistics, error) {
+	const op = opHistogram
+
+	if len(imageData) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrEmptyImageData)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrImageDecodeFailed, err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+
+	var red, green, blue, luminance [256]int
+	var pixels int
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				continue
+			}
+			red[c.R]++
+			green[c.G]++
+			blue[c.B]++
+			luminance[lumaOf(c)]++
+			pixels++
+		}
+	}
+
+	stats := &model.ImageStatistics{
+		Pixels:    pixels,
+		Red:       channelStatistics(red, pixels),
+		Green:     channelStatistics(green, pixels),
+		Blue:      channelStatistics(blue, pixels),
+		Luminance: channelStatistics(luminance, pixels),
+	}
+	if pixels == 0 {
+		stats.ExposureLabel = model.ExposureNormal
+		return stats, nil
+	}
+
+	stats.Exposure = math.Log2(math.Max(stats.Luminance.Mean, 1) / middleGray)
+	switch {
+	case stats.Exposure < -ExposureTolerance || stats.Luminance.ClippedShadows > ClippingTolerance:
+		stats.ExposureLabel = model.ExposureUnder
+	case stats.Exposure > ExposureTolerance || stats.Luminance.ClippedHighlights > ClippingTolerance:
+		stats.ExposureLabel = model.ExposureOver
+	default:
+		stats.ExposureLabel = model.ExposureNormal
+	}
+
+	return stats, nil
+}
+
+func channelStatistics(histogram [256]int, pixels int) model.ChannelStatistics {
+	stats := model.ChannelStatistics{Histogram: histogram[:]}
+	if pixels == 0 {
+		return stats
+	}
+
+	var sum float64
+	for v, count := range histogram {
+		sum += float64(v * count)
+	}
+	stats.Mean = sum / float64(pixels)
+
+	var variance float64
+	for v, count := range histogram {
+		d := float64(v) - stats.Mean
+		variance += d * d * float64(count)
+	}
+	stats.StdDev = math.Sqrt(variance / float64(pixels))
+
+	stats.ClippedShadows = float64(histogram[0]) / float64(pixels) * 100
+	stats.ClippedHighlights = float64(histogram[255]) / float64(pixels) * 100
+
+	return stats
+}
+
+func lumaOf(c color.NRGBA) uint8 {
+	return uint8(math.Round(0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)))
+}
@@ -2,6 +2,7 @@ package processor
 
 import (
 	"bytes"
+	"cmp"
 	"errors"
 	"fmt"
 	"image"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
 	"golang.org/x/image/draw"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
@@ -25,6 +27,7 @@ const (
 	DefaultQuality       = 85
 	DefaultWatermarkX    = 0
 	DefaultWatermarkY    = 0
+	DefaultBorderColor   = vo.Color("#000000")
 )
 
 var (
@@ -39,14 +42,52 @@ var (
 	ErrWatermarkFailed        = errors.New("watermark adding failed")
 	ErrFormatConversionFailed = errors.New("format conversion failed")
 	ErrImageEncodeFailed      = errors.New("failed to encode image")
+	ErrInvalidMask            = errors.New("invalid mask: supported masks are circle, ellipse")
+	ErrInvalidRadius          = errors.New("invalid radius: must be non-negative")
+	ErrInvalidBorderWidth     = errors.New("invalid border width: must be positive")
+	ErrInvalidColor           = errors.New("invalid color: must be #RRGGBB or #RRGGBBAA")
+	ErrMaskFailed             = errors.New("mask applying failed")
+	ErrBorderFailed           = errors.New("border adding failed")
+	ErrInvalidComponents      = errors.New("invalid blurhash components: must be between 1 and 9")
+	ErrPlaceholderFailed      = errors.New("placeholder creation failed")
+	ErrInvalidPaletteSize     = errors.New("invalid palette size: must be positive")
+	ErrPaletteFailed          = errors.New("palette extraction failed")
+	ErrInvalidClipPercent     = errors.New("invalid clip percent: must be between 0 and 50")
+	ErrAutoAdjustFailed       = errors.New("auto adjustment failed")
+	ErrInvalidGrid            = errors.New("invalid grid: columns and cell size must be positive, spacing non-negative")
+	ErrNoCells                = errors.New("no images to compose")
+	ErrContactSheetTooLarge   = errors.New("contact sheet is too large")
+	ErrInvalidCanvas          = errors.New("invalid canvas size")
+	ErrInvalidSlot            = errors.New("invalid slot: size must be positive")
+	ErrInvalidFit             = errors.New("invalid fit: supported fits are cover, contain")
+	ErrInvalidAlign           = errors.New("invalid align: supported aligns are left, center, right")
+	ErrUnknownFont            = errors.New("unknown font: supported fonts are regular, bold, italic, mono")
+	ErrInvalidMaxBytes        = errors.New("invalid max bytes: must be positive")
+	ErrBudgetUnreachable      = errors.New("image does not fit into max bytes")
 )
 
 const (
-	opProcessImage    = "image.Processor.Process"
-	opResize          = "image.Processor.Resize"
-	opCreateThumbnail = "image.Processor.CreateThumbnail"
-	opAddWatermark    = "image.Processor.AddWatermark"
-	opConvertFormat   = "image.Processor.ConvertFormat"
+	opProcessImage       = "image.Processor.Process"
+	opResize             = "image.Processor.Resize"
+	opCreateThumbnail    = "image.Processor.CreateThumbnail"
+	opAddWatermark       = "image.Processor.AddWatermark"
+	opConvertFormat      = "image.Processor.ConvertFormat"
+	opRoundCorners       = "image.Processor.RoundCorners"
+	opApplyMask          = "image.Processor.ApplyMask"
+	opAddBorder          = "image.Processor.AddBorder"
+	opBlurHash           = "image.Processor.BlurHash"
+	opCreateLQIP         = "image.Processor.CreateLQIP"
+	opExtractPalette     = "image.Processor.ExtractPalette"
+	opPerceptualHash     = "image.Processor.PerceptualHash"
+	opDifferenceHash     = "image.Processor.DifferenceHash"
+	opCompare            = "image.Processor.Compare"
+	opHistogram          = "image.Processor.Histogram"
+	opAutoLevel          = "image.Processor.AutoLevel"
+	opAutoWhiteBalance   = "image.Processor.AutoWhiteBalance"
+	opContactSheet       = "image.Processor.ComposeContactSheet"
+	opRenderCard         = "image.Processor.RenderCard"
+	opEncodeWithinBudget = "image.Processor.EncodeWithinBudget"
+	opInspect            = "image.Processor.Inspect"
 )
 
 type Processor struct{}
@@ -68,6 +109,20 @@ func (p *Processor) ProcessImage(imageData []byte, opts model.ProcessingOptions)
 		return nil, fmt.Errorf("%s: %w: %w", op, ErrImageDecodeFailed, err)
 	}
 
+	if opts.AutoWhiteBalance {
+		img, err = p.AutoWhiteBalance(img)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", op, ErrAutoAdjustFailed, err)
+		}
+	}
+
+	if opts.AutoLevel {
+		img, err = p.AutoLevel(img, DefaultLevelClipPercent)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", op, ErrAutoAdjustFailed, err)
+		}
+	}
+
 	if opts.Width > 0 || opts.Height > 0 {
 		img, err = p.Resize(img, opts.Width, opts.Height)
 		if err != nil {
@@ -93,23 +148,94 @@ func (p *Processor) ProcessImage(imageData []byte, opts model.ProcessingOptions)
 		}
 	}
 
+	outline := Outline(RoundedRect{Bounds: image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy())})
+	switch {
+	case opts.Mask != "":
+		img, outline, err = p.ApplyMask(img, opts.Mask)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", op, ErrMaskFailed, err)
+		}
+	case opts.CornerRadius > 0:
+		img, outline, err = p.RoundCorners(img, opts.CornerRadius)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", op, ErrMaskFailed, err)
+		}
+	}
+
+	if opts.BorderWidth > 0 {
+		borderColor := DefaultBorderColor
+		if opts.BorderColor != "" {
+			if borderColor, err = vo.NewValidColor(opts.BorderColor); err != nil {
+				return nil, fmt.Errorf("%s: %w: %w", op, ErrInvalidColor, err)
+			}
+		}
+		img, err = p.AddBorder(img, outline, opts.BorderWidth, borderColor.NRGBA())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", op, ErrBorderFailed, err)
+		}
+	}
+
 	outputFormat := opts.Format
 	if outputFormat == "" {
 		outputFormat = format
 	}
-	processedImageData, err := p.ConvertFormat(img, outputFormat, opts.Quality)
+	var processedImageData []byte
+	var quality int
+	if opts.MaxBytes > 0 {
+		processedImageData, img, quality, err = p.EncodeWithinBudget(img, outputFormat, opts.Quality, opts.MaxBytes)
+	} else {
+		processedImageData, err = p.ConvertFormat(img, outputFormat, opts.Quality)
+		if isJPEG(outputFormat) {
+			quality = cmp.Or(opts.Quality, DefaultQuality)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w: %w", op, ErrFormatConversionFailed, err)
 	}
 
-	return &model.ProcessingResult{
-		ProcessedData:  processedImageData,
-		Format:         outputFormat,
-		Width:          img.Bounds().Dx(),
-		Height:         img.Bounds().Dy(),
-		Size:           int64(len(processedImageData)),
-		ProcessingTime: time.Since(start),
-	}, nil
+	result := &model.ProcessingResult{
+		ProcessedData: processedImageData,
+		Format:        outputFormat,
+		Width:         img.Bounds().Dx(),
+		Height:        img.Bounds().Dy(),
+		Size:          int64(len(processedImageData)),
+		Quality:       quality,
+	}
+	if err = p.describe(img, result); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	result.ProcessingTime = time.Since(start)
+
+	return result, nil
+}
+
+// describe fills the placeholders and the palette of the result.
+func (p *Processor) describe(img image.Image, result *model.ProcessingResult) error {
+	blurHash, err := p.BlurHash(img, BlurHashComponentsX, BlurHashComponentsY)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPlaceholderFailed, err)
+	}
+	lqip, err := p.CreateLQIP(img, DefaultLQIPWidth)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPlaceholderFailed, err)
+	}
+
+	palette, err := p.ExtractPalette(img, DefaultPaletteSize)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPaletteFailed, err)
+	}
+	paletteHex := make([]string, 0, len(palette))
+	for _, c := range palette {
+		paletteHex = append(paletteHex, vo.NewColorFromNRGBA(c.Color).String())
+	}
+
+	result.BlurHash = blurHash
+	result.LQIP = lqip
+	result.Palette = paletteHex
+	if len(paletteHex) > 0 {
+		result.DominantColor = paletteHex[0]
+	}
+	return nil
 }
 
 func (p *Processor) Resize(originalImage image.Image, newWidth int, newHeight int) (image.Image, error) {
@@ -146,11 +272,11 @@ func (p *Processor) CreateThumbnail(originalImage image.Image, size int) (image.
 	if originalBounds.Dx() >= originalBounds.Dy() {
 		newWidth = size
 		ratio := float64(size) / float64(originalBounds.Dx())
-		newHeight = int(float64(originalBounds.Dy()) * ratio)
+		newHeight = max(1, int(float64(originalBounds.Dy())*ratio))
 	} else {
 		newHeight = size
 		ratio := float64(size) / float64(originalBounds.Dy())
-		newWidth = int(float64(originalBounds.Dx()) * ratio)
+		newWidth = max(1, int(float64(originalBounds.Dx())*ratio))
 	}
 
 	return p.Resize(originalImage, newWidth, newHeight)
@@ -214,7 +340,7 @@ func (p *Processor) ConvertFormat(originalImage image.Image, format string, qual
 
 	switch strings.ToLower(format) {
 	case "jpeg", "jpg":
-		err = jpeg.Encode(&buf, originalImage, &jpeg.Options{Quality: quality})
+		err = jpeg.Encode(&buf, flatten(originalImage, color.White), &jpeg.Options{Quality: quality})
 	case "png":
 		err = png.Encode(&buf, originalImage)
 	case "gif":
@@ -234,3 +360,93 @@ func (p *Processor) ConvertFormat(originalImage image.Image, format string, qual
 	}
 	return buf.Bytes(), nil
 }
+
+func (p *Processor) RoundCorners(originalImage image.Image, radius int) (image.Image, Outline, error) {
+	const op = opRoundCorners
+
+	originalBounds := originalImage.Bounds()
+	if originalBounds.Dx() <= 0 || originalBounds.Dy() <= 0 {
+		return nil, nil, fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+	if radius < 0 {
+		return nil, nil, fmt.Errorf("%s: %w", op, ErrInvalidRadius)
+	}
+
+	outline := RoundedRect{
+		Bounds: image.Rect(0, 0, originalBounds.Dx(), originalBounds.Dy()),
+		Radius: float64(radius),
+	}
+	return applyOutline(originalImage, outline), outline, nil
+}
+
+// ApplyMask crops the image to the given shape. The circle mask first crops
+// the centered square, the ellipse mask is inscribed into the whole image.
+func (p *Processor) ApplyMask(originalImage image.Image, mask string) (image.Image, Outline, error) {
+	const op = opApplyMask
+
+	originalBounds := originalImage.Bounds()
+	if originalBounds.Dx() <= 0 || originalBounds.Dy() <= 0 {
+		return nil, nil, fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+
+	switch strings.ToLower(mask) {
+	case MaskCircle:
+		side := min(originalBounds.Dx(), originalBounds.Dy())
+		offset := image.Pt((originalBounds.Dx()-side)/2, (originalBounds.Dy()-side)/2)
+
+		square := image.NewNRGBA(image.Rect(0, 0, side, side))
+		draw.Draw(square, square.Bounds(), originalImage, originalBounds.Min.Add(offset), draw.Src)
+
+		outline := Ellipse{Bounds: square.Bounds()}
+		return applyOutline(square, outline), outline, nil
+	case MaskEllipse:
+		outline := Ellipse{Bounds: image.Rect(0, 0, originalBounds.Dx(), originalBounds.Dy())}
+		return applyOutline(originalImage, outline), outline, nil
+	default:
+		return nil, nil, fmt.Errorf("%s: %w: %s", op, ErrInvalidMask, mask)
+	}
+}
+
+// AddBorder paints a solid border of the given width along the inner side of the outline.
+func (p *Processor) AddBorder(originalImage image.Image, outline Outline, width int, borderColor color.Color) (image.Image, error) {
+	const op = opAddBorder
+
+	originalBounds := originalImage.Bounds()
+	if originalBounds.Dx() <= 0 || originalBounds.Dy() <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+	if width <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidBorderWidth)
+	}
+
+	src := color.NRGBAModel.Convert(borderColor).(color.NRGBA)
+	inner := outline.Inset(float64(width))
+
+	resultImage := image.NewNRGBA(image.Rect(0, 0, originalBounds.Dx(), originalBounds.Dy()))
+	draw.Draw(resultImage, resultImage.Bounds(), originalImage, originalBounds.Min, draw.Src)
+
+	for y := 0; y < originalBounds.Dy(); y++ {
+		for x := 0; x < originalBounds.Dx(); x++ {
+			alpha := coverage(outline, x, y) - coverage(inner, x, y)
+			if alpha <= 0 {
+				continue
+			}
+			resultImage.SetNRGBA(x, y, blendNRGBA(resultImage.NRGBAAt(x, y), src, alpha))
+		}
+	}
+
+	return resultImage, nil
+}
+
+// flatten composes images with transparency onto a solid background for formats without alpha.
+func flatten(img image.Image, background color.Color) image.Image {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && opaque.Opaque() {
+		return img
+	}
+
+	bounds := img.Bounds()
+	resultImage := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(resultImage, resultImage.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(resultImage, resultImage.Bounds(), img, bounds.Min, draw.Over)
+	return resultImage
+}
@@ -0,0 +1,29 @@
+package processor
+
+import (
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+)
+
+// Inspect reads only the header of the image and returns its format and
+// dimensions. Formats without a registered decoder are rejected.
+func (p *Processor) Inspect(reader io.Reader) (*model.ImageInfo, error) {
+	const op = opInspect
+
+	config, format, err := image.DecodeConfig(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrImageDecodeFailed, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+
+	return &model.ImageInfo{
+		Format: format,
+		Width:  config.Width,
+		Height: config.Height,
+	}, nil
+}
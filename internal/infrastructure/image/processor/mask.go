@@ -0,0 +1,112 @@
+package processor
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+const (
+	MaskCircle  = "circle"
+	MaskEllipse = "ellipse"
+)
+
+// Outline describes the visible shape of an image. Distance is negative inside
+// the shape, positive outside and zero on the edge.
+type Outline interface {
+	Distance(x, y float64) float64
+	Inset(d float64) Outline
+}
+
+type RoundedRect struct {
+	Bounds image.Rectangle
+	Radius float64
+}
+
+func (r RoundedRect) Distance(x, y float64) float64 {
+	hw := float64(r.Bounds.Dx()) / 2
+	hh := float64(r.Bounds.Dy()) / 2
+	radius := math.Min(r.Radius, math.Min(hw, hh))
+
+	qx := math.Abs(x-(float64(r.Bounds.Min.X)+hw)) - (hw - radius)
+	qy := math.Abs(y-(float64(r.Bounds.Min.Y)+hh)) - (hh - radius)
+
+	outside := math.Hypot(math.Max(qx, 0), math.Max(qy, 0))
+	inside := math.Min(math.Max(qx, qy), 0)
+	return outside + inside - radius
+}
+
+func (r RoundedRect) Inset(d float64) Outline {
+	return RoundedRect{
+		Bounds: r.Bounds.Inset(int(d)),
+		Radius: math.Max(r.Radius-d, 0),
+	}
+}
+
+type Ellipse struct {
+	Bounds image.Rectangle
+}
+
+// Distance uses the first-order approximation of the ellipse distance, which
+// is exact on the edge and good enough for one-pixel antialiasing.
+func (e Ellipse) Distance(x, y float64) float64 {
+	a := float64(e.Bounds.Dx()) / 2
+	b := float64(e.Bounds.Dy()) / 2
+	if a <= 0 || b <= 0 {
+		return math.Inf(1)
+	}
+
+	px := x - (float64(e.Bounds.Min.X) + a)
+	py := y - (float64(e.Bounds.Min.Y) + b)
+
+	k0 := math.Hypot(px/a, py/b)
+	k1 := math.Hypot(px/(a*a), py/(b*b))
+	if k1 == 0 {
+		return -math.Min(a, b)
+	}
+	return k0 * (k0 - 1) / k1
+}
+
+func (e Ellipse) Inset(d float64) Outline {
+	return Ellipse{Bounds: e.Bounds.Inset(int(d))}
+}
+
+// coverage returns the antialiased share of the pixel (x, y) that lies inside the outline.
+func coverage(o Outline, x, y int) float64 {
+	d := o.Distance(float64(x)+0.5, float64(y)+0.5)
+	return math.Max(0, math.Min(1, 0.5-d))
+}
+
+func applyOutline(src image.Image, o Outline) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.NRGBAModel.Convert(src.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			c.A = uint8(float64(c.A) * coverage(o, x, y))
+			dst.SetNRGBA(x, y, c)
+		}
+	}
+
+	return dst
+}
+
+func blendNRGBA(dst, src color.NRGBA, alpha float64) color.NRGBA {
+	srcA := float64(src.A) / 255 * alpha
+	dstA := float64(dst.A) / 255
+	outA := srcA + dstA*(1-srcA)
+	if outA == 0 {
+		return color.NRGBA{}
+	}
+
+	mix := func(s, d uint8) uint8 {
+		return uint8((float64(s)*srcA + float64(d)*dstA*(1-srcA)) / outA)
+	}
+	return color.NRGBA{
+		R: mix(src.R, dst.R),
+		G: mix(src.G, dst.G),
+		B: mix(src.B, dst.B),
+		A: uint8(outA * 255),
+	}
+}
@@ -0,0 +1,140 @@
+package processor
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+)
+
+const (
+	DefaultPaletteSize = 5
+
+	// paletteSampleSize limits the image the palette is extracted from.
+	paletteSampleSize = 100
+	// paletteMinAlpha skips mostly transparent pixels, e.g. masked corners.
+	paletteMinAlpha = 128
+)
+
+type PaletteColor struct {
+	Color  color.NRGBA
+	Weight float64
+}
+
+// ExtractPalette returns up to size colors found with the median-cut
+// algorithm, ordered by their share of the image. The first one is dominant.
+func (p *Processor) ExtractPalette(originalImage image.Image, size int) ([]PaletteColor, error) {
+	const op = opExtractPalette
+
+	originalBounds := originalImage.Bounds()
+	if originalBounds.Dx() <= 0 || originalBounds.Dy() <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidPaletteSize)
+	}
+
+	sample := originalImage
+	if originalBounds.Dx() > paletteSampleSize || originalBounds.Dy() > paletteSampleSize {
+		var err error
+		if sample, err = p.CreateThumbnail(originalImage, paletteSampleSize); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	bounds := sample.Bounds()
+	pixels := make([]color.NRGBA, 0, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(sample.At(x, y)).(color.NRGBA)
+			if c.A >= paletteMinAlpha {
+				pixels = append(pixels, c)
+			}
+		}
+	}
+	if len(pixels) == 0 {
+		return []PaletteColor{}, nil
+	}
+
+	boxes := [][]color.NRGBA{pixels}
+	for len(boxes) < size {
+		idx, channel, spread := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			if ch, sp := widestChannel(box); sp > spread {
+				idx, channel, spread = i, ch, sp
+			}
+		}
+		if idx < 0 {
+			break
+		}
+
+		box := boxes[idx]
+		slices.SortFunc(box, func(a, b color.NRGBA) int {
+			return int(channelOf(a, channel)) - int(channelOf(b, channel))
+		})
+		mid := len(box) / 2
+		boxes = append(boxes[:idx], append([][]color.NRGBA{box[:mid], box[mid:]}, boxes[idx+1:]...)...)
+	}
+
+	palette := make([]PaletteColor, 0, len(boxes))
+	for _, box := range boxes {
+		var r, g, b int
+		for _, c := range box {
+			r, g, b = r+int(c.R), g+int(c.G), b+int(c.B)
+		}
+		average := color.NRGBA{R: uint8(r / len(box)), G: uint8(g / len(box)), B: uint8(b / len(box)), A: 255}
+		weight := float64(len(box)) / float64(len(pixels))
+
+		// flat areas are split into several boxes of the same color
+		if idx := slices.IndexFunc(palette, func(pc PaletteColor) bool { return pc.Color == average }); idx >= 0 {
+			palette[idx].Weight += weight
+			continue
+		}
+		palette = append(palette, PaletteColor{Color: average, Weight: weight})
+	}
+	slices.SortStableFunc(palette, func(a, b PaletteColor) int {
+		switch {
+		case a.Weight > b.Weight:
+			return -1
+		case a.Weight < b.Weight:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return palette, nil
+}
+
+func widestChannel(box []color.NRGBA) (int, int) {
+	lo := [3]uint8{255, 255, 255}
+	hi := [3]uint8{}
+	for _, c := range box {
+		for ch := 0; ch < 3; ch++ {
+			v := channelOf(c, ch)
+			lo[ch], hi[ch] = min(lo[ch], v), max(hi[ch], v)
+		}
+	}
+
+	channel, spread := 0, 0
+	for ch := 0; ch < 3; ch++ {
+		if sp := int(hi[ch]) - int(lo[ch]); sp > spread {
+			channel, spread = ch, sp
+		}
+	}
+	return channel, spread
+}
+
+func channelOf(c color.NRGBA, channel int) uint8 {
+	switch channel {
+	case 0:
+		return c.R
+	case 1:
+		return c.G
+	default:
+		return c.B
+	}
+}
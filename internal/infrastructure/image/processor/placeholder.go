@@ -0,0 +1,189 @@
+package processor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+)
+
+const (
+	DefaultLQIPWidth    = 16
+	BlurHashComponentsX = 4
+	BlurHashComponentsY = 3
+
+	// blurHashSampleSize limits the image the hash is computed from, the
+	// result is visually the same as for the full-size image.
+	blurHashSampleSize = 64
+	base83Chars        = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+)
+
+// BlurHash encodes the image into a https://blurha.sh string.
+func (p *Processor) BlurHash(originalImage image.Image, componentsX, componentsY int) (string, error) {
+	const op = opBlurHash
+
+	originalBounds := originalImage.Bounds()
+	if originalBounds.Dx() <= 0 || originalBounds.Dy() <= 0 {
+		return "", fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+	if componentsX < 1 || componentsX > 9 || componentsY < 1 || componentsY > 9 {
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidComponents)
+	}
+
+	sample := originalImage
+	if longest := max(originalBounds.Dx(), originalBounds.Dy()); longest > blurHashSampleSize {
+		// a side of an extreme aspect ratio would be scaled down to nothing
+		width := max(1, originalBounds.Dx()*blurHashSampleSize/longest)
+		height := max(1, originalBounds.Dy()*blurHashSampleSize/longest)
+		var err error
+		if sample, err = p.Resize(originalImage, width, height); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if sample.Bounds().Dx() <= 0 || sample.Bounds().Dy() <= 0 {
+		return "", fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+
+	factors := blurHashFactors(sample, componentsX, componentsY)
+	dc, ac := factors[0], factors[1:]
+
+	var hash strings.Builder
+	hash.WriteString(encode83((componentsX-1)+(componentsY-1)*9, 1))
+
+	maxValue := 1.0
+	if len(ac) > 0 {
+		actualMax := 0.0
+		for _, f := range ac {
+			actualMax = math.Max(actualMax, finite(math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2])))))
+		}
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maxValue = float64(quantisedMax+1) / 166
+		hash.WriteString(encode83(quantisedMax, 1))
+	} else {
+		hash.WriteString(encode83(0, 1))
+	}
+
+	hash.WriteString(encode83(
+		linearToSRGB(dc[0])<<16+linearToSRGB(dc[1])<<8+linearToSRGB(dc[2]),
+		4,
+	))
+	for _, f := range ac {
+		quant := func(v float64) int {
+			return int(math.Max(0, math.Min(18, finite(math.Floor(signPow(v/maxValue, 0.5)*9+9.5)))))
+		}
+		hash.WriteString(encode83(quant(f[0])*19*19+quant(f[1])*19+quant(f[2]), 2))
+	}
+
+	return hash.String(), nil
+}
+
+// CreateLQIP renders a tiny PNG preview of the given width as a data URI.
+func (p *Processor) CreateLQIP(originalImage image.Image, width int) (string, error) {
+	const op = opCreateLQIP
+
+	originalBounds := originalImage.Bounds()
+	if originalBounds.Dx() <= 0 || originalBounds.Dy() <= 0 {
+		return "", fmt.Errorf("%s: %w", op, ErrWrongBounds)
+	}
+	if width <= 0 {
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidDimensions)
+	}
+
+	height := max(1, originalBounds.Dy()*width/originalBounds.Dx())
+	preview, err := p.Resize(originalImage, width, height)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, preview); err != nil {
+		return "", fmt.Errorf("%s: %w: %w", op, ErrImageEncodeFailed, err)
+	}
+
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func blurHashFactors(img image.Image, componentsX, componentsY int) [][3]float64 {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	pixels := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			pixels[y*width+x] = [3]float64{sRGBToLinear(c.R), sRGBToLinear(c.G), sRGBToLinear(c.B)}
+		}
+	}
+
+	factors := make([][3]float64, 0, componentsX*componentsY)
+	for j := 0; j < componentsY; j++ {
+		for i := 0; i < componentsX; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+
+			var factor [3]float64
+			for y := 0; y < height; y++ {
+				basisY := math.Cos(math.Pi * float64(j) * float64(y) / float64(height))
+				for x := 0; x < width; x++ {
+					basis := basisY * math.Cos(math.Pi*float64(i)*float64(x)/float64(width))
+					px := pixels[y*width+x]
+					factor[0] += basis * px[0]
+					factor[1] += basis * px[1]
+					factor[2] += basis * px[2]
+				}
+			}
+
+			scale := normalisation / float64(width*height)
+			factors = append(factors, [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale})
+		}
+	}
+
+	return factors
+}
+
+// encode83 writes the value in base 83; a negative value, which a valid
+// hash never holds, is encoded as 0 rather than indexing out of range.
+func encode83(value, length int) string {
+	value = max(0, value)
+	result := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		result[i] = base83Chars[value%83]
+		value /= 83
+	}
+	return string(result)
+}
+
+func sRGBToLinear(value uint8) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, finite(value)))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
+
+// finite replaces NaN and infinities with 0, so that a degenerate factor
+// never reaches the integer conversions of the encoder.
+func finite(value float64) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,35 @@
+package processor
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestBlurHashExtremeAspectRatio(t *testing.T) {
+	p := New()
+
+	for _, size := range []image.Point{{X: 6400, Y: 50}, {X: 50, Y: 6400}, {X: 10000, Y: 1}} {
+		img := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+		for y := 0; y < size.Y; y++ {
+			for x := 0; x < size.X; x++ {
+				img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+			}
+		}
+
+		hash, err := p.BlurHash(img, BlurHashComponentsX, BlurHashComponentsY)
+		if err != nil {
+			t.Fatalf("%dx%d: unexpected error: %v", size.X, size.Y, err)
+		}
+		// size flag, max AC, DC and two characters per AC component
+		if want := 1 + 1 + 4 + 2*(BlurHashComponentsX*BlurHashComponentsY-1); len(hash) != want {
+			t.Errorf("%dx%d: hash %q has length %d, want %d", size.X, size.Y, hash, len(hash), want)
+		}
+	}
+}
+
+func TestEncode83Negative(t *testing.T) {
+	if got := encode83(-18, 2); got != "00" {
+		t.Errorf("encode83(-18, 2) = %q, want %q", got, "00")
+	}
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/config"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/image/fetcher"
+)
+
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	// maxDrainSize is how much of a response body is read so that the
+	// connection can be reused
+	maxDrainSize = 4 << 10
+)
+
+var ErrUnexpectedStatus = errors.New("unexpected response status")
+
+// Sender posts webhook payloads signed with HMAC-SHA256. The receiver
+// verifies the X-Webhook-Signature header, which is
+// "sha256=" + hex(HMAC(secret, timestamp + "." + body)), and rejects old
+// timestamps to prevent replays.
+type Sender struct {
+	client *http.Client
+	secret []byte
+}
+
+func New(cfg config.Webhook) *Sender {
+	if cfg.Secret == "" {
+		panic("webhook secret is required")
+	}
+
+	return &Sender{
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: fetcher.NewTransport(cfg.AllowedNetworks, cfg.Timeout),
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				// a redirect would resend the signed payload to another host
+				return http.ErrUseLastResponse
+			},
+		},
+		secret: []byte(cfg.Secret),
+	}
+}
+
+func (s *Sender) Send(ctx context.Context, delivery *model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "image-processor-webhook")
+	req.Header.Set(HeaderID, delivery.ID.String())
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, s.sign(timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("send: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (s *Sender) sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/config"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/image/fetcher"
+	"github.com/google/uuid"
+)
+
+func TestSign(t *testing.T) {
+	s := &Sender{secret: []byte("secret")}
+
+	got := s.sign("1700000000", []byte(`{"event":"image.completed"}`))
+	want := "sha256=d07f56e0d5b9620e6c1e32315fb96a851b0ca700525431f55c8b3eff97e46a83"
+	if got != want {
+		t.Errorf("sign = %s, want %s", got, want)
+	}
+}
+
+func TestSendSignsPayload(t *testing.T) {
+	const secret = "secret"
+	payload := []byte(`{"event":"image.completed","image_id":"42"}`)
+
+	var (
+		gotBody      []byte
+		gotTimestamp string
+		gotSignature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotTimestamp = r.Header.Get(HeaderTimestamp)
+		gotSignature = r.Header.Get(HeaderSignature)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s := New(config.Webhook{
+		Secret:          secret,
+		Timeout:         5 * time.Second,
+		AllowedNetworks: []string{"127.0.0.1"},
+	})
+	statusCode, err := s.Send(context.Background(), &model.WebhookDelivery{
+		ID:      uuid.New(),
+		URL:     server.URL,
+		Event:   "image.completed",
+		Payload: payload,
+	})
+	if err != nil || statusCode != http.StatusNoContent {
+		t.Fatalf("status = %d, error = %v", statusCode, err)
+	}
+
+	// verified the way the receiver does
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(gotTimestamp + "."))
+	mac.Write(gotBody)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); !hmac.Equal([]byte(gotSignature), []byte(want)) {
+		t.Errorf("signature = %s, want %s", gotSignature, want)
+	}
+	if string(gotBody) != string(payload) {
+		t.Errorf("body = %s, want %s", gotBody, payload)
+	}
+}
+
+func TestSendRefusesLoopback(t *testing.T) {
+	var hit atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { hit.Store(true) }))
+	defer server.Close()
+
+	s := New(config.Webhook{Secret: "secret", Timeout: 5 * time.Second})
+	if _, err := s.Send(context.Background(), &model.WebhookDelivery{
+		ID:      uuid.New(),
+		URL:     server.URL,
+		Payload: []byte(`{}`),
+	}); !errors.Is(err, fetcher.ErrForbiddenAddress) {
+		t.Errorf("error = %v, want %v", err, fetcher.ErrForbiddenAddress)
+	}
+	if hit.Load() {
+		t.Error("the loopback server was reached")
+	}
+}
+
+func TestSendDoesNotFollowRedirects(t *testing.T) {
+	var hit atomic.Bool
+	target := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { hit.Store(true) }))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	s := New(config.Webhook{
+		Secret:          "secret",
+		Timeout:         5 * time.Second,
+		AllowedNetworks: []string{"127.0.0.1"},
+	})
+	statusCode, err := s.Send(context.Background(), &model.WebhookDelivery{
+		ID:      uuid.New(),
+		URL:     server.URL,
+		Payload: []byte(`{}`),
+	})
+	if !errors.Is(err, ErrUnexpectedStatus) || statusCode != http.StatusTemporaryRedirect {
+		t.Errorf("status = %d, error = %v, want the redirect reported as a failure", statusCode, err)
+	}
+	if hit.Load() {
+		t.Error("the signed payload was resent to the redirect target")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/config"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/ticker"
 	"github.com/segmentio/kafka-go"
@@ -19,14 +20,36 @@ type Connection struct {
 	*wbfKafka.Producer // TODO: move to ./image/... to move topic creation into run func
 	*wbfKafka.Consumer
 
+	DeadLetterProducer *wbfKafka.Producer
+	DeadLetterConsumer *wbfKafka.Consumer
+
+	// RetryProducer has no topic of its own, the topic is set per message
+	RetryProducer *wbfKafka.Producer
+	// RetryConsumers read the retry topics in the order of options.RetryDelays
+	RetryConsumers []*wbfKafka.Consumer
+
 	isClosed atomic.Bool
 }
 
 func New(cfg config.Kafka) *Connection {
 	connection := &Connection{
 		cfg:      &cfg,
-		Producer: wbfKafka.NewProducer(cfg.PrepWbfProducer()),
+		Producer: newKeyedProducer(cfg.PrepWbfProducer()),
 		Consumer: wbfKafka.NewConsumer(cfg.PrepWbfConsumer()),
+
+		DeadLetterProducer: newKeyedProducer(cfg.PrepWbfDeadLetterProducer()),
+		DeadLetterConsumer: wbfKafka.NewConsumer(cfg.PrepWbfDeadLetterConsumer()),
+
+		RetryProducer: &wbfKafka.Producer{
+			Writer: &kafka.Writer{
+				Addr:     kafka.TCP(cfg.Brokers()...),
+				Balancer: &kafka.Hash{},
+			},
+		},
+		RetryConsumers: make([]*wbfKafka.Consumer, 0, len(options.RetryDelays)),
+	}
+	for _, delay := range options.RetryDelays {
+		connection.RetryConsumers = append(connection.RetryConsumers, wbfKafka.NewConsumer(cfg.PrepWbfRetryConsumer(delay)))
 	}
 
 	if cfg.CreateTopic {
@@ -38,6 +61,15 @@ func New(cfg config.Kafka) *Connection {
 	return connection
 }
 
+// newKeyedProducer sends the messages with the same key, the image ID, to
+// the same partition, so that the tasks of an image are consumed in order
+// by one consumer.
+func newKeyedProducer(brokers []string, topic string) *wbfKafka.Producer {
+	producer := wbfKafka.NewProducer(brokers, topic)
+	producer.Writer.Balancer = &kafka.Hash{}
+	return producer
+}
+
 const (
 	partitions        = 3
 	replicationFactor = 1
@@ -63,6 +95,22 @@ func createTopic(cfg *config.Kafka) error {
 	}); err != nil {
 		return fmt.Errorf("create topic err: %w", err)
 	}
+	if err := conn.CreateTopics(kafka.TopicConfig{
+		Topic:             cfg.DeadLetterTopic,
+		NumPartitions:     partitions,
+		ReplicationFactor: replicationFactor,
+	}); err != nil {
+		return fmt.Errorf("create topic err: %w", err)
+	}
+	for _, delay := range options.RetryDelays {
+		if err := conn.CreateTopics(kafka.TopicConfig{
+			Topic:             cfg.RetryTopic(delay),
+			NumPartitions:     partitions,
+			ReplicationFactor: replicationFactor,
+		}); err != nil {
+			return fmt.Errorf("create topic err: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -105,7 +153,7 @@ func (w *Connection) Shutdown(ctx context.Context) error {
 	defer w.isClosed.Store(true)
 
 	var errs []error
-	errChan := make(chan error, 3)
+	errChan := make(chan error, 5+len(w.RetryConsumers))
 	done := make(chan struct{})
 
 	wg := sync.WaitGroup{}
@@ -120,6 +168,12 @@ func (w *Connection) Shutdown(ctx context.Context) error {
 
 	wg.Go(func() { closeResource("consumer", w.Consumer.Close) })
 	wg.Go(func() { closeResource("producer", w.Producer.Close) })
+	wg.Go(func() { closeResource("dead letter consumer", w.DeadLetterConsumer.Close) })
+	wg.Go(func() { closeResource("dead letter producer", w.DeadLetterProducer.Close) })
+	wg.Go(func() { closeResource("retry producer", w.RetryProducer.Close) })
+	for _, retryConsumer := range w.RetryConsumers {
+		wg.Go(func() { closeResource("retry consumer", retryConsumer.Close) })
+	}
 
 	go func() {
 		wg.Wait()
@@ -3,80 +3,447 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
 
 	appPorts "github.com/D1sordxr/image-processor/internal/domain/app/port"
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/port"
 	"github.com/D1sordxr/image-processor/pkg/logger"
 	"github.com/segmentio/kafka-go"
 	wbfKafka "github.com/wb-go/wbf/kafka"
+	"github.com/wb-go/wbf/retry"
 )
 
+// RetryTier is a topic whose tasks are processed again once the delay has
+// passed since they failed.
+type RetryTier struct {
+	Topic string
+	Delay time.Duration
+}
+
+// Consumer processes the tasks of the image topic or of one of the retry
+// topics. A task that fails with a transient error is moved to the next
+// retry tier, a task that fails permanently or after the last tier is sent
+// to the dead-letter topic.
 type Consumer struct {
-	log      appPorts.Logger
-	consumer *wbfKafka.Consumer
-	topic    string
+	log         appPorts.Logger
+	consumer    *wbfKafka.Consumer
+	retries     *wbfKafka.Producer
+	deadLetters *wbfKafka.Producer
+	topic       string
+	tiers       []RetryTier
+	workers     int
+
+	mu      sync.Mutex
+	abort   context.CancelFunc
+	stopped chan struct{}
 }
 
 func New(
 	log appPorts.Logger,
 	consumer *wbfKafka.Consumer,
+	retries *wbfKafka.Producer,
+	deadLetters *wbfKafka.Producer,
 	topic string,
+	tiers []RetryTier,
+	workers int,
 ) *Consumer {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
 	return &Consumer{
-		log:      log,
-		consumer: consumer,
-		topic:    topic,
+		log:         log,
+		consumer:    consumer,
+		retries:     retries,
+		deadLetters: deadLetters,
+		topic:       topic,
+		tiers:       tiers,
+		workers:     workers,
+		stopped:     make(chan struct{}),
 	}
 }
 
+// StartProcessing processes the tasks in a pool of workers, one attempt at
+// a time. A message is committed once its task is done or handed to the
+// next topic, and only after the earlier messages of its partition. The
+// consumer stops if that topic is not reachable, the task is then consumed
+// again after the restart.
+//
+// Once the context is done the consumer stops fetching, while the tasks in
+// work go on until they finish or Stop gives up on them.
 func (c *Consumer) StartProcessing(
 	ctx context.Context,
 	processor func(context.Context, *model.ProcessingImage) error,
 ) error {
 	const op = "image.Consumer.StartProcessing"
 	logFields := logger.WithFields("operation", op)
+	defer close(c.stopped)
 
-	c.log.Info("Starting Kafka consumer", logFields("topic", c.topic)...)
+	c.log.Info("Starting Kafka consumer", logFields("topic", c.topic, "workers", c.workers)...)
 
 	messages := make(chan kafka.Message, 128)
 	c.consumer.StartConsuming(ctx, messages, options.BrokerStrategy)
 
+	// the tasks in work outlive the context, they are aborted by Stop or
+	// when the consumer fails
+	workCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	defer abort()
+	c.mu.Lock()
+	c.abort = abort
+	c.mu.Unlock()
+
+	workers := newPool(c.workers, options.ConsumerQueueSize)
+	results := make(chan result, c.workers)
+	var wg sync.WaitGroup
+	for _, queue := range workers.queues {
+		wg.Go(func() {
+			for msg := range queue {
+				results <- c.handle(ctx, workCtx, msg, processor)
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	offsets := newOffsetTracker()
+	err := c.dispatch(ctx, messages, workers, offsets, results)
+	workers.close()
+	if err != nil {
+		abort()
+	}
+
+	c.log.Info("Draining in-flight tasks", logFields()...)
+	for res := range results {
+		if completeErr := c.complete(ctx, offsets, res); completeErr != nil && err == nil {
+			abort()
+			err = completeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("Kafka consumer drained", logFields()...)
+	return nil
+}
+
+// Stop waits until the tasks in work are done. The tasks still in work
+// when the context is done are aborted and requeued.
+func (c *Consumer) Stop(ctx context.Context) error {
+	const op = "image.Consumer.Stop"
+
+	select {
+	case <-c.stopped:
+		return nil
+	case <-ctx.Done():
+	}
+
+	c.mu.Lock()
+	abort := c.abort
+	c.mu.Unlock()
+	if abort != nil {
+		c.log.Warn("Drain deadline exceeded, aborting tasks in work", "operation", op, "topic", c.topic)
+		abort()
+	}
+
+	return fmt.Errorf("%s: %w", op, ctx.Err())
+}
+
+// dispatch hands the messages to the workers until the context is done or
+// a message cannot be handed over.
+func (c *Consumer) dispatch(
+	ctx context.Context,
+	messages <-chan kafka.Message,
+	workers *pool,
+	offsets *offsetTracker,
+	results <-chan result,
+) error {
+	const op = "image.Consumer.dispatch"
+	logFields := logger.WithFields("operation", op)
+
 	for {
 		select {
 		case <-ctx.Done():
 			c.log.Info("Context cancelled, stopping consumer", logFields()...)
 			return nil
 
+		case res := <-results:
+			if err := c.complete(ctx, offsets, res); err != nil {
+				return err
+			}
+
 		case msg, ok := <-messages:
 			if !ok {
 				c.log.Info("Messages channel closed", logFields()...)
 				return nil
 			}
 
-			var processingImage model.ProcessingImage
-			if err := json.Unmarshal(msg.Value, &processingImage); err != nil {
-				c.log.Error("Failed to unmarshal message",
-					logFields("error", err, "offset", msg.Offset)...)
-				continue
+			offsets.track(msg)
+			queue := workers.queueFor(msg.Key)
+		handover:
+			for {
+				select {
+				case queue <- msg:
+					break handover
+				case res := <-results:
+					// the worker of the key is busy, keep committing meanwhile
+					if err := c.complete(ctx, offsets, res); err != nil {
+						return err
+					}
+				case <-ctx.Done():
+					// not dispatched, consumed again after the restart
+					return nil
+				}
 			}
+		}
+	}
+}
 
-			msgLogFields := logger.WithFields(
-				"operation", op,
-				"image_id", processingImage.ImageID,
-				"offset", msg.Offset,
-			)
+// result is the outcome of a message: done if it can be committed, err if
+// it could not be handed over after a failure.
+type result struct {
+	msg  kafka.Message
+	done bool
+	err  error
+}
 
-			if err := processor(ctx, &processingImage); err != nil {
-				c.log.Error("Failed to process image", msgLogFields("error", err)...)
-				continue
-			}
+func (c *Consumer) complete(ctx context.Context, offsets *offsetTracker, res result) error {
+	const op = "image.Consumer.complete"
 
-			if err := c.consumer.Commit(ctx, msg); err != nil {
-				c.log.Error("Failed to commit message", msgLogFields("error", err)...)
-			} else {
-				c.log.Debug("Successfully committed message", msgLogFields()...)
-			}
+	if res.err != nil {
+		c.log.Error("Failed to hand over failed message", "operation", op,
+			"key", string(res.msg.Key), "offset", res.msg.Offset, "error", res.err)
+		return res.err
+	}
+	if !res.done {
+		// left uncommitted, so are the later messages of its partition
+		return nil
+	}
+
+	msg, ok := offsets.complete(res.msg)
+	if !ok {
+		return nil
+	}
+
+	msgLogFields := logger.WithFields(
+		"operation", op,
+		"topic", c.topic,
+		"partition", msg.Partition,
+		"offset", msg.Offset,
+	)
+	// the commits of the drain happen after the context is done
+	commitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), options.ConsumerHandoverTimeout)
+	defer cancel()
+	if err := c.consumer.Commit(commitCtx, msg); err != nil {
+		c.log.Error("Failed to commit message", msgLogFields("error", err)...)
+	} else {
+		c.log.Debug("Successfully committed message", msgLogFields()...)
+	}
+
+	return nil
+}
+
+// handle processes a message once and hands a failed task over. A message
+// still queued when the context is done, or aborted while in work, is
+// requeued as it is.
+func (c *Consumer) handle(
+	ctx context.Context,
+	workCtx context.Context,
+	msg kafka.Message,
+	processor func(context.Context, *model.ProcessingImage) error,
+) result {
+	// the messages of a retry topic share the delay, so they are due in the
+	// order they are stored
+	if ctx.Err() != nil || !waitUntilDue(ctx, msg) {
+		return c.requeue(ctx, msg)
+	}
+
+	attempts := headerInt(msg, HeaderAttempts) + 1
+	if err := c.process(workCtx, msg, processor); err != nil {
+		if workCtx.Err() != nil {
+			return c.requeue(ctx, msg)
+		}
+		if err = c.handleFailure(workCtx, msg, err, attempts); err != nil {
+			return result{msg: msg, err: err}
 		}
 	}
+
+	return result{msg: msg, done: true}
+}
+
+// requeue writes a task that was not processed back to the topic it was
+// read from, without counting an attempt, so that it is taken up by another
+// replica or after the restart; a retried task keeps its due time and waits
+// in its own tier. Without retry topics, or if the broker is not reachable,
+// the message is left uncommitted instead.
+func (c *Consumer) requeue(ctx context.Context, msg kafka.Message) result {
+	const op = "image.Consumer.requeue"
+	logFields := logger.WithFields(
+		"operation", op,
+		"key", string(msg.Key),
+		"offset", msg.Offset,
+	)
+
+	if len(c.tiers) == 0 {
+		return result{msg: msg}
+	}
+
+	headers := msg.Headers
+	if _, ok := header(msg, HeaderTopic); !ok {
+		headers = append(originHeaders(msg), headers...)
+	}
+
+	requeueCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), options.ConsumerHandoverTimeout)
+	defer cancel()
+	if err := c.retries.Writer.WriteMessages(requeueCtx, kafka.Message{
+		Topic:   msg.Topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}); err != nil {
+		c.log.Warn("Failed to requeue message, leaving it uncommitted", logFields("error", err)...)
+		return result{msg: msg}
+	}
+
+	c.log.Info("Requeued unfinished message", logFields("topic", msg.Topic)...)
+	return result{msg: msg, done: true}
+}
+
+func (c *Consumer) process(
+	ctx context.Context,
+	msg kafka.Message,
+	processor func(context.Context, *model.ProcessingImage) error,
+) error {
+	var processingImage model.ProcessingImage
+	if err := json.Unmarshal(msg.Value, &processingImage); err != nil {
+		return fmt.Errorf("%w: unmarshal message: %w", port.ErrPermanent, err)
+	}
+
+	return processor(ctx, &processingImage)
+}
+
+// handleFailure moves the message to the next retry tier or, if the error
+// is permanent or the tiers are exhausted, to the dead-letter topic.
+func (c *Consumer) handleFailure(ctx context.Context, msg kafka.Message, cause error, attempts int) error {
+	const op = "image.Consumer.handleFailure"
+	logFields := logger.WithFields(
+		"operation", op,
+		"key", string(msg.Key),
+		"offset", msg.Offset,
+		"attempts", attempts,
+		"error", cause,
+	)
+
+	headers := failureHeaders(msg, cause, attempts)
+
+	if errors.Is(cause, port.ErrPermanent) || attempts > len(c.tiers) {
+		c.log.Error("Giving up on message, sending it to the dead-letter topic", logFields()...)
+		return c.send(ctx, c.deadLetters, kafka.Message{
+			Key:     msg.Key,
+			Value:   msg.Value,
+			Headers: headers,
+		})
+	}
+
+	tier := c.tiers[attempts-1]
+	dueAt := time.Now().Add(tier.Delay)
+	c.log.Warn("Failed to process message, scheduling retry", logFields(
+		"retry_topic", tier.Topic,
+		"due_at", dueAt,
+	)...)
+	return c.send(ctx, c.retries, kafka.Message{
+		Topic:   tier.Topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: append(headers, kafka.Header{Key: HeaderDueAt, Value: []byte(formatTime(dueAt))}),
+	})
+}
+
+func (c *Consumer) send(ctx context.Context, producer *wbfKafka.Producer, msg kafka.Message) error {
+	return retry.Do(func() error {
+		return producer.Writer.WriteMessages(ctx, msg)
+	}, options.BrokerStrategy)
+}
+
+// failureHeaders describes the failure; the original coordinates are kept
+// from the first failure, so that a dead letter points to the task as it
+// was published.
+func failureHeaders(msg kafka.Message, cause error, attempts int) []kafka.Header {
+	return append(originHeaders(msg),
+		kafka.Header{Key: HeaderError, Value: []byte(cause.Error())},
+		kafka.Header{Key: HeaderAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(formatTime(time.Now()))},
+	)
+}
+
+// originHeaders point to the message as it was published, which is the
+// message itself unless it was already moved.
+func originHeaders(msg kafka.Message) []kafka.Header {
+	topic, partition, offset := msg.Topic, strconv.Itoa(msg.Partition), strconv.FormatInt(msg.Offset, 10)
+	if original, ok := header(msg, HeaderTopic); ok {
+		topic = original
+		partition, _ = header(msg, HeaderPartition)
+		offset, _ = header(msg, HeaderOffset)
+	}
+
+	return []kafka.Header{
+		{Key: HeaderTopic, Value: []byte(topic)},
+		{Key: HeaderPartition, Value: []byte(partition)},
+		{Key: HeaderOffset, Value: []byte(offset)},
+	}
+}
+
+// waitUntilDue blocks until the message is due. It reports false if the
+// context is cancelled first.
+func waitUntilDue(ctx context.Context, msg kafka.Message) bool {
+	value, ok := header(msg, HeaderDueAt)
+	if !ok {
+		return true
+	}
+	dueAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return true
+	}
+
+	wait := time.Until(dueAt)
+	if wait <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func header(msg kafka.Message, key string) (string, bool) {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
+
+func headerInt(msg kafka.Message, key string) int {
+	value, _ := header(msg, key)
+	n, _ := strconv.Atoi(value)
+	return n
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
 }
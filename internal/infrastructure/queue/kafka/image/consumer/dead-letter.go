@@ -0,0 +1,143 @@
+package consumer
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	appPorts "github.com/D1sordxr/image-processor/internal/domain/app/port"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+	"github.com/segmentio/kafka-go"
+	wbfKafka "github.com/wb-go/wbf/kafka"
+)
+
+// Headers of a retried or dead-lettered message.
+const (
+	HeaderError     = "x-error"
+	HeaderAttempts  = "x-attempts"
+	HeaderTopic     = "x-original-topic"
+	HeaderPartition = "x-original-partition"
+	HeaderOffset    = "x-original-offset"
+	HeaderFailedAt  = "x-failed-at"
+	HeaderDueAt     = "x-due-at"
+)
+
+const deadLetterRetryDelay = 5 * time.Second
+
+// DeadLetterConsumer reads the dead-letter topic, so that the failed tasks
+// can be listed and replayed.
+type DeadLetterConsumer struct {
+	log      appPorts.Logger
+	consumer *wbfKafka.Consumer
+	topic    string
+}
+
+func NewDeadLetterConsumer(
+	log appPorts.Logger,
+	consumer *wbfKafka.Consumer,
+	topic string,
+) *DeadLetterConsumer {
+	return &DeadLetterConsumer{
+		log:      log,
+		consumer: consumer,
+		topic:    topic,
+	}
+}
+
+// StartConsuming hands every dead letter to the handler. A letter is
+// retried until the handler accepts it, so none is skipped while the
+// storage is down.
+func (c *DeadLetterConsumer) StartConsuming(
+	ctx context.Context,
+	handler func(context.Context, *model.DeadLetter) error,
+) error {
+	const op = "image.DeadLetterConsumer.StartConsuming"
+	logFields := logger.WithFields("operation", op)
+
+	c.log.Info("Starting dead-letter consumer", logFields("topic", c.topic)...)
+
+	messages := make(chan kafka.Message, 128)
+	c.consumer.StartConsuming(ctx, messages, options.BrokerStrategy)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case msg, ok := <-messages:
+			if !ok {
+				c.log.Info("Dead-letter channel closed", logFields()...)
+				return nil
+			}
+
+			msgLogFields := logger.WithFields(
+				"operation", op,
+				"key", string(msg.Key),
+				"offset", msg.Offset,
+			)
+
+			letter := toDeadLetter(msg)
+			for {
+				err := handler(ctx, letter)
+				if err == nil {
+					break
+				}
+				c.log.Error("Failed to handle dead letter", msgLogFields("error", err)...)
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(deadLetterRetryDelay):
+				}
+			}
+
+			if err := c.consumer.Commit(ctx, msg); err != nil {
+				c.log.Error("Failed to commit dead letter", msgLogFields("error", err)...)
+			}
+		}
+	}
+}
+
+// toDeadLetter reads the original coordinates from the headers. A message
+// without them is described by its own.
+func toDeadLetter(msg kafka.Message) *model.DeadLetter {
+	letter := &model.DeadLetter{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Payload:   string(msg.Value),
+		Attempts:  1,
+		FailedAt:  msg.Time,
+	}
+
+	for _, header := range msg.Headers {
+		value := string(header.Value)
+		switch header.Key {
+		case HeaderError:
+			letter.Error = value
+		case HeaderAttempts:
+			if attempts, err := strconv.Atoi(value); err == nil {
+				letter.Attempts = attempts
+			}
+		case HeaderTopic:
+			letter.Topic = value
+		case HeaderPartition:
+			if partition, err := strconv.Atoi(value); err == nil {
+				letter.Partition = partition
+			}
+		case HeaderOffset:
+			if offset, err := strconv.ParseInt(value, 10, 64); err == nil {
+				letter.Offset = offset
+			}
+		case HeaderFailedAt:
+			if failedAt, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				letter.FailedAt = failedAt
+			}
+		}
+	}
+
+	return letter
+}
@@ -0,0 +1,83 @@
+package consumer
+
+import (
+	"hash/fnv"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// pool routes the messages to the workers by key, so that the tasks of an
+// image are processed one after another by the same worker. The queues are
+// bounded: the dispatch blocks while the worker of a key is busy.
+type pool struct {
+	queues []chan kafka.Message
+}
+
+func newPool(workers, queueSize int) *pool {
+	queues := make([]chan kafka.Message, workers)
+	for i := range queues {
+		queues[i] = make(chan kafka.Message, queueSize)
+	}
+	return &pool{queues: queues}
+}
+
+func (p *pool) queueFor(key []byte) chan kafka.Message {
+	hash := fnv.New32a()
+	_, _ = hash.Write(key)
+	return p.queues[hash.Sum32()%uint32(len(p.queues))]
+}
+
+func (p *pool) close() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+}
+
+// offsetTracker finds the messages that can be committed. A message is
+// committed only when every earlier message of its partition is done, so
+// that a crash never skips a message still in work.
+type offsetTracker struct {
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	pending []int64 // in the order of dispatch
+	done    map[int64]kafka.Message
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: make(map[int]*partitionOffsets)}
+}
+
+func (t *offsetTracker) track(msg kafka.Message) {
+	partition, ok := t.partitions[msg.Partition]
+	if !ok {
+		partition = &partitionOffsets{done: make(map[int64]kafka.Message)}
+		t.partitions[msg.Partition] = partition
+	}
+	partition.pending = append(partition.pending, msg.Offset)
+}
+
+// complete marks the message done and returns the last message of the
+// partition whose predecessors are all done, if any.
+func (t *offsetTracker) complete(msg kafka.Message) (kafka.Message, bool) {
+	partition, ok := t.partitions[msg.Partition]
+	if !ok {
+		return kafka.Message{}, false
+	}
+	partition.done[msg.Offset] = msg
+
+	var last kafka.Message
+	found := false
+	for len(partition.pending) > 0 {
+		next, ok := partition.done[partition.pending[0]]
+		if !ok {
+			break
+		}
+		delete(partition.done, partition.pending[0])
+		partition.pending = partition.pending[1:]
+		last, found = next, true
+	}
+
+	return last, found
+}
@@ -0,0 +1,101 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestOffsetTrackerComplete(t *testing.T) {
+	type step struct {
+		offset     int64
+		wantCommit int64 // -1 when nothing may be committed
+	}
+
+	tests := []struct {
+		name    string
+		tracked []int64
+		steps   []step
+	}{
+		{
+			name:    "in order",
+			tracked: []int64{1, 2, 3},
+			steps:   []step{{1, 1}, {2, 2}, {3, 3}},
+		},
+		{
+			name:    "out of order",
+			tracked: []int64{1, 2, 3, 4},
+			steps:   []step{{3, -1}, {2, -1}, {1, 3}, {4, 4}},
+		},
+		{
+			name:    "last first",
+			tracked: []int64{10, 11, 12},
+			steps:   []step{{12, -1}, {10, 10}, {11, 12}},
+		},
+		{
+			// a failed or requeued message that is not done holds the
+			// commit at the offset before it
+			name:    "gap",
+			tracked: []int64{1, 2, 3, 4},
+			steps:   []step{{1, 1}, {3, -1}, {4, -1}},
+		},
+		{
+			name:    "sparse offsets",
+			tracked: []int64{5, 9, 20},
+			steps:   []step{{9, -1}, {5, 9}, {20, 20}},
+		},
+	}
+	for _, tt := range tests {
+		tracker := newOffsetTracker()
+		for _, offset := range tt.tracked {
+			tracker.track(kafka.Message{Partition: 0, Offset: offset})
+		}
+
+		for _, s := range tt.steps {
+			msg, ok := tracker.complete(kafka.Message{Partition: 0, Offset: s.offset})
+			switch {
+			case s.wantCommit < 0 && ok:
+				t.Errorf("%s: completing %d commits %d, want nothing", tt.name, s.offset, msg.Offset)
+			case s.wantCommit >= 0 && !ok:
+				t.Errorf("%s: completing %d commits nothing, want %d", tt.name, s.offset, s.wantCommit)
+			case s.wantCommit >= 0 && msg.Offset != s.wantCommit:
+				t.Errorf("%s: completing %d commits %d, want %d", tt.name, s.offset, msg.Offset, s.wantCommit)
+			}
+		}
+	}
+}
+
+func TestOffsetTrackerPartitions(t *testing.T) {
+	tracker := newOffsetTracker()
+	tracker.track(kafka.Message{Partition: 0, Offset: 1})
+	tracker.track(kafka.Message{Partition: 1, Offset: 1})
+	tracker.track(kafka.Message{Partition: 0, Offset: 2})
+
+	// a message in work on one partition does not hold up another
+	if msg, ok := tracker.complete(kafka.Message{Partition: 1, Offset: 1}); !ok || msg.Partition != 1 || msg.Offset != 1 {
+		t.Errorf("partition 1: commits %d/%d (%v), want 1/1", msg.Partition, msg.Offset, ok)
+	}
+	if _, ok := tracker.complete(kafka.Message{Partition: 0, Offset: 2}); ok {
+		t.Error("partition 0: offset 2 committed before offset 1")
+	}
+	if msg, ok := tracker.complete(kafka.Message{Partition: 0, Offset: 1}); !ok || msg.Offset != 2 {
+		t.Errorf("partition 0: commits %d (%v), want 2", msg.Offset, ok)
+	}
+}
+
+func TestOffsetTrackerUntracked(t *testing.T) {
+	tracker := newOffsetTracker()
+	if _, ok := tracker.complete(kafka.Message{Partition: 3, Offset: 1}); ok {
+		t.Error("an untracked partition is committed")
+	}
+}
+
+func TestPoolQueueForKey(t *testing.T) {
+	p := newPool(4, 1)
+
+	for _, key := range []string{"a", "image-1", "7f1b0c9e-41c4-4e4b-9a36-0b1f1c0f8d10"} {
+		if p.queueFor([]byte(key)) != p.queueFor([]byte(key)) {
+			t.Errorf("key %q is routed to different workers", key)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	appPorts "github.com/D1sordxr/image-processor/internal/domain/app/port"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/port"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+)
+
+var errUndecodableTask = errors.New("task cannot be decoded")
+
+// Relay publishes the tasks saved in the outbox to the queue. The pending
+// messages are leased, so several replicas can run it, and no database lock
+// is held while publishing. A message is published at least once: it is
+// sent again if it cannot be marked, or if its lease expires first.
+type Relay struct {
+	log   appPorts.Logger
+	repo  port.OutboxRepository
+	queue port.Queue
+	done  chan struct{}
+}
+
+func NewRelay(
+	log appPorts.Logger,
+	repo port.OutboxRepository,
+	queue port.Queue,
+) *Relay {
+	return &Relay{
+		log:   log,
+		repo:  repo,
+		queue: queue,
+		done:  make(chan struct{}),
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) error {
+	const op = "outbox.Relay.Run"
+	defer close(r.done)
+
+	r.log.Info("Starting outbox relay", "operation", op)
+
+	pollTicker := time.NewTicker(options.OutboxPollInterval)
+	defer pollTicker.Stop()
+	cleanupTicker := time.NewTicker(options.OutboxCleanupInterval)
+	defer cleanupTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-cleanupTicker.C:
+			r.cleanup(ctx)
+			continue
+		case <-pollTicker.C:
+		}
+
+		// a full batch means more messages may be pending
+		for {
+			relayed, err := r.relayBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					r.log.Error("Failed to relay outbox messages", "operation", op, "error", err.Error())
+				}
+				break
+			}
+			if relayed < options.OutboxBatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+// Shutdown waits for the batch in flight, so that the queue is not closed
+// under it.
+func (r *Relay) Shutdown(ctx context.Context) error {
+	const op = "outbox.Relay.Shutdown"
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", op, ctx.Err())
+	}
+}
+
+// relayBatch publishes the claimed messages and returns how many were
+// claimed. A message of an image is claimed only once the earlier ones are
+// sent, so that the tasks of an image are not reordered.
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	const op = "outbox.Relay.relayBatch"
+
+	now := time.Now()
+	messages, err := r.repo.ClaimPending(ctx, options.OutboxClaimParams{
+		Now:        now,
+		LeaseUntil: now.Add(options.OutboxLease),
+		Limit:      options.OutboxBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: claim pending messages: %w", op, err)
+	}
+
+	for _, message := range messages {
+		logFields := logger.WithFields(
+			"operation", op,
+			"message_id", message.ID.String(),
+		)
+
+		if message.Task == nil {
+			// the payload does not change, publishing it again is of no use
+			r.log.Error("Giving up outbox message with no valid task", logFields()...)
+			r.markFailed(ctx, message, errUndecodableTask, true)
+			continue
+		}
+		logFields = logger.WithFields(
+			"operation", op,
+			"message_id", message.ID.String(),
+			"image_id", message.Task.ImageID,
+		)
+
+		if publishErr := r.queue.Publish(ctx, message.Task); publishErr != nil {
+			if ctx.Err() != nil {
+				// the message is claimed again when the lease expires
+				return 0, fmt.Errorf("%s: %w", op, ctx.Err())
+			}
+
+			attempts := message.Attempts + 1
+			if attempts >= options.OutboxMaxAttempts {
+				r.log.Error("Giving up outbox message", logFields(
+					"error", publishErr.Error(),
+					"attempts", attempts,
+				)...)
+				r.markFailed(ctx, message, publishErr, true)
+				continue
+			}
+
+			r.log.Warn("Failed to publish outbox message", logFields(
+				"error", publishErr.Error(),
+				"attempts", attempts,
+			)...)
+			r.markFailed(ctx, message, publishErr, false)
+			continue
+		}
+
+		if err = r.repo.MarkSent(ctx, message.ID, time.Now()); err != nil {
+			r.log.Error("Failed to mark outbox message sent", logFields("error", err.Error())...)
+		}
+	}
+
+	return len(messages), nil
+}
+
+// markFailed schedules the next attempt of the message, or gives it up; the
+// image of a message given up is failed by the reconciler.
+func (r *Relay) markFailed(ctx context.Context, message model.OutboxMessage, cause error, giveUp bool) {
+	const op = "outbox.Relay.markFailed"
+
+	now := time.Now()
+	params := options.OutboxFailureParams{
+		ID:            message.ID,
+		Error:         cause.Error(),
+		NextAttemptAt: now.Add(outboxBackoff(message.Attempts + 1)),
+	}
+	if giveUp {
+		params.FailedAt = &now
+	}
+
+	if err := r.repo.MarkFailed(ctx, params); err != nil {
+		r.log.Error("Failed to mark outbox message failed", "operation", op,
+			"message_id", message.ID.String(),
+			"error", err.Error(),
+		)
+	}
+}
+
+func (r *Relay) cleanup(ctx context.Context) {
+	const op = "outbox.Relay.cleanup"
+
+	deleted, err := r.repo.DeleteSent(ctx, time.Now().Add(-options.OutboxRetention))
+	if err != nil {
+		r.log.Error("Failed to delete sent outbox messages", "operation", op, "error", err.Error())
+		return
+	}
+	if deleted > 0 {
+		r.log.Info("Deleted sent outbox messages", "operation", op, "deleted", deleted)
+	}
+}
+
+func outboxBackoff(attempt int) time.Duration {
+	backoff := options.OutboxBaseBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > options.OutboxMaxBackoff {
+		return options.OutboxMaxBackoff
+	}
+	return backoff
+}
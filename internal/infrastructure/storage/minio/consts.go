@@ -1,5 +1,9 @@
 package minio
 
+// PartSize is the size of the parts objects are uploaded with; minio-go
+// would pick 512MiB parts for streams of unknown size.
+const PartSize = 16 << 20
+
 const (
 	policy = `{
 			"Version": "2012-10-17",
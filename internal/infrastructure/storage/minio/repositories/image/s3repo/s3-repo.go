@@ -7,6 +7,7 @@ import (
 	"io"
 	"mime"
 	"path/filepath"
+	"time"
 
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
@@ -28,11 +29,7 @@ func New(storage *minioRoot.Connection, bucketName string) *S3Repository {
 }
 
 func (s3 *S3Repository) Save(ctx context.Context, data []byte, filename string) (*model.FileInfo, error) {
-	ext := filepath.Ext(filename)
-	mimeType := mime.TypeByExtension(ext)
-	if mimeType == "" {
-		mimeType = "application/octet-stream"
-	}
+	mimeType := mimeTypeOf(filename)
 
 	reader := bytes.NewReader(data)
 	info, err := s3.storage.Storage.PutObject(ctx, s3.bucketName, filename, reader, int64(len(data)), minio.PutObjectOptions{
@@ -50,22 +47,19 @@ func (s3 *S3Repository) Save(ctx context.Context, data []byte, filename string)
 	}, nil
 }
 
-func (s3 *S3Repository) SaveOriginal(ctx context.Context, data []byte, filename string) (*model.FileInfo, error) {
-	ext := filepath.Ext(filename)
-	mimeType := mime.TypeByExtension(ext)
-	if mimeType == "" {
-		mimeType = "application/octet-stream"
-	}
+func (s3 *S3Repository) SaveOriginal(ctx context.Context, reader io.Reader, size int64, filename string) (*model.FileInfo, error) {
+	mimeType := mimeTypeOf(filename)
 
-	reader := bytes.NewReader(data)
+	// multipart upload buffers one part at a time
 	info, err := s3.storage.Storage.PutObject(
 		ctx,
 		s3.bucketName,
 		vo.NewFilenameOriginal(filename).String(),
 		reader,
-		int64(len(data)),
+		size,
 		minio.PutObjectOptions{
 			ContentType: mimeType,
+			PartSize:    minioRoot.PartSize,
 		},
 	)
 	if err != nil {
@@ -80,6 +74,124 @@ func (s3 *S3Repository) SaveOriginal(ctx context.Context, data []byte, filename
 	}, nil
 }
 
+func (s3 *S3Repository) CreateOriginalUpload(ctx context.Context, filename string) (string, error) {
+	core := minio.Core{Client: s3.storage.Storage}
+	uploadID, err := core.NewMultipartUpload(
+		ctx,
+		s3.bucketName,
+		vo.NewFilenameOriginal(filename).String(),
+		minio.PutObjectOptions{ContentType: mimeTypeOf(filename)},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	return uploadID, nil
+}
+
+func (s3 *S3Repository) PutOriginalPart(
+	ctx context.Context,
+	filename, uploadID string,
+	number int,
+	reader io.Reader,
+	size int64,
+) (*model.UploadPart, error) {
+	core := minio.Core{Client: s3.storage.Storage}
+	part, err := core.PutObjectPart(
+		ctx,
+		s3.bucketName,
+		vo.NewFilenameOriginal(filename).String(),
+		uploadID,
+		number,
+		reader,
+		size,
+		minio.PutObjectPartOptions{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload part %d: %w", number, err)
+	}
+
+	return &model.UploadPart{
+		Number: part.PartNumber,
+		ETag:   part.ETag,
+		Size:   part.Size,
+	}, nil
+}
+
+func (s3 *S3Repository) CompleteOriginalUpload(
+	ctx context.Context,
+	filename, uploadID string,
+	parts []model.UploadPart,
+) (*model.FileInfo, error) {
+	completeParts := make([]minio.CompletePart, 0, len(parts))
+	var size int64
+	for _, part := range parts {
+		completeParts = append(completeParts, minio.CompletePart{
+			PartNumber: part.Number,
+			ETag:       part.ETag,
+		})
+		size += part.Size
+	}
+
+	core := minio.Core{Client: s3.storage.Storage}
+	info, err := core.CompleteMultipartUpload(
+		ctx,
+		s3.bucketName,
+		vo.NewFilenameOriginal(filename).String(),
+		uploadID,
+		completeParts,
+		minio.PutObjectOptions{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	return &model.FileInfo{
+		Path:     filename,
+		Size:     size,
+		MimeType: mimeTypeOf(filename),
+		ETag:     info.ETag,
+	}, nil
+}
+
+func (s3 *S3Repository) AbortOriginalUpload(ctx context.Context, filename, uploadID string) error {
+	core := minio.Core{Client: s3.storage.Storage}
+	if err := core.AbortMultipartUpload(
+		ctx,
+		s3.bucketName,
+		vo.NewFilenameOriginal(filename).String(),
+		uploadID,
+	); err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	return nil
+}
+
+func (s3 *S3Repository) PresignOriginalUpload(ctx context.Context, filename string, expiry time.Duration) (string, error) {
+	url, err := s3.storage.Storage.PresignedPutObject(
+		ctx,
+		s3.bucketName,
+		vo.NewFilenameOriginal(filename).String(),
+		expiry,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned upload URL: %w", err)
+	}
+	return url.String(), nil
+}
+
+func (s3 *S3Repository) OpenOriginal(ctx context.Context, filename string) (io.ReadCloser, error) {
+	object, err := s3.storage.Storage.GetObject(
+		ctx,
+		s3.bucketName,
+		vo.NewFilenameOriginal(filename).String(),
+		minio.GetObjectOptions{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	return object, nil
+}
+
 func (s3 *S3Repository) Get(ctx context.Context, filename string) ([]byte, error) {
 	object, err := s3.storage.Storage.GetObject(ctx, s3.bucketName, filename, minio.GetObjectOptions{})
 	if err != nil {
@@ -177,12 +289,18 @@ func (s3 *S3Repository) GetFileInfo(ctx context.Context, filename string) (*mode
 	}, nil
 }
 
-func (s3 *S3Repository) ListFiles(ctx context.Context, prefix string) ([]model.FileInfo, error) {
-	var files []model.FileInfo
+func (s3 *S3Repository) ListFiles(ctx context.Context, prefix, startAfter string, limit int) ([]model.FileInfo, error) {
+	files := make([]model.FileInfo, 0, limit)
+
+	// the listing is stopped once the page is full
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	objectCh := s3.storage.Storage.ListObjects(ctx, s3.bucketName, minio.ListObjectsOptions{
-		Prefix:    prefix,
-		Recursive: true,
+		Prefix:     prefix,
+		StartAfter: startAfter,
+		MaxKeys:    limit,
+		Recursive:  true,
 	})
 
 	for object := range objectCh {
@@ -191,10 +309,14 @@ func (s3 *S3Repository) ListFiles(ctx context.Context, prefix string) ([]model.F
 		}
 
 		files = append(files, model.FileInfo{
-			Path: object.Key,
-			Size: object.Size,
-			ETag: object.ETag,
+			Path:       object.Key,
+			Size:       object.Size,
+			ETag:       object.ETag,
+			ModifiedAt: object.LastModified,
 		})
+		if len(files) == limit {
+			break
+		}
 	}
 
 	return files, nil
@@ -208,3 +330,10 @@ func (s3 *S3Repository) CreateFolder(ctx context.Context, path string) error {
 	}
 	return nil
 }
+
+func mimeTypeOf(filename string) string {
+	if mimeType := mime.TypeByExtension(filepath.Ext(filename)); mimeType != "" {
+		return mimeType
+	}
+	return "application/octet-stream"
+}
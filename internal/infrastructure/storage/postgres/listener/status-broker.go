@@ -0,0 +1,206 @@
+package listener
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	appPorts "github.com/D1sordxr/image-processor/internal/domain/app/port"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/port"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/config"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+	pingInterval         = 90 * time.Second
+)
+
+// StatusBroker listens to the status notifications sent by the images
+// trigger and fans them out to the subscriptions of this replica.
+type StatusBroker struct {
+	log appPorts.Logger
+	cfg config.Postgres
+
+	mu       sync.RWMutex
+	listener *pq.Listener
+	watchers map[uuid.UUID]map[*subscription]struct{}
+}
+
+func NewStatusBroker(log appPorts.Logger, cfg config.Postgres) *StatusBroker {
+	return &StatusBroker{
+		log:      log,
+		cfg:      cfg,
+		watchers: make(map[uuid.UUID]map[*subscription]struct{}),
+	}
+}
+
+func (b *StatusBroker) Run(ctx context.Context) error {
+	const op = "listener.StatusBroker.Run"
+
+	listener := pq.NewListener(b.cfg.ConnectionString(), minReconnectInterval, maxReconnectInterval, b.reportEvent)
+	b.mu.Lock()
+	b.listener = listener
+	b.mu.Unlock()
+
+	// blocks until the connection is established
+	if err := listener.Listen(options.StatusEventsChannel); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	pingTicker := time.NewTicker(pingInterval)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification, ok := <-listener.Notify:
+			if !ok {
+				return nil
+			}
+			if notification == nil {
+				// sent after a reconnect, notifications in between are lost
+				b.log.Warn("Status listener reconnected", "operation", op)
+				continue
+			}
+			b.dispatch(notification.Extra)
+		case <-pingTicker.C:
+			// a dead connection is detected and re-established by the listener
+			go func() { _ = listener.Ping() }()
+		}
+	}
+}
+
+func (b *StatusBroker) Shutdown(_ context.Context) error {
+	const op = "listener.StatusBroker.Shutdown"
+
+	b.mu.Lock()
+	listener := b.listener
+	b.listener = nil
+	b.mu.Unlock()
+
+	if listener == nil {
+		return nil
+	}
+	if err := listener.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (b *StatusBroker) Subscribe() port.StatusSubscription {
+	return &subscription{
+		broker:  b,
+		events:  make(chan model.StatusEvent, options.StatusSubscriptionBuffer),
+		watched: make(map[uuid.UUID]struct{}),
+	}
+}
+
+func (b *StatusBroker) reportEvent(_ pq.ListenerEventType, err error) {
+	if err != nil {
+		b.log.Error("Status listener connection error", "error", err.Error())
+	}
+}
+
+func (b *StatusBroker) dispatch(payload string) {
+	const op = "listener.StatusBroker.dispatch"
+
+	var event model.StatusEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		b.log.Error("Invalid status notification", "operation", op, "error", err.Error())
+		return
+	}
+	imageID, err := uuid.Parse(event.ImageID)
+	if err != nil {
+		b.log.Error("Invalid status notification", "operation", op, "error", err.Error())
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.watchers[imageID] {
+		select {
+		case sub.events <- event:
+		default:
+			b.log.Warn("Status subscriber is too slow, event dropped",
+				"operation", op,
+				"image_id", event.ImageID,
+				"status", event.Status,
+			)
+		}
+	}
+}
+
+// subscription is guarded by the mutex of the broker, so that an event is
+// never sent to a closed channel.
+type subscription struct {
+	broker  *StatusBroker
+	events  chan model.StatusEvent
+	watched map[uuid.UUID]struct{}
+	closed  bool
+}
+
+func (s *subscription) Events() <-chan model.StatusEvent {
+	return s.events
+}
+
+func (s *subscription) Watch(imageIDs ...uuid.UUID) {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	for _, imageID := range imageIDs {
+		subs, ok := s.broker.watchers[imageID]
+		if !ok {
+			subs = make(map[*subscription]struct{})
+			s.broker.watchers[imageID] = subs
+		}
+		subs[s] = struct{}{}
+		s.watched[imageID] = struct{}{}
+	}
+}
+
+func (s *subscription) Unwatch(imageIDs ...uuid.UUID) {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.unwatch(imageIDs...)
+}
+
+func (s *subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	for imageID := range s.watched {
+		s.unwatch(imageID)
+	}
+	s.closed = true
+	close(s.events)
+}
+
+func (s *subscription) unwatch(imageIDs ...uuid.UUID) {
+	for _, imageID := range imageIDs {
+		delete(s.watched, imageID)
+		if subs, ok := s.broker.watchers[imageID]; ok {
+			delete(subs, s)
+			if len(subs) == 0 {
+				delete(s.broker.watchers, imageID)
+			}
+		}
+	}
+}
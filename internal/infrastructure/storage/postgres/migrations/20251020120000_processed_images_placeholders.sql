@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE processed_images
+    ADD COLUMN blur_hash VARCHAR,
+    ADD COLUMN lqip TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE processed_images
+    DROP COLUMN IF EXISTS lqip,
+    DROP COLUMN IF EXISTS blur_hash;
+-- +goose StatementEnd
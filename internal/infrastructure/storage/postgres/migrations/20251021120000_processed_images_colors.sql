@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE processed_images
+    ADD COLUMN dominant_color VARCHAR,
+    ADD COLUMN color_name VARCHAR,
+    ADD COLUMN palette VARCHAR[];
+
+CREATE INDEX idx_processed_images_color_name ON processed_images(color_name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_processed_images_color_name;
+
+ALTER TABLE processed_images
+    DROP COLUMN IF EXISTS palette,
+    DROP COLUMN IF EXISTS color_name,
+    DROP COLUMN IF EXISTS dominant_color;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE images
+    ADD COLUMN sha256 VARCHAR(64),
+    ADD COLUMN phash BIGINT;
+
+CREATE INDEX idx_images_sha256 ON images(sha256);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_images_sha256;
+
+ALTER TABLE images
+    DROP COLUMN IF EXISTS phash,
+    DROP COLUMN IF EXISTS sha256;
+-- +goose StatementEnd
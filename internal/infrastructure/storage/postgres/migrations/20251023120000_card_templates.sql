@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE card_templates (
+    id UUID PRIMARY KEY,
+    name VARCHAR NOT NULL UNIQUE,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    background VARCHAR NOT NULL,
+    image_slots JSONB NOT NULL DEFAULT '[]',
+    text_slots JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS card_templates;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE upload_sessions (
+    id UUID PRIMARY KEY,
+    filename VARCHAR NOT NULL,
+    size BIGINT NOT NULL,
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+    options JSONB NOT NULL DEFAULT '{}',
+    duplicate_policy VARCHAR NOT NULL,
+    s3_upload_id VARCHAR NOT NULL,
+    parts JSONB NOT NULL DEFAULT '[]',
+    pending BYTEA NOT NULL DEFAULT '',
+    hash_state BYTEA NOT NULL,
+    image_id UUID,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS upload_sessions;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE upload_sessions
+    ADD COLUMN method VARCHAR NOT NULL DEFAULT 'chunked';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE upload_sessions
+    DROP COLUMN IF EXISTS method;
+-- +goose StatementEnd
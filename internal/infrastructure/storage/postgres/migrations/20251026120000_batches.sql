@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE batches (
+    id UUID PRIMARY KEY,
+    total INT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP
+);
+
+CREATE TABLE batch_items (
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    item_index INT NOT NULL,
+    filename VARCHAR NOT NULL,
+    image_id UUID REFERENCES images(id) ON DELETE SET NULL,
+    error TEXT, -- validation or processing error of the item
+    PRIMARY KEY (batch_id, item_index)
+);
+
+CREATE INDEX idx_batch_items_image_id ON batch_items(image_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS batch_items;
+DROP TABLE IF EXISTS batches;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY,
+    image_id UUID NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+    url VARCHAR NOT NULL,
+    event VARCHAR NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR NOT NULL, -- "pending", "delivered", "failed"
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_deliveries_image_id ON webhook_deliveries(image_id);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE webhook_attempts (
+    delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    attempt INT NOT NULL,
+    status_code INT,
+    error TEXT,
+    duration_ms BIGINT NOT NULL,
+    attempted_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (delivery_id, attempt)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+-- +goose StatementEnd
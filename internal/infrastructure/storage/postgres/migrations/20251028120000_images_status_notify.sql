@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the notification is sent when the transaction commits, to every API replica
+-- listening on the channel
+CREATE OR REPLACE FUNCTION notify_image_status() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('image_status', json_build_object(
+        'image_id', NEW.id,
+        'status', NEW.status,
+        'previous_status', CASE WHEN TG_OP = 'UPDATE' THEN OLD.status END,
+        'timestamp', clock_timestamp()
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER images_status_inserted
+    AFTER INSERT ON images
+    FOR EACH ROW EXECUTE FUNCTION notify_image_status();
+
+CREATE TRIGGER images_status_updated
+    AFTER UPDATE OF status ON images
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION notify_image_status();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS images_status_updated ON images;
+DROP TRIGGER IF EXISTS images_status_inserted ON images;
+DROP FUNCTION IF EXISTS notify_image_status();
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE dead_letters (
+    id UUID PRIMARY KEY,
+    source_topic VARCHAR NOT NULL,
+    source_partition INT NOT NULL,
+    source_offset BIGINT NOT NULL,
+    message_key VARCHAR NOT NULL,
+    payload BYTEA NOT NULL,
+    error TEXT NOT NULL,
+    attempts INT NOT NULL,
+    failed_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    replayed_at TIMESTAMP,
+    UNIQUE (source_topic, source_partition, source_offset)
+);
+
+CREATE INDEX idx_dead_letters_created_at ON dead_letters(created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS dead_letters;
+-- +goose StatementEnd
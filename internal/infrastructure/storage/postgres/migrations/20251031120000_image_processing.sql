@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+-- image_processing holds the images in the processing status, with the time
+-- their current run started; it is kept by the triggers below, so that every
+-- path that enqueues a task is covered
+CREATE TABLE image_processing (
+    image_id UUID PRIMARY KEY REFERENCES images(id) ON DELETE CASCADE,
+    started_at TIMESTAMP NOT NULL,
+    requeues INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_image_processing_started_at ON image_processing(started_at);
+
+-- the latest task of a stuck image is enqueued again
+CREATE INDEX idx_outbox_message_key ON outbox(message_key, created_at);
+
+CREATE OR REPLACE FUNCTION track_image_processing() RETURNS trigger AS $$
+BEGIN
+    IF NEW.status = 'processing' THEN
+        INSERT INTO image_processing (image_id, started_at)
+        VALUES (NEW.id, LOCALTIMESTAMP)
+        ON CONFLICT (image_id) DO UPDATE
+            SET started_at = EXCLUDED.started_at, requeues = 0;
+    ELSE
+        DELETE FROM image_processing WHERE image_id = NEW.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER images_processing_inserted
+    AFTER INSERT ON images
+    FOR EACH ROW EXECUTE FUNCTION track_image_processing();
+
+CREATE TRIGGER images_processing_updated
+    AFTER UPDATE OF status ON images
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION track_image_processing();
+
+INSERT INTO image_processing (image_id, started_at)
+SELECT id, LOCALTIMESTAMP FROM images WHERE status = 'processing';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS images_processing_updated ON images;
+DROP TRIGGER IF EXISTS images_processing_inserted ON images;
+DROP FUNCTION IF EXISTS track_image_processing();
+DROP INDEX IF EXISTS idx_outbox_message_key;
+DROP TABLE IF EXISTS image_processing;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a message that cannot be published is given up after the last attempt,
+-- so that the relay stops retrying it and the reconciler fails its image
+ALTER TABLE outbox ADD COLUMN failed_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at) WHERE sent_at IS NULL AND failed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at) WHERE sent_at IS NULL;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS failed_at;
+-- +goose StatementEnd
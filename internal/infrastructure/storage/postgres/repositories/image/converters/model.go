@@ -1,6 +1,9 @@
 package converters
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/gen"
@@ -10,6 +13,11 @@ func ToDomainImage(dbImage gen.Image) model.ImageMetadata {
 	filename := vo.Filename(dbImage.FileName)
 	status := vo.NewStatus(dbImage.Status)
 	resultURL := vo.ResultUrl(dbImage.ResultUrl.String)
+	var phash *uint64
+	if dbImage.Phash.Valid {
+		v := uint64(dbImage.Phash.Int64)
+		phash = &v
+	}
 	return model.ImageMetadata{
 		ID:            dbImage.ID,
 		OriginalName:  dbImage.OriginalName,
@@ -19,6 +27,8 @@ func ToDomainImage(dbImage gen.Image) model.ImageMetadata {
 		Status:        status,
 		ResultURL:     resultURL,
 		UploadedAt:    dbImage.UploadedAt,
+		SHA256:        dbImage.Sha256.String,
+		PHash:         phash,
 		ProcessedData: nil,
 	}
 }
@@ -33,15 +43,217 @@ func ToDomainImageWithProcessedData(row gen.GetImageWithProcessedDataRow) model.
 		Size:         row.Size,
 		Format:       row.Format,
 		UploadedAt:   row.UploadedAt,
+		Sha256:       row.Sha256,
+		Phash:        row.Phash,
 	})
 
 	if row.Width.Valid && row.Height.Valid && row.ProcessedAt.Valid {
 		image.ProcessedData = &model.ProcessedData{
-			Width:       int(row.Width.Int32),
-			Height:      int(row.Height.Int32),
-			ProcessedAt: row.ProcessedAt.Time,
+			Width:         int(row.Width.Int32),
+			Height:        int(row.Height.Int32),
+			BlurHash:      row.BlurHash.String,
+			LQIP:          row.Lqip.String,
+			DominantColor: row.DominantColor.String,
+			ColorName:     row.ColorName.String,
+			Palette:       row.Palette,
+			ProcessedAt:   row.ProcessedAt.Time,
 		}
 	}
 
 	return image
 }
+
+func ToDomainSimilarImage(row gen.ListSimilarImagesRow) model.SimilarImage {
+	return model.SimilarImage{
+		Image: ToDomainImage(gen.Image{
+			ID:           row.ID,
+			OriginalName: row.OriginalName,
+			FileName:     row.FileName,
+			Status:       row.Status,
+			ResultUrl:    row.ResultUrl,
+			Size:         row.Size,
+			Format:       row.Format,
+			UploadedAt:   row.UploadedAt,
+			Sha256:       row.Sha256,
+			Phash:        row.Phash,
+		}),
+		Distance: int(row.Distance),
+	}
+}
+
+func ToDomainCardTemplate(dbTemplate gen.CardTemplate) (model.CardTemplate, error) {
+	template := model.CardTemplate{
+		ID:         dbTemplate.ID,
+		Name:       dbTemplate.Name,
+		Width:      int(dbTemplate.Width),
+		Height:     int(dbTemplate.Height),
+		Background: dbTemplate.Background,
+		ImageSlots: []model.CardImageSlot{},
+		TextSlots:  []model.CardTextSlot{},
+		CreatedAt:  dbTemplate.CreatedAt,
+	}
+
+	if err := json.Unmarshal(dbTemplate.ImageSlots, &template.ImageSlots); err != nil {
+		return template, fmt.Errorf("unmarshal image slots: %w", err)
+	}
+	if err := json.Unmarshal(dbTemplate.TextSlots, &template.TextSlots); err != nil {
+		return template, fmt.Errorf("unmarshal text slots: %w", err)
+	}
+
+	return template, nil
+}
+
+func ToDomainUploadSession(dbSession gen.UploadSession) (model.UploadSession, error) {
+	session := model.UploadSession{
+		ID:              dbSession.ID,
+		Method:          vo.UploadMethod(dbSession.Method),
+		Filename:        dbSession.Filename,
+		Size:            dbSession.Size,
+		Offset:          dbSession.UploadOffset,
+		DuplicatePolicy: vo.DuplicatePolicy(dbSession.DuplicatePolicy),
+		S3UploadID:      dbSession.S3UploadID,
+		Parts:           []model.UploadPart{},
+		Pending:         dbSession.Pending,
+		HashState:       dbSession.HashState,
+		CreatedAt:       dbSession.CreatedAt,
+		UpdatedAt:       dbSession.UpdatedAt,
+		ExpiresAt:       dbSession.ExpiresAt,
+	}
+	if dbSession.ImageID.Valid {
+		imageID := dbSession.ImageID.UUID
+		session.ImageID = &imageID
+	}
+
+	if err := json.Unmarshal(dbSession.Options, &session.Options); err != nil {
+		return session, fmt.Errorf("unmarshal options: %w", err)
+	}
+	if err := json.Unmarshal(dbSession.Parts, &session.Parts); err != nil {
+		return session, fmt.Errorf("unmarshal parts: %w", err)
+	}
+
+	return session, nil
+}
+
+func ToDomainOutboxMessage(dbMessage gen.Outbox) (model.OutboxMessage, error) {
+	message := model.OutboxMessage{
+		ID:        dbMessage.ID,
+		Task:      &model.ProcessingImage{},
+		Attempts:  int(dbMessage.Attempts),
+		CreatedAt: dbMessage.CreatedAt,
+	}
+
+	if dbMessage.SentAt.Valid {
+		sentAt := dbMessage.SentAt.Time
+		message.SentAt = &sentAt
+	}
+	if dbMessage.FailedAt.Valid {
+		failedAt := dbMessage.FailedAt.Time
+		message.FailedAt = &failedAt
+	}
+
+	if err := json.Unmarshal(dbMessage.Payload, message.Task); err != nil {
+		message.Task = nil
+		return message, fmt.Errorf("unmarshal task: %w", err)
+	}
+
+	return message, nil
+}
+
+func ToDomainDeadLetter(dbLetter gen.DeadLetter) model.DeadLetter {
+	letter := model.DeadLetter{
+		ID:        dbLetter.ID,
+		Topic:     dbLetter.SourceTopic,
+		Partition: int(dbLetter.SourcePartition),
+		Offset:    dbLetter.SourceOffset,
+		Key:       dbLetter.MessageKey,
+		Payload:   string(dbLetter.Payload),
+		Error:     dbLetter.Error,
+		Attempts:  int(dbLetter.Attempts),
+		FailedAt:  dbLetter.FailedAt,
+		CreatedAt: dbLetter.CreatedAt,
+	}
+	if dbLetter.ReplayedAt.Valid {
+		replayedAt := dbLetter.ReplayedAt.Time
+		letter.ReplayedAt = &replayedAt
+	}
+
+	return letter
+}
+
+func ToDomainBatch(dbBatch gen.Batch, dbItems []gen.ListBatchItemsRow) model.Batch {
+	batch := model.Batch{
+		ID:        dbBatch.ID,
+		Total:     int(dbBatch.Total),
+		Items:     make([]model.BatchItem, 0, len(dbItems)),
+		CreatedAt: dbBatch.CreatedAt,
+		UpdatedAt: dbBatch.UpdatedAt,
+	}
+	if dbBatch.CompletedAt.Valid {
+		completedAt := dbBatch.CompletedAt.Time
+		batch.CompletedAt = &completedAt
+	}
+
+	for _, dbItem := range dbItems {
+		item := model.BatchItem{
+			Index:    int(dbItem.ItemIndex),
+			Filename: dbItem.Filename,
+			Status:   vo.StatusFailed,
+			Error:    dbItem.Error.String,
+		}
+		switch {
+		case dbItem.ImageID.Valid:
+			imageID := dbItem.ImageID.UUID
+			item.ImageID = &imageID
+			item.Status = vo.NewStatus(dbItem.Status.String)
+			if item.Status == vo.StatusFailed && item.Error == "" {
+				// failed before the item was saved with the batch
+				item.Error = "processing failed"
+			}
+		case item.Error == "":
+			item.Error = "image was deleted"
+		}
+		batch.Items = append(batch.Items, item)
+	}
+
+	return batch
+}
+
+func ToDomainWebhookDelivery(dbDelivery gen.WebhookDelivery) model.WebhookDelivery {
+	delivery := model.WebhookDelivery{
+		ID:            dbDelivery.ID,
+		ImageID:       dbDelivery.ImageID,
+		URL:           dbDelivery.Url,
+		Event:         dbDelivery.Event,
+		Payload:       dbDelivery.Payload,
+		Status:        vo.DeliveryStatus(dbDelivery.Status),
+		Attempts:      int(dbDelivery.Attempts),
+		NextAttemptAt: dbDelivery.NextAttemptAt,
+		LastError:     dbDelivery.LastError.String,
+		CreatedAt:     dbDelivery.CreatedAt,
+		UpdatedAt:     dbDelivery.UpdatedAt,
+	}
+	if dbDelivery.DeliveredAt.Valid {
+		deliveredAt := dbDelivery.DeliveredAt.Time
+		delivery.DeliveredAt = &deliveredAt
+	}
+
+	return delivery
+}
+
+func ToDomainWebhookAttempt(dbAttempt gen.WebhookAttempt) model.WebhookAttempt {
+	return model.WebhookAttempt{
+		Attempt:     int(dbAttempt.Attempt),
+		StatusCode:  int(dbAttempt.StatusCode.Int32),
+		Error:       dbAttempt.Error.String,
+		DurationMS:  dbAttempt.DurationMs,
+		AttemptedAt: dbAttempt.AttemptedAt,
+	}
+}
+
+func ToDomainStuckImage(dbImage gen.ClaimStuckImagesRow) model.StuckImage {
+	return model.StuckImage{
+		ImageID:          dbImage.ImageID,
+		Requeues:         int(dbImage.Requeues),
+		HasProcessedData: dbImage.HasProcessedData,
+	}
+}
@@ -2,15 +2,23 @@ package converters
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
 
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/gen"
 	"github.com/D1sordxr/image-processor/pkg/sqlutils"
+	"github.com/google/uuid"
 )
 
 func ToCreateImageParams(params options.ImageCreateParams) gen.CreateImageParams {
 	resultUrlStr := params.ResultURL.String()
+	var phash *int64
+	if params.PHash != nil {
+		v := int64(*params.PHash)
+		phash = &v
+	}
 	return gen.CreateImageParams{
 		ID:           params.ID,
 		OriginalName: params.OriginalName,
@@ -20,6 +28,26 @@ func ToCreateImageParams(params options.ImageCreateParams) gen.CreateImageParams
 		Size:         params.Size,
 		Format:       params.Format,
 		UploadedAt:   params.UploadedAt,
+		Sha256:       sqlutils.ToNullableString(&params.SHA256),
+		Phash:        sqlutils.ToNullableInt64(phash),
+	}
+}
+
+// ToListSimilarImagesParams конвертирует параметры поиска похожих изображений
+func ToListSimilarImagesParams(params options.SimilarImagesParams) gen.ListSimilarImagesParams {
+	return gen.ListSimilarImagesParams{
+		Phash:       int64(params.PHash),
+		ID:          params.ImageID,
+		MaxDistance: int32(params.MaxDistance),
+		Limit:       params.Limit,
+	}
+}
+
+func ToUpdateImagePhashParams(params options.ImagePHashUpdateParams) gen.UpdateImagePhashParams {
+	phash := int64(params.PHash)
+	return gen.UpdateImagePhashParams{
+		ID:    params.ImageID,
+		Phash: sqlutils.ToNullableInt64(&phash),
 	}
 }
 
@@ -33,10 +61,15 @@ func ToUpdateImageStatusParams(params options.ImageUpdateParams) gen.UpdateImage
 // ToCreateProcessedImageParams конвертирует параметры создания обработанного изображения
 func ToCreateProcessedImageParams(params options.ProcessedImageCreateParams) gen.CreateProcessedImageParams {
 	return gen.CreateProcessedImageParams{
-		ImageID:     params.ImageID,
-		Width:       int32(params.Width),
-		Height:      int32(params.Height),
-		ProcessedAt: params.ProcessedAt,
+		ImageID:       params.ImageID,
+		Width:         int32(params.Width),
+		Height:        int32(params.Height),
+		BlurHash:      sqlutils.ToNullableString(&params.BlurHash),
+		Lqip:          sqlutils.ToNullableString(&params.LQIP),
+		DominantColor: sqlutils.ToNullableString(&params.DominantColor),
+		ColorName:     sqlutils.ToNullableString(&params.ColorName),
+		Palette:       params.Palette,
+		ProcessedAt:   params.ProcessedAt,
 	}
 }
 
@@ -62,6 +95,7 @@ func ToListImagesWithFiltersParams(params options.ImageListParams) gen.ListImage
 	return gen.ListImagesWithFiltersParams{
 		Status:   toNullStringFromStatus(params.Status),
 		Format:   sqlutils.ToNullableString(params.Format),
+		Color:    sqlutils.ToNullableString(params.Color),
 		FromDate: sqlutils.ToNullableTime(params.FromDate),
 		ToDate:   sqlutils.ToNullableTime(params.ToDate),
 		Offset:   sqlutils.ToNullableInt32(params.Offset),
@@ -83,3 +117,227 @@ func toNullStringFromStatus(s *vo.Status) sql.NullString {
 	}
 	return sql.NullString{String: s.String(), Valid: true}
 }
+
+// ToCreateCardTemplateParams конвертирует параметры создания шаблона карточки
+func ToCreateCardTemplateParams(params options.CardTemplateCreateParams) (gen.CreateCardTemplateParams, error) {
+	imageSlots, err := json.Marshal(emptyIfNil(params.ImageSlots))
+	if err != nil {
+		return gen.CreateCardTemplateParams{}, fmt.Errorf("marshal image slots: %w", err)
+	}
+	textSlots, err := json.Marshal(emptyIfNil(params.TextSlots))
+	if err != nil {
+		return gen.CreateCardTemplateParams{}, fmt.Errorf("marshal text slots: %w", err)
+	}
+
+	return gen.CreateCardTemplateParams{
+		ID:         params.ID,
+		Name:       params.Name,
+		Width:      int32(params.Width),
+		Height:     int32(params.Height),
+		Background: params.Background,
+		ImageSlots: imageSlots,
+		TextSlots:  textSlots,
+		CreatedAt:  params.CreatedAt,
+	}, nil
+}
+
+// ToCreateUploadSessionParams конвертирует параметры создания сессии загрузки
+func ToCreateUploadSessionParams(params options.UploadSessionCreateParams) (gen.CreateUploadSessionParams, error) {
+	opts, err := json.Marshal(params.Options)
+	if err != nil {
+		return gen.CreateUploadSessionParams{}, fmt.Errorf("marshal options: %w", err)
+	}
+
+	return gen.CreateUploadSessionParams{
+		ID:              params.ID,
+		Filename:        params.Filename,
+		Size:            params.Size,
+		Options:         opts,
+		DuplicatePolicy: params.DuplicatePolicy.String(),
+		S3UploadID:      params.S3UploadID,
+		HashState:       emptyIfNil(params.HashState),
+		CreatedAt:       params.CreatedAt,
+		UpdatedAt:       params.CreatedAt,
+		ExpiresAt:       params.ExpiresAt,
+		Method:          params.Method.String(),
+	}, nil
+}
+
+// ToUpdateUploadSessionProgressParams конвертирует параметры обновления прогресса загрузки
+func ToUpdateUploadSessionProgressParams(params options.UploadSessionProgressParams) (gen.UpdateUploadSessionProgressParams, error) {
+	parts, err := json.Marshal(emptyIfNil(params.Parts))
+	if err != nil {
+		return gen.UpdateUploadSessionProgressParams{}, fmt.Errorf("marshal parts: %w", err)
+	}
+
+	return gen.UpdateUploadSessionProgressParams{
+		ID:           params.ID,
+		UploadOffset: params.Offset,
+		Parts:        parts,
+		Pending:      emptyIfNil(params.Pending),
+		HashState:    params.HashState,
+		UpdatedAt:    params.UpdatedAt,
+	}, nil
+}
+
+func ToCompleteUploadSessionParams(params options.UploadSessionCompleteParams) gen.CompleteUploadSessionParams {
+	return gen.CompleteUploadSessionParams{
+		ID:        params.ID,
+		ImageID:   uuid.NullUUID{UUID: params.ImageID, Valid: true},
+		UpdatedAt: params.UpdatedAt,
+	}
+}
+
+func ToCreateBatchParams(params options.BatchCreateParams) gen.CreateBatchParams {
+	return gen.CreateBatchParams{
+		ID:        params.ID,
+		Total:     int32(params.Total),
+		CreatedAt: params.CreatedAt,
+		UpdatedAt: params.CreatedAt,
+	}
+}
+
+func ToCreateBatchItemParams(params options.BatchItemCreateParams) gen.CreateBatchItemParams {
+	var imageID uuid.NullUUID
+	if params.ImageID != nil {
+		imageID = uuid.NullUUID{UUID: *params.ImageID, Valid: true}
+	}
+
+	return gen.CreateBatchItemParams{
+		BatchID:   params.BatchID,
+		ItemIndex: int32(params.Index),
+		Filename:  params.Filename,
+		ImageID:   imageID,
+		Error:     sql.NullString{String: params.Error, Valid: params.Error != ""},
+	}
+}
+
+func ToUpdateBatchItemsErrorParams(params options.BatchItemsErrorParams) gen.UpdateBatchItemsErrorParams {
+	return gen.UpdateBatchItemsErrorParams{
+		ImageID: uuid.NullUUID{UUID: params.ImageID, Valid: true},
+		Error:   sql.NullString{String: params.Error, Valid: params.Error != ""},
+	}
+}
+
+func ToCreateWebhookDeliveryParams(params options.WebhookDeliveryCreateParams) gen.CreateWebhookDeliveryParams {
+	return gen.CreateWebhookDeliveryParams{
+		ID:            params.ID,
+		ImageID:       params.ImageID,
+		Url:           params.URL,
+		Event:         params.Event,
+		Payload:       params.Payload,
+		Status:        vo.DeliveryStatusPending.String(),
+		NextAttemptAt: params.CreatedAt,
+		CreatedAt:     params.CreatedAt,
+		UpdatedAt:     params.CreatedAt,
+	}
+}
+
+func ToClaimDueWebhookDeliveriesParams(params options.WebhookClaimParams) gen.ClaimDueWebhookDeliveriesParams {
+	return gen.ClaimDueWebhookDeliveriesParams{
+		LeaseUntil: params.LeaseUntil,
+		Now:        params.Now,
+		Limit:      int32(params.Limit),
+	}
+}
+
+func ToCreateWebhookAttemptParams(params options.WebhookAttemptCreateParams) gen.CreateWebhookAttemptParams {
+	return gen.CreateWebhookAttemptParams{
+		DeliveryID:  params.DeliveryID,
+		Attempt:     int32(params.Attempt),
+		StatusCode:  sql.NullInt32{Int32: int32(params.StatusCode), Valid: params.StatusCode != 0},
+		Error:       sql.NullString{String: params.Error, Valid: params.Error != ""},
+		DurationMs:  params.Duration.Milliseconds(),
+		AttemptedAt: params.AttemptedAt,
+	}
+}
+
+func ToUpdateWebhookDeliveryResultParams(params options.WebhookDeliveryResultParams) gen.UpdateWebhookDeliveryResultParams {
+	var deliveredAt sql.NullTime
+	if params.DeliveredAt != nil {
+		deliveredAt = sql.NullTime{Time: *params.DeliveredAt, Valid: true}
+	}
+
+	return gen.UpdateWebhookDeliveryResultParams{
+		ID:            params.ID,
+		Status:        params.Status.String(),
+		Attempts:      int32(params.Attempts),
+		NextAttemptAt: params.NextAttemptAt,
+		LastError:     sql.NullString{String: params.LastError, Valid: params.LastError != ""},
+		UpdatedAt:     params.UpdatedAt,
+		DeliveredAt:   deliveredAt,
+	}
+}
+
+func ToCreateOutboxMessageParams(params options.OutboxMessageCreateParams) (gen.CreateOutboxMessageParams, error) {
+	payload, err := json.Marshal(params.Task)
+	if err != nil {
+		return gen.CreateOutboxMessageParams{}, fmt.Errorf("marshal task: %w", err)
+	}
+
+	return gen.CreateOutboxMessageParams{
+		ID:            params.ID,
+		MessageKey:    params.Task.ImageID,
+		Payload:       payload,
+		CreatedAt:     params.CreatedAt,
+		NextAttemptAt: params.CreatedAt,
+	}, nil
+}
+
+func ToClaimPendingOutboxMessagesParams(params options.OutboxClaimParams) gen.ClaimPendingOutboxMessagesParams {
+	return gen.ClaimPendingOutboxMessagesParams{
+		LeaseUntil: params.LeaseUntil,
+		Now:        params.Now,
+		Limit:      int32(params.Limit),
+	}
+}
+
+func ToClaimStuckImagesParams(params options.StuckImageClaimParams) gen.ClaimStuckImagesParams {
+	return gen.ClaimStuckImagesParams{
+		TimeoutSeconds: params.Timeout.Seconds(),
+		Limit:          int32(params.Limit),
+	}
+}
+
+func ToMarkOutboxMessageFailedParams(params options.OutboxFailureParams) gen.MarkOutboxMessageFailedParams {
+	var failedAt sql.NullTime
+	if params.FailedAt != nil {
+		failedAt = sql.NullTime{Time: *params.FailedAt, Valid: true}
+	}
+
+	return gen.MarkOutboxMessageFailedParams{
+		ID:            params.ID,
+		LastError:     sql.NullString{String: params.Error, Valid: params.Error != ""},
+		NextAttemptAt: params.NextAttemptAt,
+		FailedAt:      failedAt,
+	}
+}
+
+func ToCreateDeadLetterParams(params options.DeadLetterCreateParams) gen.CreateDeadLetterParams {
+	return gen.CreateDeadLetterParams{
+		ID:              params.ID,
+		SourceTopic:     params.Topic,
+		SourcePartition: int32(params.Partition),
+		SourceOffset:    params.Offset,
+		MessageKey:      params.Key,
+		Payload:         []byte(params.Payload),
+		Error:           params.Error,
+		Attempts:        int32(params.Attempts),
+		FailedAt:        params.FailedAt,
+		CreatedAt:       params.CreatedAt,
+	}
+}
+
+func ToListDeadLettersParams(params options.PaginationParams) gen.ListDeadLettersParams {
+	return gen.ListDeadLettersParams{
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+}
+
+func emptyIfNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
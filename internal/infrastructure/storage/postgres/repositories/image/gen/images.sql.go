@@ -11,15 +11,30 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const cancelImage = `-- name: CancelImage :execrows
+UPDATE images
+SET status = 'cancelled'
+WHERE id = $1 AND status IN ('uploaded', 'processing')
+`
+
+func (q *Queries) CancelImage(ctx context.Context, db DBTX, id uuid.UUID) (int64, error) {
+	result, err := db.ExecContext(ctx, cancelImage, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createImage = `-- name: CreateImage :one
 INSERT INTO images (
-    id, original_name, file_name, status, result_url, size, format, uploaded_at
+    id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash
 ) VALUES (
-             $1, $2, $3, $4, $5, $6, $7, $8
+             $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
          )
-    RETURNING id, original_name, file_name, status, result_url, size, format, uploaded_at
+    RETURNING id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash
 `
 
 type CreateImageParams struct {
@@ -31,6 +46,8 @@ type CreateImageParams struct {
 	Size         int64          `json:"size"`
 	Format       string         `json:"format"`
 	UploadedAt   time.Time      `json:"uploaded_at"`
+	Sha256       sql.NullString `json:"sha256"`
+	Phash        sql.NullInt64  `json:"phash"`
 }
 
 func (q *Queries) CreateImage(ctx context.Context, db DBTX, arg CreateImageParams) (Image, error) {
@@ -43,6 +60,8 @@ func (q *Queries) CreateImage(ctx context.Context, db DBTX, arg CreateImageParam
 		arg.Size,
 		arg.Format,
 		arg.UploadedAt,
+		arg.Sha256,
+		arg.Phash,
 	)
 	var i Image
 	err := row.Scan(
@@ -54,24 +73,31 @@ func (q *Queries) CreateImage(ctx context.Context, db DBTX, arg CreateImageParam
 		&i.Size,
 		&i.Format,
 		&i.UploadedAt,
+		&i.Sha256,
+		&i.Phash,
 	)
 	return i, err
 }
 
 const createProcessedImage = `-- name: CreateProcessedImage :one
 INSERT INTO processed_images (
-    image_id, width, height, processed_at
+    image_id, width, height, blur_hash, lqip, dominant_color, color_name, palette, processed_at
 ) VALUES (
-             $1, $2, $3, $4
+             $1, $2, $3, $4, $5, $6, $7, $8, $9
          )
-    RETURNING image_id, width, height, processed_at
+    RETURNING image_id, width, height, processed_at, blur_hash, lqip, dominant_color, color_name, palette
 `
 
 type CreateProcessedImageParams struct {
-	ImageID     uuid.UUID `json:"image_id"`
-	Width       int32     `json:"width"`
-	Height      int32     `json:"height"`
-	ProcessedAt time.Time `json:"processed_at"`
+	ImageID       uuid.UUID      `json:"image_id"`
+	Width         int32          `json:"width"`
+	Height        int32          `json:"height"`
+	BlurHash      sql.NullString `json:"blur_hash"`
+	Lqip          sql.NullString `json:"lqip"`
+	DominantColor sql.NullString `json:"dominant_color"`
+	ColorName     sql.NullString `json:"color_name"`
+	Palette       []string       `json:"palette"`
+	ProcessedAt   time.Time      `json:"processed_at"`
 }
 
 func (q *Queries) CreateProcessedImage(ctx context.Context, db DBTX, arg CreateProcessedImageParams) (ProcessedImage, error) {
@@ -79,6 +105,11 @@ func (q *Queries) CreateProcessedImage(ctx context.Context, db DBTX, arg CreateP
 		arg.ImageID,
 		arg.Width,
 		arg.Height,
+		arg.BlurHash,
+		arg.Lqip,
+		arg.DominantColor,
+		arg.ColorName,
+		pq.Array(arg.Palette),
 		arg.ProcessedAt,
 	)
 	var i ProcessedImage
@@ -87,6 +118,11 @@ func (q *Queries) CreateProcessedImage(ctx context.Context, db DBTX, arg CreateP
 		&i.Width,
 		&i.Height,
 		&i.ProcessedAt,
+		&i.BlurHash,
+		&i.Lqip,
+		&i.DominantColor,
+		&i.ColorName,
+		pq.Array(&i.Palette),
 	)
 	return i, err
 }
@@ -112,7 +148,7 @@ func (q *Queries) DeleteProcessedImage(ctx context.Context, db DBTX, imageID uui
 }
 
 const getImageByID = `-- name: GetImageByID :one
-SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at FROM images
+SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash FROM images
 WHERE id = $1 LIMIT 1
 `
 
@@ -128,15 +164,46 @@ func (q *Queries) GetImageByID(ctx context.Context, db DBTX, id uuid.UUID) (Imag
 		&i.Size,
 		&i.Format,
 		&i.UploadedAt,
+		&i.Sha256,
+		&i.Phash,
+	)
+	return i, err
+}
+
+const getImageByIDForUpdate = `-- name: GetImageByIDForUpdate :one
+SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash FROM images
+WHERE id = $1 LIMIT 1
+    FOR UPDATE
+`
+
+func (q *Queries) GetImageByIDForUpdate(ctx context.Context, db DBTX, id uuid.UUID) (Image, error) {
+	row := db.QueryRowContext(ctx, getImageByIDForUpdate, id)
+	var i Image
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalName,
+		&i.FileName,
+		&i.Status,
+		&i.ResultUrl,
+		&i.Size,
+		&i.Format,
+		&i.UploadedAt,
+		&i.Sha256,
+		&i.Phash,
 	)
 	return i, err
 }
 
 const getImageWithProcessedData = `-- name: GetImageWithProcessedData :one
 SELECT
-    i.id, i.original_name, i.file_name, i.status, i.result_url, i.size, i.format, i.uploaded_at,
+    i.id, i.original_name, i.file_name, i.status, i.result_url, i.size, i.format, i.uploaded_at, i.sha256, i.phash,
     p.width,
     p.height,
+    p.blur_hash,
+    p.lqip,
+    p.dominant_color,
+    p.color_name,
+    p.palette,
     p.processed_at
 FROM images i
          LEFT JOIN processed_images p ON i.id = p.image_id
@@ -144,17 +211,24 @@ WHERE i.id = $1
 `
 
 type GetImageWithProcessedDataRow struct {
-	ID           uuid.UUID      `json:"id"`
-	OriginalName string         `json:"original_name"`
-	FileName     string         `json:"file_name"`
-	Status       string         `json:"status"`
-	ResultUrl    sql.NullString `json:"result_url"`
-	Size         int64          `json:"size"`
-	Format       string         `json:"format"`
-	UploadedAt   time.Time      `json:"uploaded_at"`
-	Width        sql.NullInt32  `json:"width"`
-	Height       sql.NullInt32  `json:"height"`
-	ProcessedAt  sql.NullTime   `json:"processed_at"`
+	ID            uuid.UUID      `json:"id"`
+	OriginalName  string         `json:"original_name"`
+	FileName      string         `json:"file_name"`
+	Status        string         `json:"status"`
+	ResultUrl     sql.NullString `json:"result_url"`
+	Size          int64          `json:"size"`
+	Format        string         `json:"format"`
+	UploadedAt    time.Time      `json:"uploaded_at"`
+	Sha256        sql.NullString `json:"sha256"`
+	Phash         sql.NullInt64  `json:"phash"`
+	Width         sql.NullInt32  `json:"width"`
+	Height        sql.NullInt32  `json:"height"`
+	BlurHash      sql.NullString `json:"blur_hash"`
+	Lqip          sql.NullString `json:"lqip"`
+	DominantColor sql.NullString `json:"dominant_color"`
+	ColorName     sql.NullString `json:"color_name"`
+	Palette       []string       `json:"palette"`
+	ProcessedAt   sql.NullTime   `json:"processed_at"`
 }
 
 func (q *Queries) GetImageWithProcessedData(ctx context.Context, db DBTX, id uuid.UUID) (GetImageWithProcessedDataRow, error) {
@@ -169,15 +243,22 @@ func (q *Queries) GetImageWithProcessedData(ctx context.Context, db DBTX, id uui
 		&i.Size,
 		&i.Format,
 		&i.UploadedAt,
+		&i.Sha256,
+		&i.Phash,
 		&i.Width,
 		&i.Height,
+		&i.BlurHash,
+		&i.Lqip,
+		&i.DominantColor,
+		&i.ColorName,
+		pq.Array(&i.Palette),
 		&i.ProcessedAt,
 	)
 	return i, err
 }
 
 const getImagesByFileName = `-- name: GetImagesByFileName :many
-SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at FROM images
+SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash FROM images
 WHERE file_name = $1
 ORDER BY uploaded_at DESC
 `
@@ -200,6 +281,48 @@ func (q *Queries) GetImagesByFileName(ctx context.Context, db DBTX, fileName str
 			&i.Size,
 			&i.Format,
 			&i.UploadedAt,
+			&i.Sha256,
+			&i.Phash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getImagesBySHA256 = `-- name: GetImagesBySHA256 :many
+SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash FROM images
+WHERE sha256 = $1
+ORDER BY uploaded_at
+`
+
+func (q *Queries) GetImagesBySHA256(ctx context.Context, db DBTX, sha256 sql.NullString) ([]Image, error) {
+	rows, err := db.QueryContext(ctx, getImagesBySHA256, sha256)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Image{}
+	for rows.Next() {
+		var i Image
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalName,
+			&i.FileName,
+			&i.Status,
+			&i.ResultUrl,
+			&i.Size,
+			&i.Format,
+			&i.UploadedAt,
+			&i.Sha256,
+			&i.Phash,
 		); err != nil {
 			return nil, err
 		}
@@ -246,7 +369,7 @@ func (q *Queries) GetImagesStats(ctx context.Context, db DBTX) (GetImagesStatsRo
 }
 
 const getProcessedImage = `-- name: GetProcessedImage :one
-SELECT image_id, width, height, processed_at FROM processed_images
+SELECT image_id, width, height, processed_at, blur_hash, lqip, dominant_color, color_name, palette FROM processed_images
 WHERE image_id = $1 LIMIT 1
 `
 
@@ -258,13 +381,18 @@ func (q *Queries) GetProcessedImage(ctx context.Context, db DBTX, imageID uuid.U
 		&i.Width,
 		&i.Height,
 		&i.ProcessedAt,
+		&i.BlurHash,
+		&i.Lqip,
+		&i.DominantColor,
+		&i.ColorName,
+		pq.Array(&i.Palette),
 	)
 	return i, err
 }
 
 const getRecentProcessedImages = `-- name: GetRecentProcessedImages :many
 SELECT
-    i.id, i.original_name, i.file_name, i.status, i.result_url, i.size, i.format, i.uploaded_at,
+    i.id, i.original_name, i.file_name, i.status, i.result_url, i.size, i.format, i.uploaded_at, i.sha256, i.phash,
     p.width,
     p.height,
     p.processed_at
@@ -289,6 +417,8 @@ type GetRecentProcessedImagesRow struct {
 	Size         int64          `json:"size"`
 	Format       string         `json:"format"`
 	UploadedAt   time.Time      `json:"uploaded_at"`
+	Sha256       sql.NullString `json:"sha256"`
+	Phash        sql.NullInt64  `json:"phash"`
 	Width        int32          `json:"width"`
 	Height       int32          `json:"height"`
 	ProcessedAt  time.Time      `json:"processed_at"`
@@ -312,6 +442,8 @@ func (q *Queries) GetRecentProcessedImages(ctx context.Context, db DBTX, arg Get
 			&i.Size,
 			&i.Format,
 			&i.UploadedAt,
+			&i.Sha256,
+			&i.Phash,
 			&i.Width,
 			&i.Height,
 			&i.ProcessedAt,
@@ -329,8 +461,36 @@ func (q *Queries) GetRecentProcessedImages(ctx context.Context, db DBTX, arg Get
 	return items, nil
 }
 
+const listExistingImageIDs = `-- name: ListExistingImageIDs :many
+SELECT id FROM images
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) ListExistingImageIDs(ctx context.Context, db DBTX, ids []uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := db.QueryContext(ctx, listExistingImageIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listImages = `-- name: ListImages :many
-SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at FROM images
+SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash FROM images
 ORDER BY uploaded_at DESC
     LIMIT $1 OFFSET $2
 `
@@ -358,6 +518,8 @@ func (q *Queries) ListImages(ctx context.Context, db DBTX, arg ListImagesParams)
 			&i.Size,
 			&i.Format,
 			&i.UploadedAt,
+			&i.Sha256,
+			&i.Phash,
 		); err != nil {
 			return nil, err
 		}
@@ -373,7 +535,7 @@ func (q *Queries) ListImages(ctx context.Context, db DBTX, arg ListImagesParams)
 }
 
 const listImagesByStatus = `-- name: ListImagesByStatus :many
-SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at FROM images
+SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash FROM images
 WHERE status = $1
 ORDER BY uploaded_at DESC
     LIMIT $2 OFFSET $3
@@ -403,6 +565,8 @@ func (q *Queries) ListImagesByStatus(ctx context.Context, db DBTX, arg ListImage
 			&i.Size,
 			&i.Format,
 			&i.UploadedAt,
+			&i.Sha256,
+			&i.Phash,
 		); err != nil {
 			return nil, err
 		}
@@ -418,19 +582,23 @@ func (q *Queries) ListImagesByStatus(ctx context.Context, db DBTX, arg ListImage
 }
 
 const listImagesWithFilters = `-- name: ListImagesWithFilters :many
-SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at FROM images
+SELECT id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash FROM images
 WHERE
     ($1::VARCHAR IS NULL OR status = $1) AND
     ($2::VARCHAR IS NULL OR format = $2) AND
-    ($3::TIMESTAMP IS NULL OR uploaded_at >= $3) AND
-    ($4::TIMESTAMP IS NULL OR uploaded_at <= $4)
+    ($3::VARCHAR IS NULL OR id IN (
+        SELECT image_id FROM processed_images WHERE color_name = $3
+    )) AND
+    ($4::TIMESTAMP IS NULL OR uploaded_at >= $4) AND
+    ($5::TIMESTAMP IS NULL OR uploaded_at <= $5)
 ORDER BY uploaded_at DESC
-    LIMIT $6 OFFSET $5
+    LIMIT $7 OFFSET $6
 `
 
 type ListImagesWithFiltersParams struct {
 	Status   sql.NullString `json:"status"`
 	Format   sql.NullString `json:"format"`
+	Color    sql.NullString `json:"color"`
 	FromDate sql.NullTime   `json:"from_date"`
 	ToDate   sql.NullTime   `json:"to_date"`
 	Offset   sql.NullInt32  `json:"offset"`
@@ -441,6 +609,7 @@ func (q *Queries) ListImagesWithFilters(ctx context.Context, db DBTX, arg ListIm
 	rows, err := db.QueryContext(ctx, listImagesWithFilters,
 		arg.Status,
 		arg.Format,
+		arg.Color,
 		arg.FromDate,
 		arg.ToDate,
 		arg.Offset,
@@ -462,6 +631,81 @@ func (q *Queries) ListImagesWithFilters(ctx context.Context, db DBTX, arg ListIm
 			&i.Size,
 			&i.Format,
 			&i.UploadedAt,
+			&i.Sha256,
+			&i.Phash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSimilarImages = `-- name: ListSimilarImages :many
+SELECT
+    i.id, i.original_name, i.file_name, i.status, i.result_url, i.size, i.format, i.uploaded_at, i.sha256, i.phash,
+    bit_count((i.phash # $1::BIGINT)::BIT(64))::INT AS distance
+FROM images i
+WHERE i.id <> $2
+  AND i.phash IS NOT NULL
+  AND bit_count((i.phash # $1::BIGINT)::BIT(64)) <= $3::INT
+ORDER BY distance, i.uploaded_at DESC
+    LIMIT $4
+`
+
+type ListSimilarImagesParams struct {
+	Phash       int64     `json:"phash"`
+	ID          uuid.UUID `json:"id"`
+	MaxDistance int32     `json:"max_distance"`
+	Limit       int32     `json:"limit"`
+}
+
+type ListSimilarImagesRow struct {
+	ID           uuid.UUID      `json:"id"`
+	OriginalName string         `json:"original_name"`
+	FileName     string         `json:"file_name"`
+	Status       string         `json:"status"`
+	ResultUrl    sql.NullString `json:"result_url"`
+	Size         int64          `json:"size"`
+	Format       string         `json:"format"`
+	UploadedAt   time.Time      `json:"uploaded_at"`
+	Sha256       sql.NullString `json:"sha256"`
+	Phash        sql.NullInt64  `json:"phash"`
+	Distance     int32          `json:"distance"`
+}
+
+func (q *Queries) ListSimilarImages(ctx context.Context, db DBTX, arg ListSimilarImagesParams) ([]ListSimilarImagesRow, error) {
+	rows, err := db.QueryContext(ctx, listSimilarImages,
+		arg.Phash,
+		arg.ID,
+		arg.MaxDistance,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSimilarImagesRow{}
+	for rows.Next() {
+		var i ListSimilarImagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalName,
+			&i.FileName,
+			&i.Status,
+			&i.ResultUrl,
+			&i.Size,
+			&i.Format,
+			&i.UploadedAt,
+			&i.Sha256,
+			&i.Phash,
+			&i.Distance,
 		); err != nil {
 			return nil, err
 		}
@@ -482,7 +726,7 @@ SET
     status = COALESCE($2, status),
     result_url = COALESCE($3, result_url)
 WHERE id = $1
-    RETURNING id, original_name, file_name, status, result_url, size, format, uploaded_at
+    RETURNING id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash
 `
 
 type UpdateImageParams struct {
@@ -503,15 +747,33 @@ func (q *Queries) UpdateImage(ctx context.Context, db DBTX, arg UpdateImageParam
 		&i.Size,
 		&i.Format,
 		&i.UploadedAt,
+		&i.Sha256,
+		&i.Phash,
 	)
 	return i, err
 }
 
+const updateImagePhash = `-- name: UpdateImagePhash :exec
+UPDATE images
+SET phash = $2
+WHERE id = $1
+`
+
+type UpdateImagePhashParams struct {
+	ID    uuid.UUID     `json:"id"`
+	Phash sql.NullInt64 `json:"phash"`
+}
+
+func (q *Queries) UpdateImagePhash(ctx context.Context, db DBTX, arg UpdateImagePhashParams) error {
+	_, err := db.ExecContext(ctx, updateImagePhash, arg.ID, arg.Phash)
+	return err
+}
+
 const updateImageStatus = `-- name: UpdateImageStatus :one
 UPDATE images
 SET status = $2
 WHERE id = $1
-    RETURNING id, original_name, file_name, status, result_url, size, format, uploaded_at
+    RETURNING id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash
 `
 
 type UpdateImageStatusParams struct {
@@ -531,6 +793,8 @@ func (q *Queries) UpdateImageStatus(ctx context.Context, db DBTX, arg UpdateImag
 		&i.Size,
 		&i.Format,
 		&i.UploadedAt,
+		&i.Sha256,
+		&i.Phash,
 	)
 	return i, err
 }
@@ -542,7 +806,7 @@ SET
     height = $3,
     processed_at = $4
 WHERE image_id = $1
-    RETURNING image_id, width, height, processed_at
+    RETURNING image_id, width, height, processed_at, blur_hash, lqip, dominant_color, color_name, palette
 `
 
 type UpdateProcessedImageParams struct {
@@ -565,6 +829,11 @@ func (q *Queries) UpdateProcessedImage(ctx context.Context, db DBTX, arg UpdateP
 		&i.Width,
 		&i.Height,
 		&i.ProcessedAt,
+		&i.BlurHash,
+		&i.Lqip,
+		&i.DominantColor,
+		&i.ColorName,
+		pq.Array(&i.Palette),
 	)
 	return i, err
 }
@@ -6,11 +6,53 @@ package gen
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type Batch struct {
+	ID          uuid.UUID    `json:"id"`
+	Total       int32        `json:"total"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	CompletedAt sql.NullTime `json:"completed_at"`
+}
+
+type BatchItem struct {
+	BatchID   uuid.UUID      `json:"batch_id"`
+	ItemIndex int32          `json:"item_index"`
+	Filename  string         `json:"filename"`
+	ImageID   uuid.NullUUID  `json:"image_id"`
+	Error     sql.NullString `json:"error"`
+}
+
+type CardTemplate struct {
+	ID         uuid.UUID       `json:"id"`
+	Name       string          `json:"name"`
+	Width      int32           `json:"width"`
+	Height     int32           `json:"height"`
+	Background string          `json:"background"`
+	ImageSlots json.RawMessage `json:"image_slots"`
+	TextSlots  json.RawMessage `json:"text_slots"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type DeadLetter struct {
+	ID              uuid.UUID    `json:"id"`
+	SourceTopic     string       `json:"source_topic"`
+	SourcePartition int32        `json:"source_partition"`
+	SourceOffset    int64        `json:"source_offset"`
+	MessageKey      string       `json:"message_key"`
+	Payload         []byte       `json:"payload"`
+	Error           string       `json:"error"`
+	Attempts        int32        `json:"attempts"`
+	FailedAt        time.Time    `json:"failed_at"`
+	CreatedAt       time.Time    `json:"created_at"`
+	ReplayedAt      sql.NullTime `json:"replayed_at"`
+}
+
 type Image struct {
 	ID           uuid.UUID      `json:"id"`
 	OriginalName string         `json:"original_name"`
@@ -20,11 +62,78 @@ type Image struct {
 	Size         int64          `json:"size"`
 	Format       string         `json:"format"`
 	UploadedAt   time.Time      `json:"uploaded_at"`
+	Sha256       sql.NullString `json:"sha256"`
+	Phash        sql.NullInt64  `json:"phash"`
+}
+
+type ImageProcessing struct {
+	ImageID   uuid.UUID `json:"image_id"`
+	StartedAt time.Time `json:"started_at"`
+	Requeues  int32     `json:"requeues"`
+}
+
+type Outbox struct {
+	ID            uuid.UUID       `json:"id"`
+	MessageKey    string          `json:"message_key"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int32           `json:"attempts"`
+	LastError     sql.NullString  `json:"last_error"`
+	CreatedAt     time.Time       `json:"created_at"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	SentAt        sql.NullTime    `json:"sent_at"`
+	FailedAt      sql.NullTime    `json:"failed_at"`
 }
 
 type ProcessedImage struct {
-	ImageID     uuid.UUID `json:"image_id"`
-	Width       int32     `json:"width"`
-	Height      int32     `json:"height"`
-	ProcessedAt time.Time `json:"processed_at"`
+	ImageID       uuid.UUID      `json:"image_id"`
+	Width         int32          `json:"width"`
+	Height        int32          `json:"height"`
+	ProcessedAt   time.Time      `json:"processed_at"`
+	BlurHash      sql.NullString `json:"blur_hash"`
+	Lqip          sql.NullString `json:"lqip"`
+	DominantColor sql.NullString `json:"dominant_color"`
+	ColorName     sql.NullString `json:"color_name"`
+	Palette       []string       `json:"palette"`
+}
+
+type UploadSession struct {
+	ID              uuid.UUID       `json:"id"`
+	Filename        string          `json:"filename"`
+	Size            int64           `json:"size"`
+	UploadOffset    int64           `json:"upload_offset"`
+	Options         json.RawMessage `json:"options"`
+	DuplicatePolicy string          `json:"duplicate_policy"`
+	S3UploadID      string          `json:"s3_upload_id"`
+	Parts           json.RawMessage `json:"parts"`
+	Pending         []byte          `json:"pending"`
+	HashState       []byte          `json:"hash_state"`
+	ImageID         uuid.NullUUID   `json:"image_id"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	ExpiresAt       time.Time       `json:"expires_at"`
+	Method          string          `json:"method"`
+}
+
+type WebhookAttempt struct {
+	DeliveryID  uuid.UUID      `json:"delivery_id"`
+	Attempt     int32          `json:"attempt"`
+	StatusCode  sql.NullInt32  `json:"status_code"`
+	Error       sql.NullString `json:"error"`
+	DurationMs  int64          `json:"duration_ms"`
+	AttemptedAt time.Time      `json:"attempted_at"`
+}
+
+type WebhookDelivery struct {
+	ID            uuid.UUID       `json:"id"`
+	ImageID       uuid.UUID       `json:"image_id"`
+	Url           string          `json:"url"`
+	Event         string          `json:"event"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int32           `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     sql.NullString  `json:"last_error"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	DeliveredAt   sql.NullTime    `json:"delivered_at"`
 }
@@ -0,0 +1,65 @@
+-- name: CreateBatch :one
+INSERT INTO batches (
+    id, total, created_at, updated_at
+) VALUES (
+             $1, $2, $3, $4
+         )
+    RETURNING *;
+
+-- name: CreateBatchItem :exec
+INSERT INTO batch_items (
+    batch_id, item_index, filename, image_id, error
+) VALUES (
+             $1, $2, $3, $4, $5
+         );
+
+-- name: GetBatchByID :one
+SELECT * FROM batches
+WHERE id = $1 LIMIT 1;
+
+-- name: ListBatchItems :many
+SELECT
+    bi.item_index,
+    bi.filename,
+    bi.image_id,
+    bi.error,
+    i.status
+FROM batch_items bi
+         LEFT JOIN images i ON i.id = bi.image_id
+WHERE bi.batch_id = $1
+ORDER BY bi.item_index;
+
+-- name: RefreshBatch :exec
+UPDATE batches b
+SET
+    updated_at = sqlc.arg('updated_at'),
+    completed_at = CASE WHEN (
+        (SELECT count(*) FROM batch_items bi WHERE bi.batch_id = b.id) = b.total AND
+        NOT EXISTS (
+            SELECT 1 FROM batch_items bi
+                     JOIN images i ON i.id = bi.image_id
+            WHERE bi.batch_id = b.id AND i.status IN ('uploaded', 'processing')
+        )
+    ) THEN sqlc.arg('updated_at') END
+WHERE b.id = sqlc.arg('id') AND b.completed_at IS NULL;
+
+-- name: RefreshBatchesByImage :exec
+UPDATE batches b
+SET
+    updated_at = sqlc.arg('updated_at'),
+    completed_at = CASE WHEN (
+        (SELECT count(*) FROM batch_items bi WHERE bi.batch_id = b.id) = b.total AND
+        NOT EXISTS (
+            SELECT 1 FROM batch_items bi
+                     JOIN images i ON i.id = bi.image_id
+            WHERE bi.batch_id = b.id AND i.status IN ('uploaded', 'processing')
+        )
+    ) THEN sqlc.arg('updated_at') END
+WHERE b.id IN (
+    SELECT batch_id FROM batch_items WHERE image_id = sqlc.arg('image_id')
+) AND b.completed_at IS NULL;
+
+-- name: UpdateBatchItemsError :exec
+UPDATE batch_items
+SET error = $2
+WHERE image_id = $1;
@@ -0,0 +1,20 @@
+-- name: CreateCardTemplate :one
+INSERT INTO card_templates (
+    id, name, width, height, background, image_slots, text_slots, created_at
+) VALUES (
+             $1, $2, $3, $4, $5, $6, $7, $8
+         )
+    RETURNING *;
+
+-- name: GetCardTemplateByID :one
+SELECT * FROM card_templates
+WHERE id = $1 LIMIT 1;
+
+-- name: ListCardTemplates :many
+SELECT * FROM card_templates
+ORDER BY name
+    LIMIT $1 OFFSET $2;
+
+-- name: DeleteCardTemplate :execrows
+DELETE FROM card_templates
+WHERE id = $1;
@@ -0,0 +1,21 @@
+-- name: CreateDeadLetter :execrows
+INSERT INTO dead_letters (
+    id, source_topic, source_partition, source_offset, message_key, payload, error, attempts, failed_at, created_at
+) VALUES (
+             $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+         )
+    ON CONFLICT (source_topic, source_partition, source_offset) DO NOTHING;
+
+-- name: GetDeadLetterByID :one
+SELECT * FROM dead_letters
+WHERE id = $1 LIMIT 1;
+
+-- name: ListDeadLetters :many
+SELECT * FROM dead_letters
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: MarkDeadLetterReplayed :execrows
+UPDATE dead_letters
+SET replayed_at = $2
+WHERE id = $1 AND replayed_at IS NULL;
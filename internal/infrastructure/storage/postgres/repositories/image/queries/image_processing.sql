@@ -0,0 +1,23 @@
+-- name: ClaimStuckImages :many
+WITH stuck AS (
+    SELECT image_id FROM image_processing
+    WHERE started_at < LOCALTIMESTAMP - make_interval(secs => sqlc.arg('timeout_seconds')::float8)
+    ORDER BY started_at
+    LIMIT sqlc.arg('limit')
+        FOR UPDATE SKIP LOCKED
+)
+UPDATE image_processing p
+SET started_at = LOCALTIMESTAMP
+FROM stuck
+WHERE p.image_id = stuck.image_id
+    RETURNING
+    p.image_id,
+    p.requeues,
+    EXISTS (
+        SELECT 1 FROM processed_images pi WHERE pi.image_id = p.image_id
+    )::bool AS has_processed_data;
+
+-- name: IncrementImageRequeues :execrows
+UPDATE image_processing
+SET requeues = requeues + 1
+WHERE image_id = $1;
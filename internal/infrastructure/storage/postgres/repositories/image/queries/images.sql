@@ -2,11 +2,21 @@
 SELECT * FROM images
 WHERE id = $1 LIMIT 1;
 
+-- name: GetImageByIDForUpdate :one
+SELECT * FROM images
+WHERE id = $1 LIMIT 1
+    FOR UPDATE;
+
 -- name: GetImageWithProcessedData :one
 SELECT
     i.*,
     p.width,
     p.height,
+    p.blur_hash,
+    p.lqip,
+    p.dominant_color,
+    p.color_name,
+    p.palette,
     p.processed_at
 FROM images i
          LEFT JOIN processed_images p ON i.id = p.image_id
@@ -14,9 +24,9 @@ WHERE i.id = $1;
 
 -- name: CreateImage :one
 INSERT INTO images (
-    id, original_name, file_name, status, result_url, size, format, uploaded_at
+    id, original_name, file_name, status, result_url, size, format, uploaded_at, sha256, phash
 ) VALUES (
-             $1, $2, $3, $4, $5, $6, $7, $8
+             $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
          )
     RETURNING *;
 
@@ -26,6 +36,11 @@ SET status = $2
 WHERE id = $1
     RETURNING *;
 
+-- name: UpdateImagePhash :exec
+UPDATE images
+SET phash = $2
+WHERE id = $1;
+
 -- name: UpdateImage :one
 UPDATE images
 SET
@@ -34,6 +49,11 @@ SET
 WHERE id = $1
     RETURNING *;
 
+-- name: CancelImage :execrows
+UPDATE images
+SET status = 'cancelled'
+WHERE id = $1 AND status IN ('uploaded', 'processing');
+
 -- name: DeleteImage :exec
 DELETE FROM images
 WHERE id = $1;
@@ -43,6 +63,10 @@ SELECT * FROM images
 ORDER BY uploaded_at DESC
     LIMIT $1 OFFSET $2;
 
+-- name: ListExistingImageIDs :many
+SELECT id FROM images
+WHERE id = ANY(sqlc.arg('ids')::uuid[]);
+
 -- name: ListImagesByStatus :many
 SELECT * FROM images
 WHERE status = $1
@@ -54,6 +78,9 @@ SELECT * FROM images
 WHERE
     (sqlc.narg('status')::VARCHAR IS NULL OR status = sqlc.narg('status')) AND
     (sqlc.narg('format')::VARCHAR IS NULL OR format = sqlc.narg('format')) AND
+    (sqlc.narg('color')::VARCHAR IS NULL OR id IN (
+        SELECT image_id FROM processed_images WHERE color_name = sqlc.narg('color')
+    )) AND
     (sqlc.narg('from_date')::TIMESTAMP IS NULL OR uploaded_at >= sqlc.narg('from_date')) AND
     (sqlc.narg('to_date')::TIMESTAMP IS NULL OR uploaded_at <= sqlc.narg('to_date'))
 ORDER BY uploaded_at DESC
@@ -64,11 +91,27 @@ SELECT * FROM images
 WHERE file_name = $1
 ORDER BY uploaded_at DESC;
 
+-- name: GetImagesBySHA256 :many
+SELECT * FROM images
+WHERE sha256 = $1
+ORDER BY uploaded_at;
+
+-- name: ListSimilarImages :many
+SELECT
+    i.*,
+    bit_count((i.phash # sqlc.arg('phash')::BIGINT)::BIT(64))::INT AS distance
+FROM images i
+WHERE i.id <> sqlc.arg('id')
+  AND i.phash IS NOT NULL
+  AND bit_count((i.phash # sqlc.arg('phash')::BIGINT)::BIT(64)) <= sqlc.arg('max_distance')::INT
+ORDER BY distance, i.uploaded_at DESC
+    LIMIT sqlc.arg('limit');
+
 -- name: CreateProcessedImage :one
 INSERT INTO processed_images (
-    image_id, width, height, processed_at
+    image_id, width, height, blur_hash, lqip, dominant_color, color_name, palette, processed_at
 ) VALUES (
-             $1, $2, $3, $4
+             $1, $2, $3, $4, $5, $6, $7, $8, $9
          )
     RETURNING *;
 
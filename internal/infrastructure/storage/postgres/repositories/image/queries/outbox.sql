@@ -0,0 +1,50 @@
+-- name: ClaimPendingOutboxMessages :many
+UPDATE outbox
+SET next_attempt_at = sqlc.arg('lease_until')
+WHERE id IN (
+    SELECT o.id FROM outbox o
+    WHERE o.sent_at IS NULL AND o.failed_at IS NULL AND o.next_attempt_at <= sqlc.arg('now')
+      AND NOT EXISTS (
+        SELECT 1 FROM outbox e
+        WHERE e.message_key = o.message_key AND e.sent_at IS NULL AND e.failed_at IS NULL
+          AND (e.created_at, e.id) < (o.created_at, o.id)
+    )
+    ORDER BY o.created_at
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE SKIP LOCKED
+)
+    RETURNING *;
+
+-- name: CreateOutboxMessage :exec
+INSERT INTO outbox (
+    id, message_key, payload, created_at, next_attempt_at
+) VALUES (
+             $1, $2, $3, $4, $5
+         );
+
+-- name: DeleteSentOutboxMessages :execrows
+DELETE FROM outbox
+WHERE sent_at IS NOT NULL AND sent_at < $1;
+
+-- name: GetLatestOutboxMessageByKey :one
+SELECT * FROM outbox
+WHERE message_key = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: MarkOutboxMessageFailed :exec
+UPDATE outbox
+SET
+    attempts = attempts + 1,
+    last_error = $2,
+    next_attempt_at = $3,
+    failed_at = $4
+WHERE id = $1;
+
+-- name: MarkOutboxMessageSent :exec
+UPDATE outbox
+SET
+    attempts = attempts + 1,
+    last_error = NULL,
+    sent_at = $2
+WHERE id = $1;
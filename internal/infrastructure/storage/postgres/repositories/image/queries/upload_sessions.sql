@@ -0,0 +1,37 @@
+-- name: CreateUploadSession :one
+INSERT INTO upload_sessions (
+    id, filename, size, options, duplicate_policy, s3_upload_id, hash_state, created_at, updated_at, expires_at, method
+) VALUES (
+             $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+         )
+    RETURNING *;
+
+-- name: GetUploadSessionByID :one
+SELECT * FROM upload_sessions
+WHERE id = $1 LIMIT 1;
+
+-- name: GetUploadSessionForUpdate :one
+SELECT * FROM upload_sessions
+WHERE id = $1 LIMIT 1
+    FOR UPDATE;
+
+-- name: UpdateUploadSessionProgress :exec
+UPDATE upload_sessions
+SET upload_offset = $2,
+    parts = $3,
+    pending = $4,
+    hash_state = $5,
+    updated_at = $6
+WHERE id = $1;
+
+-- name: CompleteUploadSession :exec
+UPDATE upload_sessions
+SET upload_offset = size,
+    pending = '',
+    image_id = $2,
+    updated_at = $3
+WHERE id = $1;
+
+-- name: DeleteUploadSession :execrows
+DELETE FROM upload_sessions
+WHERE id = $1;
@@ -0,0 +1,54 @@
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET
+    next_attempt_at = sqlc.arg('lease_until'),
+    updated_at = sqlc.arg('now')
+WHERE id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= sqlc.arg('now')
+    ORDER BY next_attempt_at
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE SKIP LOCKED
+)
+    RETURNING *;
+
+-- name: CreateWebhookAttempt :exec
+INSERT INTO webhook_attempts (
+    delivery_id, attempt, status_code, error, duration_ms, attempted_at
+) VALUES (
+             $1, $2, $3, $4, $5, $6
+         );
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+    id, image_id, url, event, payload, status, next_attempt_at, created_at, updated_at
+) VALUES (
+             $1, $2, $3, $4, $5, $6, $7, $8, $9
+         )
+    RETURNING *;
+
+-- name: GetWebhookDeliveryByID :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookAttemptsByImage :many
+SELECT a.* FROM webhook_attempts a
+                    JOIN webhook_deliveries d ON d.id = a.delivery_id
+WHERE d.image_id = $1
+ORDER BY a.delivery_id, a.attempt;
+
+-- name: ListWebhookDeliveriesByImage :many
+SELECT * FROM webhook_deliveries
+WHERE image_id = $1
+ORDER BY created_at DESC;
+
+-- name: UpdateWebhookDeliveryResult :exec
+UPDATE webhook_deliveries
+SET
+    status = $2,
+    attempts = $3,
+    next_attempt_at = $4,
+    last_error = $5,
+    updated_at = $6,
+    delivered_at = $7
+WHERE id = $1;
@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/executor"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/converters"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/gen"
+	"github.com/google/uuid"
+)
+
+type BatchRepository struct {
+	executor *executor.Executor
+	queries  *gen.Queries
+}
+
+func NewBatchRepository(executor *executor.Executor) *BatchRepository {
+	return &BatchRepository{
+		executor: executor,
+		queries:  gen.New(),
+	}
+}
+
+func (r *BatchRepository) Save(
+	ctx context.Context,
+	p options.BatchCreateParams,
+) error {
+	const op = "image.BatchRepository.Save"
+
+	if _, err := r.queries.CreateBatch(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToCreateBatchParams(p),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *BatchRepository) SaveItem(
+	ctx context.Context,
+	p options.BatchItemCreateParams,
+) error {
+	const op = "image.BatchRepository.SaveItem"
+
+	if err := r.queries.CreateBatchItem(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToCreateBatchItemParams(p),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *BatchRepository) Get(
+	ctx context.Context,
+	batchID uuid.UUID,
+) (*model.Batch, error) {
+	const op = "image.BatchRepository.Get"
+
+	rawBatch, err := r.queries.GetBatchByID(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		batchID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rawItems, err := r.queries.ListBatchItems(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		batchID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	batch := converters.ToDomainBatch(rawBatch, rawItems)
+	return &batch, nil
+}
+
+func (r *BatchRepository) UpdateItemsError(
+	ctx context.Context,
+	p options.BatchItemsErrorParams,
+) error {
+	const op = "image.BatchRepository.UpdateItemsError"
+
+	if err := r.queries.UpdateBatchItemsError(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToUpdateBatchItemsErrorParams(p),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *BatchRepository) Refresh(
+	ctx context.Context,
+	batchID uuid.UUID,
+	now time.Time,
+) error {
+	const op = "image.BatchRepository.Refresh"
+
+	if err := r.queries.RefreshBatch(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		gen.RefreshBatchParams{
+			UpdatedAt: now,
+			ID:        batchID,
+		},
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *BatchRepository) RefreshByImage(
+	ctx context.Context,
+	imageID uuid.UUID,
+	now time.Time,
+) error {
+	const op = "image.BatchRepository.RefreshByImage"
+
+	if err := r.queries.RefreshBatchesByImage(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		gen.RefreshBatchesByImageParams{
+			UpdatedAt: now,
+			ImageID:   uuid.NullUUID{UUID: imageID, Valid: true},
+		},
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
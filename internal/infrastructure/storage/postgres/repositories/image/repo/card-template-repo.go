@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/errordb"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/executor"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/converters"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/gen"
+	"github.com/google/uuid"
+)
+
+type CardTemplateRepository struct {
+	executor *executor.Executor
+	queries  *gen.Queries
+}
+
+func NewCardTemplateRepository(executor *executor.Executor) *CardTemplateRepository {
+	return &CardTemplateRepository{
+		executor: executor,
+		queries:  gen.New(),
+	}
+}
+
+func (r *CardTemplateRepository) Save(
+	ctx context.Context,
+	p options.CardTemplateCreateParams,
+) (*model.CardTemplate, error) {
+	const op = "image.CardTemplateRepository.Save"
+
+	params, err := converters.ToCreateCardTemplateParams(p)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rawTemplate, err := r.queries.CreateCardTemplate(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		params,
+	)
+	if err != nil {
+		if errordb.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("%s: %w: %w", op, errordb.ErrUniqueViolation, err)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	template, err := converters.ToDomainCardTemplate(rawTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &template, nil
+}
+
+func (r *CardTemplateRepository) Get(
+	ctx context.Context,
+	templateID uuid.UUID,
+) (*model.CardTemplate, error) {
+	const op = "image.CardTemplateRepository.Get"
+
+	rawTemplate, err := r.queries.GetCardTemplateByID(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		templateID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	template, err := converters.ToDomainCardTemplate(rawTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &template, nil
+}
+
+func (r *CardTemplateRepository) List(
+	ctx context.Context,
+	p options.PaginationParams,
+) ([]model.CardTemplate, error) {
+	const op = "image.CardTemplateRepository.List"
+
+	rawTemplates, err := r.queries.ListCardTemplates(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		gen.ListCardTemplatesParams{
+			Limit:  p.Limit,
+			Offset: p.Offset,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	templates := make([]model.CardTemplate, 0, len(rawTemplates))
+	for _, rawTemplate := range rawTemplates {
+		template, err := converters.ToDomainCardTemplate(rawTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+func (r *CardTemplateRepository) Delete(
+	ctx context.Context,
+	templateID uuid.UUID,
+) error {
+	const op = "image.CardTemplateRepository.Delete"
+
+	rows, err := r.queries.DeleteCardTemplate(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		templateID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, sql.ErrNoRows)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/executor"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/converters"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/gen"
+	"github.com/google/uuid"
+)
+
+type DeadLetterRepository struct {
+	executor *executor.Executor
+	queries  *gen.Queries
+}
+
+func NewDeadLetterRepository(executor *executor.Executor) *DeadLetterRepository {
+	return &DeadLetterRepository{
+		executor: executor,
+		queries:  gen.New(),
+	}
+}
+
+func (r *DeadLetterRepository) Save(
+	ctx context.Context,
+	p options.DeadLetterCreateParams,
+) (bool, error) {
+	const op = "image.DeadLetterRepository.Save"
+
+	created, err := r.queries.CreateDeadLetter(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToCreateDeadLetterParams(p),
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created > 0, nil
+}
+
+func (r *DeadLetterRepository) Get(
+	ctx context.Context,
+	letterID uuid.UUID,
+) (*model.DeadLetter, error) {
+	const op = "image.DeadLetterRepository.Get"
+
+	rawLetter, err := r.queries.GetDeadLetterByID(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		letterID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	letter := converters.ToDomainDeadLetter(rawLetter)
+	return &letter, nil
+}
+
+func (r *DeadLetterRepository) List(
+	ctx context.Context,
+	p options.PaginationParams,
+) ([]model.DeadLetter, error) {
+	const op = "image.DeadLetterRepository.List"
+
+	rawLetters, err := r.queries.ListDeadLetters(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToListDeadLettersParams(p),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	letters := make([]model.DeadLetter, 0, len(rawLetters))
+	for _, rawLetter := range rawLetters {
+		letters = append(letters, converters.ToDomainDeadLetter(rawLetter))
+	}
+
+	return letters, nil
+}
+
+func (r *DeadLetterRepository) MarkReplayed(
+	ctx context.Context,
+	letterID uuid.UUID,
+	replayedAt time.Time,
+) (bool, error) {
+	const op = "image.DeadLetterRepository.MarkReplayed"
+
+	updated, err := r.queries.MarkDeadLetterReplayed(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		gen.MarkDeadLetterReplayedParams{
+			ID:         letterID,
+			ReplayedAt: sql.NullTime{Time: replayedAt, Valid: true},
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return updated > 0, nil
+}
@@ -0,0 +1,156 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/executor"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/converters"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/gen"
+	"github.com/google/uuid"
+)
+
+type OutboxRepository struct {
+	executor *executor.Executor
+	queries  *gen.Queries
+}
+
+func NewOutboxRepository(executor *executor.Executor) *OutboxRepository {
+	return &OutboxRepository{
+		executor: executor,
+		queries:  gen.New(),
+	}
+}
+
+func (r *OutboxRepository) Save(
+	ctx context.Context,
+	p options.OutboxMessageCreateParams,
+) error {
+	const op = "image.OutboxRepository.Save"
+
+	params, err := converters.ToCreateOutboxMessageParams(p)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = r.queries.CreateOutboxMessage(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		params,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) ClaimPending(
+	ctx context.Context,
+	p options.OutboxClaimParams,
+) ([]model.OutboxMessage, error) {
+	const op = "image.OutboxRepository.ClaimPending"
+
+	rawMessages, err := r.queries.ClaimPendingOutboxMessages(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToClaimPendingOutboxMessagesParams(p),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// a message that cannot be decoded is returned without a task, so that
+	// it does not hold up the others
+	messages := make([]model.OutboxMessage, 0, len(rawMessages))
+	for _, rawMessage := range rawMessages {
+		message, _ := converters.ToDomainOutboxMessage(rawMessage)
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkSent(
+	ctx context.Context,
+	messageID uuid.UUID,
+	sentAt time.Time,
+) error {
+	const op = "image.OutboxRepository.MarkSent"
+
+	if err := r.queries.MarkOutboxMessageSent(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		gen.MarkOutboxMessageSentParams{
+			ID:     messageID,
+			SentAt: sql.NullTime{Time: sentAt, Valid: true},
+		},
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(
+	ctx context.Context,
+	p options.OutboxFailureParams,
+) error {
+	const op = "image.OutboxRepository.MarkFailed"
+
+	if err := r.queries.MarkOutboxMessageFailed(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToMarkOutboxMessageFailedParams(p),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) DeleteSent(
+	ctx context.Context,
+	before time.Time,
+) (int64, error) {
+	const op = "image.OutboxRepository.DeleteSent"
+
+	deleted, err := r.queries.DeleteSentOutboxMessages(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		sql.NullTime{Time: before, Valid: true},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+func (r *OutboxRepository) FindLatest(
+	ctx context.Context,
+	imageID uuid.UUID,
+) (*model.OutboxMessage, error) {
+	const op = "image.OutboxRepository.FindLatest"
+
+	rawMessage, err := r.queries.GetLatestOutboxMessageByKey(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		imageID.String(),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the task of a message that cannot be decoded is left nil
+	message, _ := converters.ToDomainOutboxMessage(rawMessage)
+
+	return &message, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/executor"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/converters"
 	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/gen"
+	"github.com/D1sordxr/image-processor/pkg/sqlutils"
 	"github.com/google/uuid"
 )
 
@@ -77,6 +78,23 @@ func (r *Repository) UpdateStatus(
 	return nil
 }
 
+func (r *Repository) UpdatePHash(
+	ctx context.Context,
+	p options.ImagePHashUpdateParams,
+) error {
+	const op = "image.Repository.UpdatePHash"
+
+	if err := r.queries.UpdateImagePhash(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToUpdateImagePhashParams(p),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 func (r *Repository) Get(
 	ctx context.Context,
 	imageID uuid.UUID,
@@ -96,6 +114,25 @@ func (r *Repository) Get(
 	return &metadata, nil
 }
 
+func (r *Repository) GetForUpdate(
+	ctx context.Context,
+	imageID uuid.UUID,
+) (*model.ImageMetadata, error) {
+	const op = "image.Repository.GetForUpdate"
+
+	metadataRaw, err := r.queries.GetImageByIDForUpdate(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		imageID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	metadata := converters.ToDomainImage(metadataRaw)
+	return &metadata, nil
+}
+
 func (r *Repository) GetWithProcessedData(
 	ctx context.Context,
 	imageID uuid.UUID,
@@ -115,6 +152,72 @@ func (r *Repository) GetWithProcessedData(
 	return &metadata, nil
 }
 
+func (r *Repository) List(
+	ctx context.Context,
+	p options.ImageListParams,
+) ([]model.ImageMetadata, error) {
+	const op = "image.Repository.List"
+
+	rawImages, err := r.queries.ListImagesWithFilters(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToListImagesWithFiltersParams(p),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	images := make([]model.ImageMetadata, 0, len(rawImages))
+	for _, rawImage := range rawImages {
+		images = append(images, converters.ToDomainImage(rawImage))
+	}
+	return images, nil
+}
+
+func (r *Repository) FindBySHA256(
+	ctx context.Context,
+	sha256 string,
+) ([]model.ImageMetadata, error) {
+	const op = "image.Repository.FindBySHA256"
+
+	rawImages, err := r.queries.GetImagesBySHA256(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		sqlutils.ToNullableString(&sha256),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	images := make([]model.ImageMetadata, 0, len(rawImages))
+	for _, rawImage := range rawImages {
+		images = append(images, converters.ToDomainImage(rawImage))
+	}
+	return images, nil
+}
+
+func (r *Repository) ListSimilar(
+	ctx context.Context,
+	p options.SimilarImagesParams,
+) ([]model.SimilarImage, error) {
+	const op = "image.Repository.ListSimilar"
+
+	rows, err := r.queries.ListSimilarImages(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToListSimilarImagesParams(p),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	images := make([]model.SimilarImage, 0, len(rows))
+	for _, row := range rows {
+		images = append(images, converters.ToDomainSimilarImage(row))
+	}
+	return images, nil
+}
+
 func (r *Repository) Delete(
 	ctx context.Context,
 	imageID uuid.UUID,
@@ -148,3 +251,80 @@ func (r *Repository) DeleteProcessed(
 
 	return nil
 }
+
+func (r *Repository) ListExistingIDs(
+	ctx context.Context,
+	imageIDs []uuid.UUID,
+) ([]uuid.UUID, error) {
+	const op = "image.Repository.ListExistingIDs"
+
+	existing, err := r.queries.ListExistingImageIDs(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		imageIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return existing, nil
+}
+
+func (r *Repository) ClaimStuck(
+	ctx context.Context,
+	p options.StuckImageClaimParams,
+) ([]model.StuckImage, error) {
+	const op = "image.Repository.ClaimStuck"
+
+	rawImages, err := r.queries.ClaimStuckImages(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToClaimStuckImagesParams(p),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	images := make([]model.StuckImage, 0, len(rawImages))
+	for _, rawImage := range rawImages {
+		images = append(images, converters.ToDomainStuckImage(rawImage))
+	}
+
+	return images, nil
+}
+
+func (r *Repository) MarkRequeued(
+	ctx context.Context,
+	imageID uuid.UUID,
+) (bool, error) {
+	const op = "image.Repository.MarkRequeued"
+
+	updated, err := r.queries.IncrementImageRequeues(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		imageID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return updated > 0, nil
+}
+
+func (r *Repository) Cancel(
+	ctx context.Context,
+	imageID uuid.UUID,
+) (bool, error) {
+	const op = "image.Repository.Cancel"
+
+	cancelled, err := r.queries.CancelImage(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		imageID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cancelled > 0, nil
+}
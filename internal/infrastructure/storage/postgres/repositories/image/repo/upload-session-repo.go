@@ -0,0 +1,157 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/executor"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/converters"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/gen"
+	"github.com/google/uuid"
+)
+
+type UploadSessionRepository struct {
+	executor *executor.Executor
+	queries  *gen.Queries
+}
+
+func NewUploadSessionRepository(executor *executor.Executor) *UploadSessionRepository {
+	return &UploadSessionRepository{
+		executor: executor,
+		queries:  gen.New(),
+	}
+}
+
+func (r *UploadSessionRepository) Save(
+	ctx context.Context,
+	p options.UploadSessionCreateParams,
+) (*model.UploadSession, error) {
+	const op = "image.UploadSessionRepository.Save"
+
+	params, err := converters.ToCreateUploadSessionParams(p)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rawSession, err := r.queries.CreateUploadSession(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		params,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	session, err := converters.ToDomainUploadSession(rawSession)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &session, nil
+}
+
+func (r *UploadSessionRepository) Get(
+	ctx context.Context,
+	sessionID uuid.UUID,
+) (*model.UploadSession, error) {
+	const op = "image.UploadSessionRepository.Get"
+
+	rawSession, err := r.queries.GetUploadSessionByID(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	session, err := converters.ToDomainUploadSession(rawSession)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &session, nil
+}
+
+func (r *UploadSessionRepository) GetForUpdate(
+	ctx context.Context,
+	sessionID uuid.UUID,
+) (*model.UploadSession, error) {
+	const op = "image.UploadSessionRepository.GetForUpdate"
+
+	rawSession, err := r.queries.GetUploadSessionForUpdate(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	session, err := converters.ToDomainUploadSession(rawSession)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &session, nil
+}
+
+func (r *UploadSessionRepository) UpdateProgress(
+	ctx context.Context,
+	p options.UploadSessionProgressParams,
+) error {
+	const op = "image.UploadSessionRepository.UpdateProgress"
+
+	params, err := converters.ToUpdateUploadSessionProgressParams(p)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = r.queries.UpdateUploadSessionProgress(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		params,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *UploadSessionRepository) Complete(
+	ctx context.Context,
+	p options.UploadSessionCompleteParams,
+) error {
+	const op = "image.UploadSessionRepository.Complete"
+
+	if err := r.queries.CompleteUploadSession(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToCompleteUploadSessionParams(p),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *UploadSessionRepository) Delete(
+	ctx context.Context,
+	sessionID uuid.UUID,
+) error {
+	const op = "image.UploadSessionRepository.Delete"
+
+	rows, err := r.queries.DeleteUploadSession(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		sessionID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, sql.ErrNoRows)
+	}
+
+	return nil
+}
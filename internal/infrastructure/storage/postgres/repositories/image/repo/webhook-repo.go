@@ -0,0 +1,162 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/executor"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/converters"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/storage/postgres/repositories/image/gen"
+	"github.com/google/uuid"
+)
+
+type WebhookRepository struct {
+	executor *executor.Executor
+	queries  *gen.Queries
+}
+
+func NewWebhookRepository(executor *executor.Executor) *WebhookRepository {
+	return &WebhookRepository{
+		executor: executor,
+		queries:  gen.New(),
+	}
+}
+
+func (r *WebhookRepository) Save(
+	ctx context.Context,
+	p options.WebhookDeliveryCreateParams,
+) (*model.WebhookDelivery, error) {
+	const op = "image.WebhookRepository.Save"
+
+	rawDelivery, err := r.queries.CreateWebhookDelivery(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToCreateWebhookDeliveryParams(p),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	delivery := converters.ToDomainWebhookDelivery(rawDelivery)
+	return &delivery, nil
+}
+
+func (r *WebhookRepository) Get(
+	ctx context.Context,
+	deliveryID uuid.UUID,
+) (*model.WebhookDelivery, error) {
+	const op = "image.WebhookRepository.Get"
+
+	rawDelivery, err := r.queries.GetWebhookDeliveryByID(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		deliveryID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	delivery := converters.ToDomainWebhookDelivery(rawDelivery)
+	return &delivery, nil
+}
+
+func (r *WebhookRepository) ListByImage(
+	ctx context.Context,
+	imageID uuid.UUID,
+) ([]model.WebhookDelivery, error) {
+	const op = "image.WebhookRepository.ListByImage"
+
+	rawDeliveries, err := r.queries.ListWebhookDeliveriesByImage(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		imageID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rawAttempts, err := r.queries.ListWebhookAttemptsByImage(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		imageID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attempts := make(map[uuid.UUID][]model.WebhookAttempt, len(rawDeliveries))
+	for _, rawAttempt := range rawAttempts {
+		attempts[rawAttempt.DeliveryID] = append(
+			attempts[rawAttempt.DeliveryID],
+			converters.ToDomainWebhookAttempt(rawAttempt),
+		)
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0, len(rawDeliveries))
+	for _, rawDelivery := range rawDeliveries {
+		delivery := converters.ToDomainWebhookDelivery(rawDelivery)
+		delivery.AttemptLog = attempts[delivery.ID]
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) ClaimDue(
+	ctx context.Context,
+	p options.WebhookClaimParams,
+) ([]model.WebhookDelivery, error) {
+	const op = "image.WebhookRepository.ClaimDue"
+
+	rawDeliveries, err := r.queries.ClaimDueWebhookDeliveries(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToClaimDueWebhookDeliveriesParams(p),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0, len(rawDeliveries))
+	for _, rawDelivery := range rawDeliveries {
+		deliveries = append(deliveries, converters.ToDomainWebhookDelivery(rawDelivery))
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) SaveAttempt(
+	ctx context.Context,
+	p options.WebhookAttemptCreateParams,
+) error {
+	const op = "image.WebhookRepository.SaveAttempt"
+
+	if err := r.queries.CreateWebhookAttempt(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToCreateWebhookAttemptParams(p),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) UpdateResult(
+	ctx context.Context,
+	p options.WebhookDeliveryResultParams,
+) error {
+	const op = "image.WebhookRepository.UpdateResult"
+
+	if err := r.queries.UpdateWebhookDeliveryResult(
+		ctx,
+		r.executor.GetExecutor(ctx),
+		converters.ToUpdateWebhookDeliveryResultParams(p),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -15,18 +15,23 @@ type Handler interface {
 	Stop(ctx context.Context) error
 }
 
+// Worker runs the handlers. On shutdown the handlers stop taking new work
+// and get drainTimeout to finish what is in flight.
 type Worker struct {
-	log      port.Logger
-	handlers []Handler
+	log          port.Logger
+	drainTimeout time.Duration
+	handlers     []Handler
 }
 
 func New(
 	log port.Logger,
+	drainTimeout time.Duration,
 	handlers ...Handler,
 ) *Worker {
 	return &Worker{
-		log:      log,
-		handlers: handlers,
+		log:          log,
+		drainTimeout: drainTimeout,
+		handlers:     handlers,
 	}
 }
 
@@ -56,7 +61,7 @@ func (w *Worker) Run(ctx context.Context) error {
 
 func (w *Worker) Shutdown(ctx context.Context) error {
 	done := make(chan struct{})
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, w.drainTimeout)
 	defer cancel()
 
 	go func() {
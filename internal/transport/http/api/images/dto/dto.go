@@ -12,6 +12,13 @@ type UploadRequest struct {
 	Format        string `form:"format" validate:"oneof=jpeg jpg png gif"`
 	WatermarkText string `form:"watermark"`
 	Thumbnail     bool   `form:"thumbnail"`
+	CornerRadius  int    `form:"corner_radius" validate:"min=0"`
+	Mask          string `form:"mask" validate:"omitempty,oneof=circle ellipse"`
+	BorderWidth   int    `form:"border_width" validate:"min=0"`
+	BorderColor   string `form:"border_color" validate:"omitempty,color"`
+	AutoLevel     bool   `form:"auto_level"`
+	AutoWB        bool   `form:"auto_white_balance"`
+	MaxBytes      int    `form:"max_bytes" validate:"min=0"`
 }
 
 func (r *UploadRequest) Validate() error {
@@ -20,15 +27,150 @@ func (r *UploadRequest) Validate() error {
 
 func (r *UploadRequest) ToProcessingOptions() model.ProcessingOptions {
 	return model.ProcessingOptions{
-		Width:         r.Width,
-		Height:        r.Height,
-		Quality:       r.Quality,
-		Format:        r.Format,
-		WatermarkText: r.WatermarkText,
-		Thumbnail:     r.Thumbnail,
+		Width:            r.Width,
+		Height:           r.Height,
+		Quality:          r.Quality,
+		Format:           r.Format,
+		WatermarkText:    r.WatermarkText,
+		Thumbnail:        r.Thumbnail,
+		CornerRadius:     r.CornerRadius,
+		Mask:             r.Mask,
+		BorderWidth:      r.BorderWidth,
+		BorderColor:      r.BorderColor,
+		AutoLevel:        r.AutoLevel,
+		AutoWhiteBalance: r.AutoWB,
+		MaxBytes:         r.MaxBytes,
 	}
 }
 
+type ContactSheetRequest struct {
+	ImageIDs   []string `json:"image_ids" validate:"required,min=1,max=100,dive,uuid"`
+	Columns    int      `json:"columns" validate:"required,min=1,max=50"`
+	CellWidth  int      `json:"cell_width" validate:"required,min=1,max=2048"`
+	CellHeight int      `json:"cell_height" validate:"required,min=1,max=2048"`
+	Spacing    int      `json:"spacing" validate:"min=0,max=256"`
+	Background string   `json:"background" validate:"omitempty,color"`
+	Captions   bool     `json:"captions"`
+	Format     string   `json:"format" validate:"omitempty,oneof=jpeg jpg png gif"`
+	Quality    int      `json:"quality" validate:"omitempty,min=1,max=100"`
+}
+
+func (r *ContactSheetRequest) Validate() error {
+	return validator.ValidateStruct(r)
+}
+
+func (r *ContactSheetRequest) ToContactSheetSpec() model.ContactSheetSpec {
+	return model.ContactSheetSpec{
+		ImageIDs:   r.ImageIDs,
+		Columns:    r.Columns,
+		CellWidth:  r.CellWidth,
+		CellHeight: r.CellHeight,
+		Spacing:    r.Spacing,
+		Background: r.Background,
+		Captions:   r.Captions,
+		Format:     r.Format,
+		Quality:    r.Quality,
+	}
+}
+
+type CardTemplateRequest struct {
+	Name       string                 `json:"name" validate:"required,max=255"`
+	Width      int                    `json:"width" validate:"required,min=1,max=4096"`
+	Height     int                    `json:"height" validate:"required,min=1,max=4096"`
+	Background string                 `json:"background" validate:"omitempty,color"`
+	ImageSlots []CardImageSlotRequest `json:"image_slots" validate:"max=20,dive"`
+	TextSlots  []CardTextSlotRequest  `json:"text_slots" validate:"max=20,dive"`
+}
+
+type CardImageSlotRequest struct {
+	Name         string `json:"name" validate:"required,max=64"`
+	X            int    `json:"x"`
+	Y            int    `json:"y"`
+	Width        int    `json:"width" validate:"required,min=1,max=4096"`
+	Height       int    `json:"height" validate:"required,min=1,max=4096"`
+	Fit          string `json:"fit" validate:"omitempty,oneof=cover contain"`
+	CornerRadius int    `json:"corner_radius" validate:"min=0"`
+}
+
+type CardTextSlotRequest struct {
+	Name       string  `json:"name" validate:"required,max=64"`
+	X          int     `json:"x"`
+	Y          int     `json:"y"`
+	Width      int     `json:"width" validate:"required,min=1,max=4096"`
+	MaxLines   int     `json:"max_lines" validate:"min=0,max=50"`
+	Font       string  `json:"font" validate:"omitempty,oneof=regular bold italic mono"`
+	FontSize   float64 `json:"font_size" validate:"required,gt=0,max=512"`
+	LineHeight float64 `json:"line_height" validate:"omitempty,gt=0,max=5"`
+	Color      string  `json:"color" validate:"omitempty,color"`
+	Align      string  `json:"align" validate:"omitempty,oneof=left center right"`
+	Default    string  `json:"default"`
+}
+
+func (r *CardTemplateRequest) Validate() error {
+	return validator.ValidateStruct(r)
+}
+
+func (r *CardTemplateRequest) ToImageSlots() []model.CardImageSlot {
+	slots := make([]model.CardImageSlot, 0, len(r.ImageSlots))
+	for _, s := range r.ImageSlots {
+		slots = append(slots, model.CardImageSlot{
+			Name:         s.Name,
+			X:            s.X,
+			Y:            s.Y,
+			Width:        s.Width,
+			Height:       s.Height,
+			Fit:          s.Fit,
+			CornerRadius: s.CornerRadius,
+		})
+	}
+	return slots
+}
+
+func (r *CardTemplateRequest) ToTextSlots() []model.CardTextSlot {
+	slots := make([]model.CardTextSlot, 0, len(r.TextSlots))
+	for _, s := range r.TextSlots {
+		slots = append(slots, model.CardTextSlot{
+			Name:       s.Name,
+			X:          s.X,
+			Y:          s.Y,
+			Width:      s.Width,
+			MaxLines:   s.MaxLines,
+			Font:       s.Font,
+			FontSize:   s.FontSize,
+			LineHeight: s.LineHeight,
+			Color:      s.Color,
+			Align:      s.Align,
+			Default:    s.Default,
+		})
+	}
+	return slots
+}
+
+type RenderCardRequest struct {
+	Images  map[string]string `json:"images" validate:"max=20,dive,uuid"`
+	Texts   map[string]string `json:"texts" validate:"max=20"`
+	Format  string            `json:"format" validate:"omitempty,oneof=jpeg jpg png"`
+	Quality int               `json:"quality" validate:"omitempty,min=1,max=100"`
+}
+
+func (r *RenderCardRequest) Validate() error {
+	return validator.ValidateStruct(r)
+}
+
+// StatusStreamRequest is a message of the client of the status WebSocket.
+type StatusStreamRequest struct {
+	Action   string   `json:"action"` // "subscribe", "unsubscribe"
+	ImageIDs []string `json:"image_ids"`
+}
+
+// StatusStreamMessage is a message of the status WebSocket.
+type StatusStreamMessage struct {
+	Type    string             `json:"type"` // "status", "error", "ping"
+	Event   *model.StatusEvent `json:"event,omitempty"`
+	Error   string             `json:"error,omitempty"`
+	Details string             `json:"details,omitempty"`
+}
+
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Details string `json:"details,omitempty"`
@@ -43,6 +185,7 @@ type UploadResponse struct {
 	ImageID           string                  `json:"image_id"`
 	ResultURL         string                  `json:"result_url"`
 	ProcessingOptions model.ProcessingOptions `json:"processing_options"`
+	Duplicate         bool                    `json:"duplicate,omitempty"`
 	Message           string                  `json:"message"`
 }
 
@@ -50,6 +193,8 @@ type ProcessingStatusResponse struct {
 	Status   string `json:"status"`
 	ImageID  string `json:"image_id"`
 	ImageURL string `json:"image_url"`
+	BlurHash string `json:"blur_hash,omitempty"`
+	LQIP     string `json:"lqip,omitempty"`
 	Message  string `json:"message"`
 }
 
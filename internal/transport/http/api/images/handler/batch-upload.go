@@ -0,0 +1,375 @@
+package handler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/transport/http/api/images/dto"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+const (
+	MaxBatchItems       = 500
+	MaxBatchRequestSize = 1 << 30 // 1GB
+
+	ErrBatchEmpty      = "No files provided"
+	ErrBatchIDRequired = "Batch ID is required"
+	ErrBatchNotFound   = "Batch not found"
+)
+
+var (
+	errInvalidBatchImage = errors.New("invalid image file: please provide a valid JPEG, PNG, or GIF image")
+	errTooManyBatchItems = fmt.Errorf("maximum number of files per batch is %d", MaxBatchItems)
+)
+
+// UploadBatch uploads the files of the "images" form field and the images of
+// a ZIP archive sent in the "archive" field. Options from the form or the
+// query apply to every file; the "options" field may hold a JSON object with
+// options of single files keyed by filename, e.g. {"cover.jpg": {"width": 800}}.
+func (h *Handler) UploadBatch(c *ginext.Context) {
+	const op = "image.Handler.UploadBatch"
+	logFields := logger.WithFields("operation", op)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxBatchRequestSize)
+	form, err := c.MultipartForm()
+	if err != nil {
+		h.log.Error("Invalid multipart form", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid multipart form",
+			Details: fmt.Sprintf("Maximum request size is %dMB", MaxBatchRequestSize>>20),
+		})
+		return
+	}
+
+	sharedOptions := h.formOptions(c)
+	if _, err = h.parseOptions(sharedOptions); err != nil {
+		h.log.Error("Invalid processing options", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid processing options",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	fileOptions, err := parseFileOptions(c.PostForm("options"))
+	if err != nil {
+		h.log.Error("Invalid file options", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid processing options",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	duplicatePolicy, err := h.parseDuplicatePolicy(h.formOptions(c))
+	if err != nil {
+		h.log.Error("Invalid duplicate policy", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid duplicate policy",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	callbackURL, err := h.parseCallbackURL(h.formOptions(c))
+	if err != nil {
+		h.log.Error("Invalid callback URL", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid callback URL",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if len(form.File["images"]) > MaxBatchItems {
+		h.log.Error("Too many files", logFields("items", len(form.File["images"]))...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Too many files",
+			Details: errTooManyBatchItems.Error(),
+		})
+		return
+	}
+
+	items := make([]input.UploadBatchItem, 0, len(form.File["images"]))
+	for _, fileHeader := range form.File["images"] {
+		items = append(items, h.newBatchItem(
+			fileHeader.Filename,
+			fileHeader.Size,
+			func() (io.ReadCloser, error) { return fileHeader.Open() },
+			fileOptionsOf(fileOptions, fileHeader.Filename, sharedOptions),
+		))
+	}
+
+	archives := form.File["archive"]
+	if len(archives) > 1 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Too many archives",
+			Details: "Only one archive can be uploaded per request",
+		})
+		return
+	}
+	if len(archives) == 1 {
+		archive, err := archives[0].Open()
+		if err != nil {
+			h.log.Error("Failed to open archive", logFields("error", err)...)
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to open archive",
+				Details: err.Error(),
+			})
+			return
+		}
+		defer func() { _ = archive.Close() }()
+
+		archiveItems, err := h.archiveBatchItems(
+			archive,
+			archives[0].Size,
+			MaxBatchItems-len(items),
+			fileOptions,
+			sharedOptions,
+		)
+		if errors.Is(err, errTooManyBatchItems) {
+			h.log.Error("Too many files", logFields("filename", archives[0].Filename)...)
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Too many files",
+				Details: err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			h.log.Error("Invalid archive", logFields("error", err, "filename", archives[0].Filename)...)
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid archive",
+				Details: "Please provide a valid ZIP archive",
+			})
+			return
+		}
+		items = append(items, archiveItems...)
+	}
+
+	if len(items) == 0 {
+		h.log.Error("No files provided", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrBatchEmpty,
+			Details: "Please provide images using 'images' form field or a ZIP archive using 'archive' form field",
+		})
+		return
+	}
+
+	h.log.Info("Starting batch upload", logFields("items", len(items))...)
+
+	result, err := h.uc.UploadBatch(c.Request.Context(), input.UploadBatchInput{
+		Items:           items,
+		DuplicatePolicy: duplicatePolicy,
+		CallbackURL:     callbackURL,
+	})
+	if err != nil {
+		h.log.Error("Failed to upload batch", logFields("error", err)...)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to upload batch",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Batch uploaded", logFields(
+		"batch_id", result.BatchID,
+		"accepted", result.Accepted,
+		"failed", result.Failed,
+	)...)
+
+	status := http.StatusAccepted
+	if result.Accepted == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.Header("Location", h.buildBatchURL(result.BatchID))
+	c.JSON(status, dto.SuccessResponse{
+		Message: fmt.Sprintf("%d of %d images accepted", result.Accepted, result.Total),
+		Data:    result,
+	})
+}
+
+// GetBatch reports the aggregated progress of the images of a batch.
+func (h *Handler) GetBatch(c *ginext.Context) {
+	const op = "image.Handler.GetBatch"
+	logFields := logger.WithFields("operation", op)
+
+	batchID := c.Param("id")
+	if batchID == "" {
+		h.log.Error("Batch ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrBatchIDRequired,
+		})
+		return
+	}
+
+	h.log.Info("Getting batch", logFields("batch_id", batchID)...)
+
+	result, err := h.uc.GetBatch(c.Request.Context(), input.GetBatchInput{
+		BatchID: batchID,
+	})
+	if err != nil {
+		h.log.Error("Failed to get batch", logFields("error", err, "batch_id", batchID)...)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrBatchNotFound,
+				Details: fmt.Sprintf("Batch with ID %s not found", batchID),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to get batch",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: result,
+	})
+}
+
+func (h *Handler) buildBatchURL(batchID string) string {
+	return fmt.Sprintf("%s/api/batches/%s", h.baseURL, batchID)
+}
+
+// archiveBatchItems lists the files of a ZIP archive, skipping directories
+// and hidden files. The files are counted before any of them is opened.
+func (h *Handler) archiveBatchItems(
+	archive io.ReaderAt,
+	size int64,
+	limit int,
+	fileOptions map[string]map[string]string,
+	sharedOptions func(key string) string,
+) ([]input.UploadBatchItem, error) {
+	zipReader, err := zip.NewReader(archive, size)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*zip.File, 0, len(zipReader.File))
+	for _, entry := range zipReader.File {
+		filename := path.Base(entry.Name)
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") || strings.HasPrefix(filename, ".") {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) > limit {
+		return nil, errTooManyBatchItems
+	}
+
+	items := make([]input.UploadBatchItem, 0, len(entries))
+	for _, entry := range entries {
+		filename := path.Base(entry.Name)
+
+		// the declared size is enforced by the zip reader while reading
+		entrySize := int64(min(entry.UncompressedSize64, MaxFileSize+1))
+		items = append(items, h.newBatchItem(
+			filename,
+			entrySize,
+			entry.Open,
+			fileOptionsOf(fileOptions, filename, sharedOptions),
+		))
+	}
+
+	return items, nil
+}
+
+// newBatchItem validates a file of the batch. An invalid file is kept in the
+// batch so that its error is reported along with the other results.
+func (h *Handler) newBatchItem(
+	filename string,
+	size int64,
+	open func() (io.ReadCloser, error),
+	readOpt func(key string) string,
+) input.UploadBatchItem {
+	item := input.UploadBatchItem{
+		Filename: filename,
+		Size:     size,
+		Open:     open,
+	}
+
+	opts, err := h.parseOptions(readOpt)
+	if err != nil {
+		item.Rejected = fmt.Errorf("invalid processing options: %w", err)
+		return item
+	}
+	item.Options = opts
+	item.Rejected = h.validateBatchFile(filename, size, open)
+
+	return item
+}
+
+func (h *Handler) validateBatchFile(filename string, size int64, open func() (io.ReadCloser, error)) error {
+	if size > MaxFileSize {
+		return fmt.Errorf("file too large: maximum file size is %dMB", MaxFileSize>>20)
+	}
+	if !h.hasImageExtension(filename) {
+		return errInvalidBatchImage
+	}
+
+	file, err := open()
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	header := make([]byte, SniffSize)
+	n, _ := io.ReadFull(file, header)
+	if !h.hasImageSignature(header[:n]) {
+		return errInvalidBatchImage
+	}
+
+	return nil
+}
+
+// parseFileOptions decodes the per-file options. Values are kept as strings
+// so that they are validated the same way as the form options.
+func parseFileOptions(raw string) (map[string]map[string]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+
+	var decoded map[string]map[string]any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("options must be a JSON object keyed by filename: %w", err)
+	}
+
+	fileOptions := make(map[string]map[string]string, len(decoded))
+	for filename, opts := range decoded {
+		fileOptions[filename] = make(map[string]string, len(opts))
+		for key, val := range opts {
+			fileOptions[filename][key] = fmt.Sprint(val)
+		}
+	}
+
+	return fileOptions, nil
+}
+
+// fileOptionsOf reads the options of the file, falling back to the shared ones.
+func fileOptionsOf(
+	fileOptions map[string]map[string]string,
+	filename string,
+	sharedOptions func(key string) string,
+) func(key string) string {
+	opts := fileOptions[filename]
+	return func(key string) string {
+		if val, ok := opts[key]; ok {
+			return val
+		}
+		return sharedOptions(key)
+	}
+}
@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
+	"github.com/D1sordxr/image-processor/internal/transport/http/api/images/dto"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+const (
+	DefaultCardBackground = "#FFFFFF"
+
+	ErrTemplateIDRequired = "Template ID is required"
+	ErrTemplateNotFound   = "Template not found"
+	ErrInvalidTemplate    = "Invalid card template"
+)
+
+func (h *Handler) CreateCardTemplate(c *ginext.Context) {
+	const op = "image.Handler.CreateCardTemplate"
+	logFields := logger.WithFields("operation", op)
+
+	var req dto.CardTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Invalid card template request", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrInvalidTemplate,
+			Details: err.Error(),
+		})
+		return
+	}
+
+	in, err := h.parseCardTemplate(&req)
+	if err != nil {
+		h.log.Error("Card template validation failed", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrInvalidTemplate,
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Creating card template", logFields("name", in.Name)...)
+
+	result, err := h.uc.CreateCardTemplate(c.Request.Context(), in)
+	if err != nil {
+		h.log.Error("Failed to create card template", logFields("error", err)...)
+		if strings.Contains(err.Error(), "unique violation") {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Template already exists",
+				Details: fmt.Sprintf("Template with name %s already exists", in.Name),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to create card template",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "Card template created",
+		Data:    result.Template,
+	})
+}
+
+func (h *Handler) ListCardTemplates(c *ginext.Context) {
+	const op = "image.Handler.ListCardTemplates"
+	logFields := logger.WithFields("operation", op)
+
+	in := input.ListCardTemplatesInput{Limit: DefaultListLimit}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > MaxListLimit {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid limit",
+				Details: fmt.Sprintf("limit must be between 1 and %d", MaxListLimit),
+			})
+			return
+		}
+		in.Limit = int32(limit)
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid offset",
+				Details: "offset must be non-negative integer",
+			})
+			return
+		}
+		in.Offset = int32(offset)
+	}
+
+	h.log.Info("Listing card templates", logFields()...)
+
+	result, err := h.uc.ListCardTemplates(c.Request.Context(), in)
+	if err != nil {
+		h.log.Error("Failed to list card templates", logFields("error", err)...)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to list card templates",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: result,
+	})
+}
+
+func (h *Handler) GetCardTemplate(c *ginext.Context) {
+	const op = "image.Handler.GetCardTemplate"
+	logFields := logger.WithFields("operation", op)
+
+	templateID := c.Param("id")
+	if templateID == "" {
+		h.log.Error("Template ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrTemplateIDRequired,
+		})
+		return
+	}
+
+	h.log.Info("Getting card template", logFields("template_id", templateID)...)
+
+	result, err := h.uc.GetCardTemplate(c.Request.Context(), input.GetCardTemplateInput{
+		TemplateID: templateID,
+	})
+	if err != nil {
+		h.log.Error("Failed to get card template", logFields("error", err, "template_id", templateID)...)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrTemplateNotFound,
+				Details: fmt.Sprintf("Template with ID %s not found", templateID),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to get card template",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: result.Template,
+	})
+}
+
+func (h *Handler) DeleteCardTemplate(c *ginext.Context) {
+	const op = "image.Handler.DeleteCardTemplate"
+	logFields := logger.WithFields("operation", op)
+
+	templateID := c.Param("id")
+	if templateID == "" {
+		h.log.Error("Template ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrTemplateIDRequired,
+		})
+		return
+	}
+
+	h.log.Info("Deleting card template", logFields("template_id", templateID)...)
+
+	result, err := h.uc.DeleteCardTemplate(c.Request.Context(), input.DeleteCardTemplateInput{
+		TemplateID: templateID,
+	})
+	if err != nil {
+		h.log.Error("Failed to delete card template", logFields("error", err, "template_id", templateID)...)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrTemplateNotFound,
+				Details: fmt.Sprintf("Template with ID %s not found", templateID),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to delete card template",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: result.Message,
+		Data: map[string]string{
+			"template_id": templateID,
+		},
+	})
+}
+
+func (h *Handler) RenderCard(c *ginext.Context) {
+	const op = "image.Handler.RenderCard"
+	logFields := logger.WithFields("operation", op)
+
+	templateID := c.Param("id")
+	if templateID == "" {
+		h.log.Error("Template ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrTemplateIDRequired,
+		})
+		return
+	}
+
+	var req dto.RenderCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Invalid render request", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid render request",
+			Details: err.Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.log.Error("Render request validation failed", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid render request",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Rendering card", logFields("template_id", templateID)...)
+
+	result, err := h.uc.RenderCard(c.Request.Context(), input.RenderCardInput{
+		Spec: model.CardRenderSpec{
+			TemplateID: templateID,
+			Images:     req.Images,
+			Texts:      req.Texts,
+			Format:     req.Format,
+			Quality:    req.Quality,
+		},
+	})
+	if err != nil {
+		h.log.Error("Failed to render card", logFields("error", err, "template_id", templateID)...)
+		switch {
+		case errors.Is(err, errs.ErrUnknownSlot):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid render request",
+				Details: err.Error(),
+			})
+		case strings.Contains(err.Error(), "template not found"):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrTemplateNotFound,
+				Details: fmt.Sprintf("Template with ID %s not found", templateID),
+			})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrImageNotFound,
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to render card",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	h.log.Info("Card task created", logFields("image_id", result.ImageID)...)
+
+	c.JSON(http.StatusAccepted, dto.SuccessResponse{
+		Message: "Card is being rendered",
+		Data: dto.ProcessingStatusResponse{
+			Status:   result.Status,
+			ImageID:  result.ImageID,
+			ImageURL: h.buildImageURL(result.ImageID),
+			Message:  "Card is being rendered",
+		},
+	})
+}
+
+// parseCardTemplate validates the request and normalizes its colors.
+func (h *Handler) parseCardTemplate(req *dto.CardTemplateRequest) (input.CreateCardTemplateInput, error) {
+	if err := req.Validate(); err != nil {
+		return input.CreateCardTemplateInput{}, err
+	}
+
+	in := input.CreateCardTemplateInput{
+		Name:       req.Name,
+		Width:      req.Width,
+		Height:     req.Height,
+		Background: DefaultCardBackground,
+		ImageSlots: req.ToImageSlots(),
+		TextSlots:  req.ToTextSlots(),
+	}
+
+	if req.Background != "" {
+		background, err := vo.NewValidColor(req.Background)
+		if err != nil {
+			return in, fmt.Errorf("invalid background: must be #RRGGBB or #RRGGBBAA")
+		}
+		in.Background = background.String()
+	}
+
+	names := make(map[string]struct{}, len(in.ImageSlots)+len(in.TextSlots))
+	for _, slot := range in.ImageSlots {
+		if _, ok := names[slot.Name]; ok {
+			return in, fmt.Errorf("duplicate slot name: %s", slot.Name)
+		}
+		names[slot.Name] = struct{}{}
+	}
+	for i, slot := range in.TextSlots {
+		if _, ok := names[slot.Name]; ok {
+			return in, fmt.Errorf("duplicate slot name: %s", slot.Name)
+		}
+		names[slot.Name] = struct{}{}
+
+		if slot.Color != "" {
+			textColor, err := vo.NewValidColor(slot.Color)
+			if err != nil {
+				return in, fmt.Errorf("invalid color of slot %s: must be #RRGGBB or #RRGGBBAA", slot.Name)
+			}
+			in.TextSlots[i].Color = textColor.String()
+		}
+	}
+
+	return in, nil
+}
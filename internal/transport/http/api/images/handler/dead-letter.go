@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/transport/http/api/images/dto"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+const (
+	ErrDeadLetterIDRequired = "Dead letter ID is required"
+	ErrDeadLetterNotFound   = "Dead letter not found"
+)
+
+// ListDeadLetters returns the tasks that ran out of retries, newest first.
+func (h *Handler) ListDeadLetters(c *ginext.Context) {
+	const op = "image.Handler.ListDeadLetters"
+	logFields := logger.WithFields("operation", op)
+
+	in := input.ListDeadLettersInput{
+		Limit: DefaultListLimit,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > MaxListLimit {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid limit",
+				Details: fmt.Sprintf("limit must be between 1 and %d", MaxListLimit),
+			})
+			return
+		}
+		in.Limit = int32(limit)
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid offset",
+				Details: "offset must be a non-negative integer",
+			})
+			return
+		}
+		in.Offset = int32(offset)
+	}
+
+	h.log.Info("Listing dead letters", logFields("limit", in.Limit, "offset", in.Offset)...)
+
+	result, err := h.uc.ListDeadLetters(c.Request.Context(), in)
+	if err != nil {
+		h.log.Error("Failed to list dead letters", logFields("error", err)...)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to list dead letters",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: result,
+	})
+}
+
+// ReplayDeadLetter puts the task of a dead letter back to the queue.
+func (h *Handler) ReplayDeadLetter(c *ginext.Context) {
+	const op = "image.Handler.ReplayDeadLetter"
+	logFields := logger.WithFields("operation", op)
+
+	letterID := c.Param("id")
+	if letterID == "" {
+		h.log.Error("Dead letter ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrDeadLetterIDRequired,
+		})
+		return
+	}
+
+	h.log.Info("Replaying dead letter", logFields("dead_letter_id", letterID)...)
+
+	result, err := h.uc.ReplayDeadLetter(c.Request.Context(), input.ReplayDeadLetterInput{
+		DeadLetterID: letterID,
+	})
+	if err != nil {
+		h.log.Error("Failed to replay dead letter", logFields("error", err, "dead_letter_id", letterID)...)
+		switch {
+		case errors.Is(err, errs.ErrAlreadyReplayed):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Dead letter is already replayed",
+				Details: err.Error(),
+			})
+		case errors.Is(err, errs.ErrNotReplayable):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Image is not failed",
+				Details: err.Error(),
+			})
+		case errors.Is(err, errs.ErrUndecodableTask):
+			c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "Dead letter cannot be replayed",
+				Details: err.Error(),
+			})
+		case strings.Contains(err.Error(), "dead letter not found"):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrDeadLetterNotFound,
+				Details: fmt.Sprintf("Dead letter with ID %s not found", letterID),
+			})
+		case strings.Contains(err.Error(), "image not found"):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrImageNotFound,
+				Details: "The image of the dead letter no longer exists",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to replay dead letter",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.SuccessResponse{
+		Message: "Dead letter replayed",
+		Data:    result,
+	})
+}
@@ -1,20 +1,26 @@
 package handler
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"github.com/D1sordxr/image-processor/internal/transport/http/api/images/dto"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
 	"github.com/D1sordxr/image-processor/internal/application/image/input"
 	"github.com/D1sordxr/image-processor/internal/application/image/port"
 	appPorts "github.com/D1sordxr/image-processor/internal/domain/app/port"
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/model"
+	domainPorts "github.com/D1sordxr/image-processor/internal/domain/core/image/port"
 	"github.com/D1sordxr/image-processor/internal/domain/core/image/vo"
 	sharedVO "github.com/D1sordxr/image-processor/internal/domain/core/shared/vo"
 	"github.com/D1sordxr/image-processor/pkg/logger"
@@ -23,8 +29,16 @@ import (
 )
 
 const (
-	MaxFileSize = 10 << 20 // 10MB
-	CacheMaxAge = 3600     // 1 hour
+	MaxFileSize      = 10 << 20 // 10MB
+	MaxFormValueSize = 64 << 10 // 64KB, for each field sent before the file
+	SniffSize        = 8        // bytes of the file header checked for the signature
+	CacheMaxAge      = 3600     // 1 hour
+
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+
+	DefaultSimilarMaxDistance = 10
+	MaxSimilarDistance        = 64
 
 	ContentTypeJPEG = "image/jpeg"
 	ContentTypePNG  = "image/png"
@@ -35,19 +49,39 @@ const (
 	ErrInvalidImage    = "Invalid image file"
 	ErrImageIDRequired = "Image ID is required"
 	ErrImageNotFound   = "Image not found"
+	ErrDuplicateImage  = "Duplicate image"
 )
 
+var errFieldsAfterImage = errors.New("form fields must be sent before the image file")
+
 type Handler struct {
 	log     appPorts.Logger
 	uc      port.UseCase
+	fetcher domainPorts.Fetcher
 	baseURL string
+	// origins are the browser origins allowed besides the API's own
+	origins []string
+
+	// streamsDone is closed when the server shuts down, so that the event
+	// streams end instead of holding the shutdown up
+	streamsDone  chan struct{}
+	closeStreams sync.Once
 }
 
-func New(log appPorts.Logger, uc port.UseCase, baseURL sharedVO.BaseURL) *Handler {
+func New(
+	log appPorts.Logger,
+	uc port.UseCase,
+	fetcher domainPorts.Fetcher,
+	baseURL sharedVO.BaseURL,
+	origins []string,
+) *Handler {
 	return &Handler{
-		uc:      uc,
-		log:     log,
-		baseURL: baseURL.String(),
+		uc:          uc,
+		log:         log,
+		fetcher:     fetcher,
+		baseURL:     baseURL.String(),
+		origins:     origins,
+		streamsDone: make(chan struct{}),
 	}
 }
 
@@ -57,7 +91,8 @@ func (h *Handler) UploadNewImage(c *ginext.Context) {
 
 	h.log.Info("Starting image upload", logFields()...)
 
-	imageHeader, err := c.FormFile("image")
+	// the file is streamed to the storage, the form is not buffered
+	imagePart, fields, err := h.readImagePart(c)
 	if err != nil {
 		h.log.Error("No image file provided", logFields("error", err)...)
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -66,63 +101,84 @@ func (h *Handler) UploadNewImage(c *ginext.Context) {
 		})
 		return
 	}
+	defer func() { _ = imagePart.Close() }()
+
+	// only its header is read here
+	imageFile := &maxSizeReader{r: imagePart, max: MaxFileSize}
+	imageReader := bufio.NewReaderSize(imageFile, SniffSize)
+	header, _ := imageReader.Peek(SniffSize)
 
-	if err = h.validateFile(imageHeader); err != nil {
-		h.log.Error("File validation failed", logFields("error", err)...)
+	if !h.isValidImage(imagePart.FileName(), header) {
+		h.log.Error("Invalid image file", logFields("filename", imagePart.FileName())...)
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   ErrFileTooLarge,
-			Details: fmt.Sprintf("Maximum file size is %dMB", MaxFileSize>>20),
+			Error:   ErrInvalidImage,
+			Details: "Please provide a valid JPEG, PNG, or GIF image",
 		})
 		return
 	}
 
-	imageFile, err := imageHeader.Open()
+	readOpt := h.multipartOptions(c, fields)
+	opts, err := h.parseOptions(readOpt)
 	if err != nil {
-		h.log.Error("Failed to open uploaded file", logFields("error", err)...)
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "Failed to open uploaded file",
+		h.log.Error("Invalid processing options", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid processing options",
 			Details: err.Error(),
 		})
 		return
 	}
-	defer func() { _ = imageFile.Close() }()
 
-	imageData, err := io.ReadAll(imageFile)
+	duplicatePolicy, err := h.parseDuplicatePolicy(readOpt)
 	if err != nil {
-		h.log.Error("Failed to read file data", logFields("error", err)...)
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "Failed to read file data",
-			Details: err.Error(),
-		})
-		return
-	}
-
-	if !h.isValidImage(imageHeader.Filename, imageData) {
-		h.log.Error("Invalid image file", logFields("filename", imageHeader.Filename)...)
+		h.log.Error("Invalid duplicate policy", logFields("error", err)...)
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   ErrInvalidImage,
-			Details: "Please provide a valid JPEG, PNG, or GIF image",
+			Error:   "Invalid duplicate policy",
+			Details: err.Error(),
 		})
 		return
 	}
 
-	opts, err := h.parseProcessingOptions(c)
+	callbackURL, err := h.parseCallbackURL(readOpt)
 	if err != nil {
-		h.log.Error("Invalid processing options", logFields("error", err)...)
+		h.log.Error("Invalid callback URL", logFields("error", err)...)
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "Invalid processing options",
+			Error:   "Invalid callback URL",
 			Details: err.Error(),
 		})
 		return
 	}
 
 	result, err := h.uc.Upload(c.Request.Context(), input.UploadImageInput{
-		ImageData: imageData,
-		Filename:  imageHeader.Filename,
-		Options:   opts,
+		Image:           imageReader,
+		Size:            -1,
+		Filename:        imagePart.FileName(),
+		Options:         opts,
+		DuplicatePolicy: duplicatePolicy,
+		CallbackURL:     callbackURL,
 	})
 	if err != nil {
 		h.log.Error("Failed to upload image", logFields("error", err)...)
+		if imagePart.trailing {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid multipart form",
+				Details: errFieldsAfterImage.Error(),
+			})
+			return
+		}
+		if imageFile.exceeded() {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   ErrFileTooLarge,
+				Details: fmt.Sprintf("Maximum file size is %dMB", MaxFileSize>>20),
+			})
+			return
+		}
+		if errors.Is(err, errs.ErrDuplicateImage) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   ErrDuplicateImage,
+				Details: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Failed to upload image",
 			Details: err.Error(),
@@ -132,20 +188,92 @@ func (h *Handler) UploadNewImage(c *ginext.Context) {
 
 	h.log.Info("Image uploaded successfully", logFields(
 		"image_id", result.ImageID,
-		"file_size", len(imageData),
+		"file_size", imageFile.read,
 	)...)
 
-	c.JSON(http.StatusAccepted, dto.SuccessResponse{
+	status := http.StatusAccepted
+	if result.Duplicate {
+		status = http.StatusOK
+	}
+	c.JSON(status, dto.SuccessResponse{
 		Message: result.Message,
 		Data: dto.UploadResponse{
 			ImageID:           result.ImageID,
 			ResultURL:         h.buildImageURL(result.ImageID),
 			ProcessingOptions: opts,
+			Duplicate:         result.Duplicate,
 			Message:           result.Message,
 		},
 	})
 }
 
+func (h *Handler) CreateContactSheet(c *ginext.Context) {
+	const op = "image.Handler.CreateContactSheet"
+	logFields := logger.WithFields("operation", op)
+
+	var req dto.ContactSheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Invalid contact sheet request", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid contact sheet request",
+			Details: err.Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.log.Error("Contact sheet validation failed", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid contact sheet request",
+			Details: err.Error(),
+		})
+		return
+	}
+	if req.Background != "" {
+		background, err := vo.NewValidColor(req.Background)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid contact sheet request",
+				Details: "background must be #RRGGBB or #RRGGBBAA",
+			})
+			return
+		}
+		req.Background = background.String()
+	}
+
+	h.log.Info("Creating contact sheet", logFields("images", len(req.ImageIDs))...)
+
+	result, err := h.uc.CreateContactSheet(c.Request.Context(), input.CreateContactSheetInput{
+		Spec: req.ToContactSheetSpec(),
+	})
+	if err != nil {
+		h.log.Error("Failed to create contact sheet", logFields("error", err)...)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrImageNotFound,
+				Details: err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to create contact sheet",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	h.log.Info("Contact sheet task created", logFields("image_id", result.ImageID)...)
+
+	c.JSON(http.StatusAccepted, dto.SuccessResponse{
+		Message: "Contact sheet is being composed",
+		Data: dto.ProcessingStatusResponse{
+			Status:   result.Status,
+			ImageID:  result.ImageID,
+			ImageURL: h.buildImageURL(result.ImageID),
+			Message:  "Contact sheet is being composed",
+		},
+	})
+}
+
 func (h *Handler) GetProcessedImage(c *ginext.Context) {
 	const op = "image.Handler.GetProcessedImage"
 	logFields := logger.WithFields("operation", op)
@@ -242,11 +370,221 @@ func (h *Handler) GetImageStatus(c *ginext.Context) {
 			Status:   result.Status,
 			ImageID:  imageID,
 			ImageURL: h.buildImageURL(imageID),
+			BlurHash: result.BlurHash,
+			LQIP:     result.LQIP,
 			Message:  fmt.Sprintf("Image status: %s", result.Status),
 		},
 	})
 }
 
+func (h *Handler) ListImages(c *ginext.Context) {
+	const op = "image.Handler.ListImages"
+	logFields := logger.WithFields("operation", op)
+
+	in, err := h.parseListParams(c)
+	if err != nil {
+		h.log.Error("Invalid list parameters", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid list parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Listing images", logFields("status", in.Status, "format", in.Format, "color", in.Color)...)
+
+	result, err := h.uc.List(c.Request.Context(), in)
+	if err != nil {
+		h.log.Error("Failed to list images", logFields("error", err)...)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to list images",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: result,
+	})
+}
+
+func (h *Handler) GetSimilarImages(c *ginext.Context) {
+	const op = "image.Handler.GetSimilarImages"
+	logFields := logger.WithFields("operation", op)
+
+	imageID := c.Param("id")
+	if imageID == "" {
+		h.log.Error("Image ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrImageIDRequired,
+		})
+		return
+	}
+
+	in := input.FindSimilarImagesInput{
+		ImageID:     imageID,
+		MaxDistance: DefaultSimilarMaxDistance,
+		Limit:       DefaultListLimit,
+	}
+	if maxDistanceStr := c.Query("max_distance"); maxDistanceStr != "" {
+		maxDistance, err := strconv.Atoi(maxDistanceStr)
+		if err != nil || maxDistance < 0 || maxDistance > MaxSimilarDistance {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid max_distance",
+				Details: fmt.Sprintf("max_distance must be between 0 and %d", MaxSimilarDistance),
+			})
+			return
+		}
+		in.MaxDistance = maxDistance
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > MaxListLimit {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid limit",
+				Details: fmt.Sprintf("limit must be between 1 and %d", MaxListLimit),
+			})
+			return
+		}
+		in.Limit = int32(limit)
+	}
+
+	h.log.Info("Getting similar images", logFields("image_id", imageID)...)
+
+	result, err := h.uc.FindSimilar(c.Request.Context(), in)
+	if err != nil {
+		h.log.Error("Failed to find similar images", logFields("error", err, "image_id", imageID)...)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrImageNotFound,
+				Details: fmt.Sprintf("Image with ID %s not found", imageID),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to find similar images",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: result,
+	})
+}
+
+func (h *Handler) CompareImages(c *ginext.Context) {
+	const op = "image.Handler.CompareImages"
+	logFields := logger.WithFields("operation", op)
+
+	imageID := c.Param("id")
+	if imageID == "" {
+		h.log.Error("Image ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrImageIDRequired,
+		})
+		return
+	}
+
+	in, err := h.parseCompareParams(c, imageID)
+	if err != nil {
+		h.log.Error("Invalid compare parameters", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid compare parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Comparing images", logFields("image_id", imageID, "other_image_id", in.OtherImageID)...)
+
+	result, err := h.uc.Compare(c.Request.Context(), in)
+	if err != nil {
+		h.log.Error("Failed to compare images", logFields("error", err, "image_id", imageID)...)
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrImageNotFound,
+				Details: err.Error(),
+			})
+		case strings.Contains(err.Error(), "not processed"):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Image is still being processed",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to compare images",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: result,
+	})
+}
+
+func (h *Handler) GetImageHistogram(c *ginext.Context) {
+	const op = "image.Handler.GetImageHistogram"
+	logFields := logger.WithFields("operation", op)
+
+	imageID := c.Param("id")
+	if imageID == "" {
+		h.log.Error("Image ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrImageIDRequired,
+		})
+		return
+	}
+
+	variant := vo.VariantOriginal
+	if rawVariant := c.Query("variant"); rawVariant != "" {
+		v, err := vo.NewValidVariant(strings.ToLower(rawVariant))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid variant",
+				Details: "Supported variants are original, processed",
+			})
+			return
+		}
+		variant = v
+	}
+
+	h.log.Info("Getting image histogram", logFields("image_id", imageID, "variant", variant.String())...)
+
+	result, err := h.uc.GetHistogram(c.Request.Context(), input.GetImageHistogramInput{
+		ImageID: imageID,
+		Variant: variant,
+	})
+	if err != nil {
+		h.log.Error("Failed to get image histogram", logFields("error", err, "image_id", imageID)...)
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrImageNotFound,
+				Details: fmt.Sprintf("Image with ID %s not found", imageID),
+			})
+		case strings.Contains(err.Error(), "not processed"):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Image is still being processed",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to get image histogram",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: result,
+	})
+}
+
 func (h *Handler) DeleteImage(c *ginext.Context) {
 	const op = "image.Handler.DeleteImage"
 	logFields := logger.WithFields("operation", op)
@@ -291,6 +629,55 @@ func (h *Handler) DeleteImage(c *ginext.Context) {
 	})
 }
 
+// CancelImage stops the processing of a pending or processing image.
+func (h *Handler) CancelImage(c *ginext.Context) {
+	const op = "image.Handler.CancelImage"
+	logFields := logger.WithFields("operation", op)
+
+	imageID := c.Param("id")
+	if imageID == "" {
+		h.log.Error("Image ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrImageIDRequired,
+		})
+		return
+	}
+
+	h.log.Info("Cancelling image processing", logFields("image_id", imageID)...)
+
+	result, err := h.uc.Cancel(c.Request.Context(), input.CancelImageInput{
+		ImageID: imageID,
+	})
+	if err != nil {
+		h.log.Error("Failed to cancel image processing", logFields("error", err, "image_id", imageID)...)
+		switch {
+		case errors.Is(err, errs.ErrNotCancellable):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Image processing cannot be cancelled",
+				Details: err.Error(),
+			})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrImageNotFound,
+				Details: fmt.Sprintf("Image with ID %s not found", imageID),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to cancel image processing",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	h.log.Info("Image processing cancelled", logFields("image_id", imageID)...)
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Image processing cancelled",
+		Data:    result,
+	})
+}
+
 func (h *Handler) ProcessImageSync(c *ginext.Context) {
 	const op = "image.Handler.ProcessImageSync"
 	logFields := logger.WithFields("operation", op)
@@ -349,44 +736,173 @@ func (h *Handler) ServeFrontend(c *ginext.Context) {
 
 func (h *Handler) RegisterRoutes(router *ginext.RouterGroup) {
 	router.POST("/upload", h.UploadNewImage)
+	router.POST("/upload/batch", h.UploadBatch)
+	router.POST("/upload/url", h.UploadFromURL)
+	router.POST("/uploads", h.CreateUploadSession)
+	router.POST("/uploads/presigned", h.CreatePresignedUpload)
+	router.POST("/uploads/:id/complete", h.CompletePresignedUpload)
+	router.GET("/uploads/:id", h.GetUploadSession)
+	router.HEAD("/uploads/:id", h.GetUploadSession)
+	router.PATCH("/uploads/:id", h.AppendUploadChunk)
+	router.DELETE("/uploads/:id", h.CancelUploadSession)
+	router.GET("/batches/:id", h.GetBatch)
+	router.GET("/images", h.ListImages)
+	router.POST("/images/contact-sheet", h.CreateContactSheet)
 	router.GET("/images/:id", h.GetProcessedImage)
 	router.GET("/images/:id/status", h.GetImageStatus)
+	router.GET("/images/:id/events", h.StreamImageEvents)
+	router.GET("/events/ws", h.StreamEvents)
+	router.GET("/images/:id/similar", h.GetSimilarImages)
+	router.GET("/images/:id/compare", h.CompareImages)
+	router.GET("/images/:id/histogram", h.GetImageHistogram)
+	router.GET("/images/:id/webhooks", h.ListWebhookDeliveries)
+	router.POST("/images/:id/cancel", h.CancelImage)
+	router.POST("/webhooks/:id/redeliver", h.RedeliverWebhook)
+	router.GET("/admin/dead-letters", h.ListDeadLetters)
+	router.POST("/admin/dead-letters/:id/replay", h.ReplayDeadLetter)
 	// router.POST("/images/:id/process", h.ProcessImageSync)
 	router.DELETE("/images/:id", h.DeleteImage)
+	router.POST("/templates", h.CreateCardTemplate)
+	router.GET("/templates", h.ListCardTemplates)
+	router.GET("/templates/:id", h.GetCardTemplate)
+	router.DELETE("/templates/:id", h.DeleteCardTemplate)
+	router.POST("/templates/:id/render", h.RenderCard)
 	router.GET("/health", h.HealthCheck)
 	router.GET("/", h.ServeFrontend)
 }
 
-func (h *Handler) validateFile(fileHeader *multipart.FileHeader) error {
-	if fileHeader.Size > MaxFileSize {
-		return fmt.Errorf("file size %d exceeds maximum %d", fileHeader.Size, MaxFileSize)
+// readImagePart reads the multipart form up to the "image" file and returns
+// the text fields sent before it. The file is streamed, so the fields cannot
+// follow it; the returned reader fails if they do.
+func (h *Handler) readImagePart(c *ginext.Context) (*imagePartReader, url.Values, error) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := url.Values{}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil, errors.New("no image part")
+			}
+			return nil, nil, err
+		}
+
+		if part.FileName() != "" {
+			if part.FormName() == "image" {
+				return &imagePartReader{Part: part, form: reader}, fields, nil
+			}
+			_ = part.Close()
+			continue
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, MaxFormValueSize+1))
+		_ = part.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(value) > MaxFormValueSize {
+			return nil, nil, fmt.Errorf("form field %q exceeds %d bytes", part.FormName(), MaxFormValueSize)
+		}
+		fields.Add(part.FormName(), string(value))
+	}
+}
+
+// multipartOptions reads the options from the fields sent before the file,
+// falling back to the query.
+func (h *Handler) multipartOptions(c *ginext.Context, fields url.Values) func(key string) string {
+	return func(key string) string {
+		if val := fields.Get(key); val != "" {
+			return val
+		}
+		return c.Query(key)
+	}
+}
+
+// imagePartReader reads the image part and fails at its end if more parts
+// follow, so that the image is never processed without the options sent
+// after it.
+type imagePartReader struct {
+	*multipart.Part
+	form     *multipart.Reader
+	trailing bool
+}
+
+func (r *imagePartReader) Read(p []byte) (int, error) {
+	n, err := r.Part.Read(p)
+	if !errors.Is(err, io.EOF) {
+		return n, err
+	}
+
+	next, nextErr := r.form.NextPart()
+	switch {
+	case nextErr == nil:
+		_ = next.Close()
+		r.trailing = true
+		return n, errFieldsAfterImage
+	case !errors.Is(nextErr, io.EOF):
+		return n, nextErr
+	default:
+		return n, err
+	}
+}
+
+// maxSizeReader fails the read once more than max bytes are read, so that a
+// file of unknown size is not stored past the limit.
+type maxSizeReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func (l *maxSizeReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.exceeded() {
+		return n, fmt.Errorf("file size exceeds maximum %d", l.max)
 	}
-	return nil
+	return n, err
 }
 
-func (h *Handler) isValidImage(filename string, data []byte) bool {
+func (l *maxSizeReader) exceeded() bool {
+	return l.read > l.max
+}
+
+func (h *Handler) isValidImage(filename string, header []byte) bool {
+	return h.hasImageExtension(filename) && h.hasImageSignature(header)
+}
+
+func (h *Handler) hasImageExtension(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
 	validExtensions := map[string]bool{
 		".jpg": true, ".jpeg": true, ".png": true, ".gif": true,
 	}
-	if !validExtensions[ext] {
-		return false
-	}
+	return validExtensions[ext]
+}
 
-	if len(data) < 8 {
-		return false
+func (h *Handler) hasImageSignature(header []byte) bool {
+	return h.signatureExtension(header) != ""
+}
+
+// signatureExtension returns the file extension matching the signature of
+// the file, or an empty string for an unsupported file.
+func (h *Handler) signatureExtension(header []byte) string {
+	if len(header) < SniffSize {
+		return ""
 	}
 
 	// Check file signatures
 	switch {
-	case data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF: // JPEG
-		return true
-	case data[0] == 0x89 && data[1] == 0x50 && data[2] == 0x4E && data[3] == 0x47: // PNG
-		return true
-	case string(data[:6]) == "GIF87a" || string(data[:6]) == "GIF89a": // GIF
-		return true
+	case header[0] == 0xFF && header[1] == 0xD8 && header[2] == 0xFF: // JPEG
+		return ".jpg"
+	case header[0] == 0x89 && header[1] == 0x50 && header[2] == 0x4E && header[3] == 0x47: // PNG
+		return ".png"
+	case string(header[:6]) == "GIF87a" || string(header[:6]) == "GIF89a": // GIF
+		return ".gif"
 	default:
-		return false
+		return ""
 	}
 }
 
@@ -408,15 +924,21 @@ func (h *Handler) buildImageURL(imageID string) string {
 }
 
 func (h *Handler) parseProcessingOptions(c *ginext.Context) (model.ProcessingOptions, error) {
-	var (
-		opts    = model.ProcessingOptions{}
-		readOpt = func(key string) string {
-			if val := c.PostForm(key); val != "" {
-				return val
-			}
-			return c.Query(key)
+	return h.parseOptions(h.formOptions(c))
+}
+
+// formOptions reads the options from the form, falling back to the query.
+func (h *Handler) formOptions(c *ginext.Context) func(key string) string {
+	return func(key string) string {
+		if val := c.PostForm(key); val != "" {
+			return val
 		}
-	)
+		return c.Query(key)
+	}
+}
+
+func (h *Handler) parseOptions(readOpt func(key string) string) (model.ProcessingOptions, error) {
+	opts := model.ProcessingOptions{}
 
 	// Validate and parse width
 	if widthStr := readOpt("width"); widthStr != "" {
@@ -468,5 +990,160 @@ func (h *Handler) parseProcessingOptions(c *ginext.Context) (model.ProcessingOpt
 		opts.Thumbnail = thumb
 	}
 
+	// Validate and parse corner radius
+	if radiusStr := readOpt("corner_radius"); radiusStr != "" {
+		// 0 means no rounding
+		radius, err := strconv.Atoi(radiusStr)
+		if err != nil || radius < 0 {
+			return opts, fmt.Errorf("invalid corner_radius: must be non-negative integer")
+		}
+		opts.CornerRadius = radius
+	}
+
+	// Validate and parse mask
+	if mask := readOpt("mask"); mask != "" {
+		mask = strings.ToLower(mask)
+		if mask != "circle" && mask != "ellipse" {
+			return opts, fmt.Errorf("invalid mask: supported masks are circle, ellipse")
+		}
+		opts.Mask = mask
+	}
+
+	// Validate and parse border width
+	if borderWidthStr := readOpt("border_width"); borderWidthStr != "" {
+		borderWidth, err := strconv.Atoi(borderWidthStr)
+		if err != nil || borderWidth <= 0 {
+			return opts, fmt.Errorf("invalid border_width: must be positive integer")
+		}
+		opts.BorderWidth = borderWidth
+	}
+
+	// Validate and parse border color
+	if borderColor := readOpt("border_color"); borderColor != "" {
+		c, err := vo.NewValidColor(borderColor)
+		if err != nil {
+			return opts, fmt.Errorf("invalid border_color: must be #RRGGBB or #RRGGBBAA")
+		}
+		opts.BorderColor = c.String()
+	}
+
+	// Validate and parse max bytes
+	if maxBytesStr := readOpt("max_bytes"); maxBytesStr != "" {
+		maxBytes, err := strconv.Atoi(maxBytesStr)
+		if err != nil || maxBytes <= 0 {
+			return opts, fmt.Errorf("invalid max_bytes: must be positive integer")
+		}
+		opts.MaxBytes = maxBytes
+	}
+
+	// Validate and parse auto level flag
+	if autoLevel := readOpt("auto_level"); autoLevel != "" {
+		level, err := strconv.ParseBool(autoLevel)
+		if err != nil {
+			return opts, fmt.Errorf("invalid auto_level value: must be true or false")
+		}
+		opts.AutoLevel = level
+	}
+
+	// Validate and parse auto white balance flag
+	if autoWB := readOpt("auto_white_balance"); autoWB != "" {
+		wb, err := strconv.ParseBool(autoWB)
+		if err != nil {
+			return opts, fmt.Errorf("invalid auto_white_balance value: must be true or false")
+		}
+		opts.AutoWhiteBalance = wb
+	}
+
 	return opts, nil
 }
+
+func (h *Handler) parseListParams(c *ginext.Context) (input.ListImagesInput, error) {
+	in := input.ListImagesInput{
+		Status: c.Query("status"),
+		Format: c.Query("format"),
+		Limit:  DefaultListLimit,
+	}
+
+	// Validate status
+	if in.Status != "" && vo.NewStatus(in.Status) == vo.StatusUnknown {
+		return in, fmt.Errorf("invalid status: %s", in.Status)
+	}
+
+	// Validate color
+	if color := strings.ToLower(c.Query("color")); color != "" {
+		if !vo.IsValidColorName(color) {
+			return in, fmt.Errorf("invalid color: supported colors are %s", strings.Join(vo.ColorNames, ", "))
+		}
+		in.Color = color
+	}
+
+	// Validate and parse limit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > MaxListLimit {
+			return in, fmt.Errorf("invalid limit: must be between 1 and %d", MaxListLimit)
+		}
+		in.Limit = int32(limit)
+	}
+
+	// Validate and parse offset
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return in, fmt.Errorf("invalid offset: must be non-negative integer")
+		}
+		in.Offset = int32(offset)
+	}
+
+	return in, nil
+}
+
+func (h *Handler) parseDuplicatePolicy(readOpt func(key string) string) (vo.DuplicatePolicy, error) {
+	policy := readOpt("duplicates")
+	if policy == "" {
+		return vo.DuplicatePolicyAllow, nil
+	}
+
+	return vo.NewValidDuplicatePolicy(strings.ToLower(policy))
+}
+
+// parseCompareParams defaults to comparing the processed image with its own original.
+func (h *Handler) parseCompareParams(c *ginext.Context, imageID string) (input.CompareImagesInput, error) {
+	in := input.CompareImagesInput{
+		ImageID:      imageID,
+		Variant:      vo.VariantProcessed,
+		OtherImageID: imageID,
+		OtherVariant: vo.VariantOriginal,
+	}
+
+	if otherImageID := c.Query("with"); otherImageID != "" && otherImageID != imageID {
+		in.OtherImageID = otherImageID
+		in.OtherVariant = vo.VariantProcessed
+	}
+
+	if variant := c.Query("variant"); variant != "" {
+		v, err := vo.NewValidVariant(strings.ToLower(variant))
+		if err != nil {
+			return in, fmt.Errorf("invalid variant: supported variants are original, processed")
+		}
+		in.Variant = v
+	}
+
+	if otherVariant := c.Query("with_variant"); otherVariant != "" {
+		v, err := vo.NewValidVariant(strings.ToLower(otherVariant))
+		if err != nil {
+			return in, fmt.Errorf("invalid with_variant: supported variants are original, processed")
+		}
+		in.OtherVariant = v
+	}
+
+	if diff := c.Query("diff"); diff != "" {
+		withDiff, err := strconv.ParseBool(diff)
+		if err != nil {
+			return in, fmt.Errorf("invalid diff value: must be true or false")
+		}
+		in.Diff = withDiff
+	}
+
+	return in, nil
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"testing"
+)
+
+func TestImagePartReaderFieldsAfterImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		trailing bool
+	}{
+		{"image last", false},
+		{"field after image", true},
+	}
+	for _, tt := range tests {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		_ = form.WriteField("width", "100")
+		file, _ := form.CreateFormFile("image", "cat.png")
+		_, _ = file.Write([]byte("image data"))
+		if tt.trailing {
+			_ = form.WriteField("height", "100")
+		}
+		_ = form.Close()
+
+		reader := multipart.NewReader(&body, form.Boundary())
+		var part *multipart.Part
+		for {
+			next, err := reader.NextPart()
+			if err != nil {
+				t.Fatalf("%s: no image part: %v", tt.name, err)
+			}
+			if next.FormName() == "image" {
+				part = next
+				break
+			}
+		}
+
+		imagePart := &imagePartReader{Part: part, form: reader}
+		data, err := io.ReadAll(imagePart)
+		switch {
+		case tt.trailing && !errors.Is(err, errFieldsAfterImage):
+			t.Errorf("%s: error = %v, want %v", tt.name, err, errFieldsAfterImage)
+		case !tt.trailing && err != nil:
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		case string(data) != "image data":
+			t.Errorf("%s: data = %q", tt.name, data)
+		}
+		if imagePart.trailing != tt.trailing {
+			t.Errorf("%s: trailing = %v, want %v", tt.name, imagePart.trailing, tt.trailing)
+		}
+	}
+}
+
+func TestMaxSizeReader(t *testing.T) {
+	reader := &maxSizeReader{r: bytes.NewReader(make([]byte, 11)), max: 10}
+	if _, err := io.ReadAll(reader); err == nil || !reader.exceeded() {
+		t.Errorf("error = %v, exceeded = %v, want the limit to be exceeded", err, reader.exceeded())
+	}
+
+	reader = &maxSizeReader{r: bytes.NewReader(make([]byte, 10)), max: 10}
+	if _, err := io.ReadAll(reader); err != nil || reader.exceeded() {
+		t.Errorf("error = %v, exceeded = %v, want a file of the maximum size to pass", err, reader.exceeded())
+	}
+}
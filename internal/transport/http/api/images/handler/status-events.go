@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	domainPorts "github.com/D1sordxr/image-processor/internal/domain/core/image/port"
+	"github.com/D1sordxr/image-processor/internal/transport/http/api/images/dto"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+
+	"github.com/wb-go/wbf/ginext"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	StatusEventName = "status"
+
+	MaxStatusRequestSize = 64 << 10 // 64KB
+	StatusWriteTimeout   = 10 * time.Second
+
+	StatusActionSubscribe   = "subscribe"
+	StatusActionUnsubscribe = "unsubscribe"
+
+	StatusMessageStatus = "status"
+	StatusMessageError  = "error"
+	StatusMessagePing   = "ping"
+)
+
+// StreamImageEvents streams the status changes of an image as Server-Sent
+// Events. The first event carries the current status.
+func (h *Handler) StreamImageEvents(c *ginext.Context) {
+	const op = "image.Handler.StreamImageEvents"
+	logFields := logger.WithFields("operation", op)
+
+	imageID := c.Param("id")
+	if imageID == "" {
+		h.log.Error("Image ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrImageIDRequired,
+		})
+		return
+	}
+
+	result, err := h.uc.SubscribeStatus(c.Request.Context(), input.SubscribeStatusInput{
+		ImageIDs: []string{imageID},
+	})
+	if err != nil {
+		h.log.Error("Failed to subscribe to image status", logFields("error", err, "image_id", imageID)...)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrImageNotFound,
+				Details: fmt.Sprintf("Image with ID %s not found", imageID),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to subscribe to image status",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+	defer result.Subscription.Close()
+
+	// the stream outlives the write timeout of the server
+	if err = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.log.Warn("Failed to clear write deadline", logFields("error", err)...)
+	}
+
+	h.log.Info("Streaming image events", logFields("image_id", imageID)...)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	for _, event := range result.Current {
+		c.SSEvent(StatusEventName, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(options.StatusEventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-h.streamsDone:
+			return
+		case event, ok := <-result.Subscription.Events():
+			if !ok {
+				return
+			}
+			c.SSEvent(StatusEventName, event)
+		case <-heartbeat.C:
+			// a comment keeps proxies from closing an idle stream
+			_, _ = io.WriteString(c.Writer, ": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// StreamEvents streams the status changes of several images over a
+// WebSocket. The client sends {"action": "subscribe", "image_ids": [...]} or
+// "unsubscribe" with the same fields; the current status of the subscribed
+// images is sent right away, then their transitions as they happen.
+func (h *Handler) StreamEvents(c *ginext.Context) {
+	server := websocket.Server{
+		Handshake: h.checkOrigin,
+		Handler: func(ws *websocket.Conn) {
+			h.serveStatusStream(c.Request.Context(), ws)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkOrigin keeps other sites from opening the stream in the name of the
+// user. Browsers always send the Origin; other clients may not, and are let
+// through.
+func (h *Handler) checkOrigin(_ *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	if originURL, err := url.Parse(origin); err == nil && strings.EqualFold(originURL.Host, req.Host) {
+		return nil
+	}
+	for _, allowed := range h.origins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return nil
+		}
+	}
+	return fmt.Errorf("origin %s is not allowed", origin)
+}
+
+func (h *Handler) serveStatusStream(ctx context.Context, ws *websocket.Conn) {
+	const op = "image.Handler.serveStatusStream"
+	logFields := logger.WithFields("operation", op)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the deadlines of the server do not apply to a long-lived connection
+	_ = ws.SetDeadline(time.Time{})
+	ws.MaxPayloadBytes = MaxStatusRequestSize
+
+	result, err := h.uc.SubscribeStatus(ctx, input.SubscribeStatusInput{})
+	if err != nil {
+		h.log.Error("Failed to subscribe to image status", logFields("error", err)...)
+		return
+	}
+	defer result.Subscription.Close()
+
+	h.log.Info("Status stream opened", logFields("remote_addr", ws.Request().RemoteAddr)...)
+
+	var sendMu sync.Mutex
+	send := func(msg dto.StatusStreamMessage) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		_ = ws.SetWriteDeadline(time.Now().Add(StatusWriteTimeout))
+		return websocket.JSON.Send(ws, msg)
+	}
+
+	go func() {
+		defer cancel()
+		h.readStatusRequests(ctx, ws, result.Subscription, send)
+	}()
+
+	heartbeat := time.NewTicker(options.StatusEventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.streamsDone:
+			return
+		case event, ok := <-result.Subscription.Events():
+			if !ok {
+				return
+			}
+			err = send(dto.StatusStreamMessage{Type: StatusMessageStatus, Event: &event})
+		case <-heartbeat.C:
+			err = send(dto.StatusStreamMessage{Type: StatusMessagePing})
+		}
+		if err != nil {
+			h.log.Info("Status stream closed", logFields("error", err)...)
+			return
+		}
+	}
+}
+
+// readStatusRequests handles the messages of the client until it
+// disconnects. Invalid messages are answered with an error message.
+func (h *Handler) readStatusRequests(
+	ctx context.Context,
+	ws *websocket.Conn,
+	subscription domainPorts.StatusSubscription,
+	send func(msg dto.StatusStreamMessage) error,
+) {
+	watched := make(map[string]struct{})
+	for {
+		var raw []byte
+		if err := websocket.Message.Receive(ws, &raw); err != nil {
+			return
+		}
+
+		var req dto.StatusStreamRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			_ = send(dto.StatusStreamMessage{
+				Type:    StatusMessageError,
+				Error:   "Invalid message",
+				Details: err.Error(),
+			})
+			continue
+		}
+
+		var sendErr error
+		switch req.Action {
+		case StatusActionSubscribe:
+			sendErr = h.watchStatus(ctx, subscription, watched, req.ImageIDs, send)
+		case StatusActionUnsubscribe:
+			if err := h.uc.UnwatchStatus(ctx, input.WatchStatusInput{
+				Subscription: subscription,
+				ImageIDs:     req.ImageIDs,
+			}); err != nil {
+				sendErr = send(dto.StatusStreamMessage{
+					Type:    StatusMessageError,
+					Error:   "Invalid image ID",
+					Details: err.Error(),
+				})
+				break
+			}
+			for _, imageID := range req.ImageIDs {
+				delete(watched, imageID)
+			}
+		default:
+			sendErr = send(dto.StatusStreamMessage{
+				Type:    StatusMessageError,
+				Error:   "Unknown action",
+				Details: fmt.Sprintf("Supported actions are %q and %q", StatusActionSubscribe, StatusActionUnsubscribe),
+			})
+		}
+		if sendErr != nil {
+			return
+		}
+	}
+}
+
+func (h *Handler) watchStatus(
+	ctx context.Context,
+	subscription domainPorts.StatusSubscription,
+	watched map[string]struct{},
+	imageIDs []string,
+	send func(msg dto.StatusStreamMessage) error,
+) error {
+	// images that are already watched are not watched again, so that a
+	// failed request does not unwatch them
+	added := make([]string, 0, len(imageIDs))
+	for _, imageID := range imageIDs {
+		if _, ok := watched[imageID]; !ok {
+			added = append(added, imageID)
+		}
+	}
+	if len(watched)+len(added) > options.MaxWatchedImages {
+		return send(dto.StatusStreamMessage{
+			Type:    StatusMessageError,
+			Error:   "Too many images",
+			Details: fmt.Sprintf("Maximum number of images per connection is %d", options.MaxWatchedImages),
+		})
+	}
+
+	result, err := h.uc.WatchStatus(ctx, input.WatchStatusInput{
+		Subscription: subscription,
+		ImageIDs:     added,
+	})
+	if err != nil {
+		msg := dto.StatusStreamMessage{Type: StatusMessageError, Error: "Failed to subscribe", Details: err.Error()}
+		if strings.Contains(err.Error(), "not found") {
+			msg.Error = ErrImageNotFound
+		}
+		return send(msg)
+	}
+
+	for _, imageID := range added {
+		watched[imageID] = struct{}{}
+	}
+	for _, event := range result.Current {
+		if err = send(dto.StatusStreamMessage{Type: StatusMessageStatus, Event: &event}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnShutdown ends the event streams when the server shuts down.
+func (h *Handler) OnShutdown() {
+	h.closeStreams.Do(func() { close(h.streamsDone) })
+}
@@ -0,0 +1,499 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/output"
+	"github.com/D1sordxr/image-processor/internal/transport/http/api/images/dto"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+const (
+	MaxChunkSize = 8 << 20 // 8MB
+
+	HeaderUploadLength   = "Upload-Length"
+	HeaderUploadOffset   = "Upload-Offset"
+	HeaderUploadMetadata = "Upload-Metadata"
+	HeaderUploadExpires  = "Upload-Expires"
+
+	ContentTypeOffsetStream = "application/offset+octet-stream"
+
+	ErrUploadIDRequired = "Upload ID is required"
+	ErrUploadNotFound   = "Upload not found"
+	ErrUploadExpired    = "Upload expired"
+)
+
+// CreateUploadSession starts a resumable upload. The length of the file is
+// taken from the Upload-Length header and its name from Upload-Metadata
+// ("filename <base64>"); processing options are passed as query parameters.
+func (h *Handler) CreateUploadSession(c *ginext.Context) {
+	const op = "image.Handler.CreateUploadSession"
+	logFields := logger.WithFields("operation", op)
+
+	size, err := strconv.ParseInt(c.GetHeader(HeaderUploadLength), 10, 64)
+	if err != nil || size <= 0 {
+		h.log.Error("Invalid upload length", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid upload length",
+			Details: fmt.Sprintf("%s header must be a positive integer", HeaderUploadLength),
+		})
+		return
+	}
+	if size > MaxFileSize {
+		h.log.Error("Upload too large", logFields("size", size)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrFileTooLarge,
+			Details: fmt.Sprintf("Maximum file size is %dMB", MaxFileSize>>20),
+		})
+		return
+	}
+
+	metadata, err := parseUploadMetadata(c.GetHeader(HeaderUploadMetadata))
+	if err != nil || metadata["filename"] == "" {
+		h.log.Error("Invalid upload metadata", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid upload metadata",
+			Details: fmt.Sprintf("%s header must contain base64 encoded filename", HeaderUploadMetadata),
+		})
+		return
+	}
+	filename := metadata["filename"]
+
+	if !h.hasImageExtension(filename) {
+		h.log.Error("Invalid image file", logFields("filename", filename)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrInvalidImage,
+			Details: "Please provide a valid JPEG, PNG, or GIF image",
+		})
+		return
+	}
+
+	opts, err := h.parseProcessingOptions(c)
+	if err != nil {
+		h.log.Error("Invalid processing options", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid processing options",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	duplicatePolicy, err := h.parseDuplicatePolicy(h.formOptions(c))
+	if err != nil {
+		h.log.Error("Invalid duplicate policy", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid duplicate policy",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Creating upload session", logFields("filename", filename, "size", size)...)
+
+	result, err := h.uc.CreateUploadSession(c.Request.Context(), input.CreateUploadSessionInput{
+		Filename:        filename,
+		Size:            size,
+		Options:         opts,
+		DuplicatePolicy: duplicatePolicy,
+	})
+	if err != nil {
+		h.log.Error("Failed to create upload session", logFields("error", err)...)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to create upload",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.setUploadHeaders(c, result)
+	c.Header("Location", h.buildUploadURL(result.UploadID))
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "Upload created",
+		Data:    result,
+	})
+}
+
+// GetUploadSession reports the progress of the upload. It also serves HEAD
+// requests, which get only the Upload-* headers.
+func (h *Handler) GetUploadSession(c *ginext.Context) {
+	const op = "image.Handler.GetUploadSession"
+	logFields := logger.WithFields("operation", op)
+
+	uploadID := c.Param("id")
+	if uploadID == "" {
+		h.log.Error("Upload ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrUploadIDRequired,
+		})
+		return
+	}
+
+	result, err := h.uc.GetUploadSession(c.Request.Context(), input.GetUploadSessionInput{
+		UploadID: uploadID,
+	})
+	if err != nil {
+		h.log.Error("Failed to get upload session", logFields("error", err, "upload_id", uploadID)...)
+		h.writeUploadError(c, uploadID, err)
+		return
+	}
+
+	h.setUploadHeaders(c, result)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: result,
+	})
+}
+
+// AppendUploadChunk writes the request body at the Upload-Offset of the
+// upload. The chunk that completes the upload gets the created image in
+// response, the others get 204 with the new offset.
+func (h *Handler) AppendUploadChunk(c *ginext.Context) {
+	const op = "image.Handler.AppendUploadChunk"
+	logFields := logger.WithFields("operation", op)
+
+	uploadID := c.Param("id")
+	if uploadID == "" {
+		h.log.Error("Upload ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrUploadIDRequired,
+		})
+		return
+	}
+
+	if c.ContentType() != ContentTypeOffsetStream {
+		c.JSON(http.StatusUnsupportedMediaType, dto.ErrorResponse{
+			Error:   "Invalid content type",
+			Details: fmt.Sprintf("Content-Type must be %s", ContentTypeOffsetStream),
+		})
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader(HeaderUploadOffset), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid upload offset",
+			Details: fmt.Sprintf("%s header must be a non-negative integer", HeaderUploadOffset),
+		})
+		return
+	}
+
+	size := c.Request.ContentLength
+	if size < 0 {
+		c.JSON(http.StatusLengthRequired, dto.ErrorResponse{
+			Error:   "Content-Length is required",
+			Details: "Chunks must be sent with a known length",
+		})
+		return
+	}
+	if size > MaxChunkSize {
+		c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+			Error:   "Chunk too large",
+			Details: fmt.Sprintf("Maximum chunk size is %dMB", MaxChunkSize>>20),
+		})
+		return
+	}
+
+	var chunk io.Reader = c.Request.Body
+	if offset == 0 && size > 0 {
+		// the first chunk has to start with the signature of the image
+		chunkReader := bufio.NewReaderSize(c.Request.Body, SniffSize)
+		header, _ := chunkReader.Peek(SniffSize)
+		if !h.hasImageSignature(header) {
+			h.log.Error("Invalid image file", logFields("upload_id", uploadID)...)
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   ErrInvalidImage,
+				Details: "Please provide a valid JPEG, PNG, or GIF image",
+			})
+			return
+		}
+		chunk = chunkReader
+	}
+
+	h.log.Info("Appending upload chunk", logFields("upload_id", uploadID, "offset", offset, "size", size)...)
+
+	result, err := h.uc.AppendUploadChunk(c.Request.Context(), input.AppendUploadChunkInput{
+		UploadID: uploadID,
+		Offset:   offset,
+		Chunk:    chunk,
+		Size:     size,
+	})
+	if err != nil {
+		h.log.Error("Failed to append upload chunk", logFields("error", err, "upload_id", uploadID)...)
+		h.writeUploadError(c, uploadID, err)
+		return
+	}
+
+	c.Header(HeaderUploadOffset, strconv.FormatInt(result.Offset, 10))
+	if result.Upload == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	h.log.Info("Upload completed", logFields("upload_id", uploadID, "image_id", result.Upload.ImageID)...)
+
+	status := http.StatusAccepted
+	if result.Upload.Duplicate {
+		status = http.StatusOK
+	}
+	c.JSON(status, dto.SuccessResponse{
+		Message: result.Upload.Message,
+		Data: dto.ProcessingStatusResponse{
+			Status:   result.Upload.Status,
+			ImageID:  result.Upload.ImageID,
+			ImageURL: h.buildImageURL(result.Upload.ImageID),
+			Message:  result.Upload.Message,
+		},
+	})
+}
+
+// CreatePresignedUpload reserves an image and returns a presigned URL the
+// original is PUT to. The filename, the exact size and processing options
+// are passed as form or query parameters.
+func (h *Handler) CreatePresignedUpload(c *ginext.Context) {
+	const op = "image.Handler.CreatePresignedUpload"
+	logFields := logger.WithFields("operation", op)
+
+	filename := c.PostForm("filename")
+	if filename == "" {
+		filename = c.Query("filename")
+	}
+	if !h.hasImageExtension(filename) {
+		h.log.Error("Invalid image file", logFields("filename", filename)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrInvalidImage,
+			Details: "Please provide a filename of a JPEG, PNG, or GIF image",
+		})
+		return
+	}
+
+	sizeStr := c.PostForm("size")
+	if sizeStr == "" {
+		sizeStr = c.Query("size")
+	}
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil || size <= 0 {
+		h.log.Error("Invalid upload size", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid upload size",
+			Details: "size must be a positive integer",
+		})
+		return
+	}
+	if size > MaxFileSize {
+		h.log.Error("Upload too large", logFields("size", size)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrFileTooLarge,
+			Details: fmt.Sprintf("Maximum file size is %dMB", MaxFileSize>>20),
+		})
+		return
+	}
+
+	opts, err := h.parseProcessingOptions(c)
+	if err != nil {
+		h.log.Error("Invalid processing options", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid processing options",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	duplicatePolicy, err := h.parseDuplicatePolicy(h.formOptions(c))
+	if err != nil {
+		h.log.Error("Invalid duplicate policy", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid duplicate policy",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Creating presigned upload", logFields("filename", filename, "size", size)...)
+
+	result, err := h.uc.CreatePresignedUpload(c.Request.Context(), input.CreatePresignedUploadInput{
+		Filename:        filename,
+		Size:            size,
+		Options:         opts,
+		DuplicatePolicy: duplicatePolicy,
+	})
+	if err != nil {
+		h.log.Error("Failed to create presigned upload", logFields("error", err)...)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to create upload",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Location", h.buildUploadURL(result.UploadID))
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "Upload the file with PUT to upload_url, then complete the upload",
+		Data:    result,
+	})
+}
+
+// CompletePresignedUpload is called by the client once the PUT to the
+// presigned URL has succeeded.
+func (h *Handler) CompletePresignedUpload(c *ginext.Context) {
+	const op = "image.Handler.CompletePresignedUpload"
+	logFields := logger.WithFields("operation", op)
+
+	uploadID := c.Param("id")
+	if uploadID == "" {
+		h.log.Error("Upload ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrUploadIDRequired,
+		})
+		return
+	}
+
+	h.log.Info("Completing presigned upload", logFields("upload_id", uploadID)...)
+
+	result, err := h.uc.CompletePresignedUpload(c.Request.Context(), input.CompletePresignedUploadInput{
+		UploadID: uploadID,
+	})
+	if err != nil {
+		h.log.Error("Failed to complete presigned upload", logFields("error", err, "upload_id", uploadID)...)
+		h.writeUploadError(c, uploadID, err)
+		return
+	}
+
+	status := http.StatusAccepted
+	if result.Duplicate {
+		status = http.StatusOK
+	}
+	c.JSON(status, dto.SuccessResponse{
+		Message: result.Message,
+		Data: dto.ProcessingStatusResponse{
+			Status:   result.Status,
+			ImageID:  result.ImageID,
+			ImageURL: h.buildImageURL(result.ImageID),
+			Message:  result.Message,
+		},
+	})
+}
+
+func (h *Handler) CancelUploadSession(c *ginext.Context) {
+	const op = "image.Handler.CancelUploadSession"
+	logFields := logger.WithFields("operation", op)
+
+	uploadID := c.Param("id")
+	if uploadID == "" {
+		h.log.Error("Upload ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrUploadIDRequired,
+		})
+		return
+	}
+
+	h.log.Info("Cancelling upload", logFields("upload_id", uploadID)...)
+
+	result, err := h.uc.CancelUploadSession(c.Request.Context(), input.CancelUploadSessionInput{
+		UploadID: uploadID,
+	})
+	if err != nil {
+		h.log.Error("Failed to cancel upload", logFields("error", err, "upload_id", uploadID)...)
+		h.writeUploadError(c, uploadID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: result.Message,
+		Data: map[string]string{
+			"upload_id": uploadID,
+		},
+	})
+}
+
+func (h *Handler) writeUploadError(c *ginext.Context, uploadID string, err error) {
+	switch {
+	case errors.Is(err, errs.ErrOffsetMismatch):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Upload offset mismatch",
+			Details: err.Error(),
+		})
+	case errors.Is(err, errs.ErrChunkExceedsLength):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Chunk exceeds upload length",
+			Details: err.Error(),
+		})
+	case errors.Is(err, errs.ErrWrongUploadMethod), errors.Is(err, errs.ErrObjectNotUploaded):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Upload cannot be processed",
+			Details: err.Error(),
+		})
+	case errors.Is(err, errs.ErrUploadSizeMismatch):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Upload size mismatch",
+			Details: err.Error(),
+		})
+	case errors.Is(err, errs.ErrInvalidImage):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrInvalidImage,
+			Details: "Please provide a valid JPEG, PNG, or GIF image",
+		})
+	case errors.Is(err, errs.ErrUploadExpired):
+		c.JSON(http.StatusGone, dto.ErrorResponse{
+			Error:   ErrUploadExpired,
+			Details: fmt.Sprintf("Upload with ID %s has expired", uploadID),
+		})
+	case errors.Is(err, errs.ErrDuplicateImage):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   ErrDuplicateImage,
+			Details: err.Error(),
+		})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   ErrUploadNotFound,
+			Details: fmt.Sprintf("Upload with ID %s not found", uploadID),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to process upload",
+			Details: err.Error(),
+		})
+	}
+}
+
+func (h *Handler) setUploadHeaders(c *ginext.Context, upload *output.UploadSessionOutput) {
+	c.Header(HeaderUploadOffset, strconv.FormatInt(upload.Offset, 10))
+	c.Header(HeaderUploadLength, strconv.FormatInt(upload.Length, 10))
+	if upload.ImageID == "" {
+		c.Header(HeaderUploadExpires, upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+func (h *Handler) buildUploadURL(uploadID string) string {
+	return fmt.Sprintf("%s/api/uploads/%s", h.baseURL, uploadID)
+}
+
+// parseUploadMetadata parses the "key base64value,key base64value" list of
+// the Upload-Metadata header.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s: %w", key, err)
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/errs"
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/transport/http/api/images/dto"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+const ErrURLRequired = "No source URL provided"
+
+// UploadFromURL downloads the image at the "url" form or query parameter and
+// uploads it the same way as UploadNewImage. A file without an image
+// extension in its name gets the one of its signature.
+func (h *Handler) UploadFromURL(c *ginext.Context) {
+	const op = "image.Handler.UploadFromURL"
+	logFields := logger.WithFields("operation", op)
+
+	rawURL := h.formOptions(c)("url")
+	if rawURL == "" {
+		h.log.Error("No source URL provided", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrURLRequired,
+			Details: "Please provide the URL of an image using 'url' parameter",
+		})
+		return
+	}
+	if sourceURL, err := url.Parse(rawURL); err != nil ||
+		(sourceURL.Scheme != "http" && sourceURL.Scheme != "https") || sourceURL.Host == "" {
+		h.log.Error("Invalid source URL", logFields("url", rawURL)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid source URL",
+			Details: "Only absolute http and https URLs are supported",
+		})
+		return
+	}
+
+	opts, err := h.parseProcessingOptions(c)
+	if err != nil {
+		h.log.Error("Invalid processing options", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid processing options",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	duplicatePolicy, err := h.parseDuplicatePolicy(h.formOptions(c))
+	if err != nil {
+		h.log.Error("Invalid duplicate policy", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid duplicate policy",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	callbackURL, err := h.parseCallbackURL(h.formOptions(c))
+	if err != nil {
+		h.log.Error("Invalid callback URL", logFields("error", err)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid callback URL",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Fetching image", logFields("url", rawURL)...)
+
+	remote, err := h.fetcher.Fetch(c.Request.Context(), rawURL, MaxFileSize)
+	if err != nil {
+		h.log.Error("Failed to fetch image", logFields("error", err, "url", rawURL)...)
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error:   "Failed to fetch image",
+			Details: err.Error(),
+		})
+		return
+	}
+	defer func() { _ = remote.Body.Close() }()
+
+	if remote.Size > MaxFileSize {
+		h.log.Error("File validation failed", logFields("size", remote.Size)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrFileTooLarge,
+			Details: fmt.Sprintf("Maximum file size is %dMB", MaxFileSize>>20),
+		})
+		return
+	}
+
+	// the file is streamed to the storage, only its header is read here
+	imageReader := bufio.NewReaderSize(remote.Body, SniffSize)
+	header, _ := imageReader.Peek(SniffSize)
+
+	extension := h.signatureExtension(header)
+	if extension == "" {
+		h.log.Error("Invalid image file", logFields("url", rawURL, "content_type", remote.ContentType)...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   ErrInvalidImage,
+			Details: "Please provide a URL of a valid JPEG, PNG, or GIF image",
+		})
+		return
+	}
+	filename := remote.Filename
+	if !h.hasImageExtension(filename) {
+		filename = strings.TrimSuffix(filename, ".") + extension
+	}
+
+	result, err := h.uc.Upload(c.Request.Context(), input.UploadImageInput{
+		Image:           imageReader,
+		Size:            remote.Size,
+		Filename:        filename,
+		Options:         opts,
+		DuplicatePolicy: duplicatePolicy,
+		CallbackURL:     callbackURL,
+	})
+	if err != nil {
+		h.log.Error("Failed to upload image", logFields("error", err)...)
+		if errors.Is(err, errs.ErrDuplicateImage) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   ErrDuplicateImage,
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to upload image",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Image imported successfully", logFields(
+		"image_id", result.ImageID,
+		"url", rawURL,
+	)...)
+
+	status := http.StatusAccepted
+	if result.Duplicate {
+		status = http.StatusOK
+	}
+	c.JSON(status, dto.SuccessResponse{
+		Message: result.Message,
+		Data: dto.UploadResponse{
+			ImageID:           result.ImageID,
+			ResultURL:         h.buildImageURL(result.ImageID),
+			ProcessingOptions: opts,
+			Duplicate:         result.Duplicate,
+			Message:           result.Message,
+		},
+	})
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/transport/http/api/images/dto"
+	"github.com/D1sordxr/image-processor/pkg/logger"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+const (
+	MaxCallbackURLLength = 2048
+
+	ErrDeliveryIDRequired = "Delivery ID is required"
+	ErrDeliveryNotFound   = "Webhook delivery not found"
+)
+
+var errInvalidCallbackURL = errors.New("callback_url must be an absolute http or https URL")
+
+// ListWebhookDeliveries returns the webhook deliveries of an image with the
+// log of their attempts.
+func (h *Handler) ListWebhookDeliveries(c *ginext.Context) {
+	const op = "image.Handler.ListWebhookDeliveries"
+	logFields := logger.WithFields("operation", op)
+
+	imageID := c.Param("id")
+	if imageID == "" {
+		h.log.Error("Image ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrImageIDRequired,
+		})
+		return
+	}
+
+	h.log.Info("Listing webhook deliveries", logFields("image_id", imageID)...)
+
+	result, err := h.uc.ListWebhookDeliveries(c.Request.Context(), input.ListWebhookDeliveriesInput{
+		ImageID: imageID,
+	})
+	if err != nil {
+		h.log.Error("Failed to list webhook deliveries", logFields("error", err, "image_id", imageID)...)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrImageNotFound,
+				Details: fmt.Sprintf("Image with ID %s not found", imageID),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to list webhook deliveries",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: result,
+	})
+}
+
+// RedeliverWebhook sends the payload of a delivery again, e.g. after the
+// callback was down for longer than the retries last.
+func (h *Handler) RedeliverWebhook(c *ginext.Context) {
+	const op = "image.Handler.RedeliverWebhook"
+	logFields := logger.WithFields("operation", op)
+
+	deliveryID := c.Param("id")
+	if deliveryID == "" {
+		h.log.Error("Delivery ID is required", logFields()...)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: ErrDeliveryIDRequired,
+		})
+		return
+	}
+
+	h.log.Info("Redelivering webhook", logFields("delivery_id", deliveryID)...)
+
+	result, err := h.uc.RedeliverWebhook(c.Request.Context(), input.RedeliverWebhookInput{
+		DeliveryID: deliveryID,
+	})
+	if err != nil {
+		h.log.Error("Failed to redeliver webhook", logFields("error", err, "delivery_id", deliveryID)...)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   ErrDeliveryNotFound,
+				Details: fmt.Sprintf("Webhook delivery with ID %s not found", deliveryID),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to redeliver webhook",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.SuccessResponse{
+		Message: "Webhook redelivery scheduled",
+		Data:    result,
+	})
+}
+
+// parseCallbackURL reads the optional "callback_url" form or query parameter.
+// Whether the host may be reached is checked when the webhook is sent.
+func (h *Handler) parseCallbackURL(readOpt func(key string) string) (string, error) {
+	rawURL := readOpt("callback_url")
+	if rawURL == "" {
+		return "", nil
+	}
+	if len(rawURL) > MaxCallbackURLLength {
+		return "", fmt.Errorf("callback_url is longer than %d characters", MaxCallbackURLLength)
+	}
+
+	callbackURL, err := url.Parse(rawURL)
+	if err != nil || (callbackURL.Scheme != "http" && callbackURL.Scheme != "https") || callbackURL.Host == "" {
+		return "", errInvalidCallbackURL
+	}
+
+	return callbackURL.String(), nil
+}
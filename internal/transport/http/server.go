@@ -23,6 +23,12 @@ type routeRegisterer interface {
 	RegisterRoutes(router *ginext.RouterGroup)
 }
 
+// shutdownNotifiee is a handler with long-lived requests, e.g. streams, that
+// have to end for the server to shut down.
+type shutdownNotifiee interface {
+	OnShutdown()
+}
+
 type Server struct {
 	log      port.Logger
 	cfg      *config.HTTPServer
@@ -48,30 +54,32 @@ func NewServer(
 	engine.Use(middleware.Recovery())
 
 	if cfg.CORS {
-		allowedOrigins := cfg.AllowOrigins
-		if len(allowedOrigins) == 0 {
-			allowedOrigins = []string{"*"}
-		}
-
 		engine.Use(middleware.CORS(middleware.CORSConfig{
-			AllowOrigins:     allowedOrigins,
+			AllowOrigins:     cfg.Origins(),
 			AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-			AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-			ExposeHeaders:    []string{"Content-Length"},
+			AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Upload-Length", "Upload-Offset", "Upload-Metadata"},
+			ExposeHeaders:    []string{"Content-Length", "Location", "Upload-Length", "Upload-Offset", "Upload-Expires"},
 			AllowCredentials: true,
 			MaxAge:           12 * time.Hour,
 		}))
 	}
 
+	server := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           engine.Handler(),
+		ReadHeaderTimeout: cfg.Timeout,
+		ReadTimeout:       cfg.Timeout,
+		WriteTimeout:      cfg.Timeout,
+	}
+	for _, handler := range handlers {
+		if h, ok := handler.(shutdownNotifiee); ok {
+			server.RegisterOnShutdown(h.OnShutdown)
+		}
+	}
+
 	return &Server{
-		log: log,
-		server: &http.Server{
-			Addr:              ":" + cfg.Port,
-			Handler:           engine.Handler(),
-			ReadHeaderTimeout: cfg.Timeout,
-			ReadTimeout:       cfg.Timeout,
-			WriteTimeout:      cfg.Timeout,
-		},
+		log:      log,
+		server:   server,
 		engine:   engine,
 		handlers: handlers,
 	}
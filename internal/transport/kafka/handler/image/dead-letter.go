@@ -0,0 +1,39 @@
+package image
+
+import (
+	"context"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/port"
+	appPorts "github.com/D1sordxr/image-processor/internal/domain/app/port"
+	domainPort "github.com/D1sordxr/image-processor/internal/domain/core/image/port"
+)
+
+type DeadLetterHandler struct {
+	log      appPorts.Logger
+	consumer domainPort.DeadLetterConsumer
+	uc       port.UseCase
+}
+
+func NewDeadLetterHandler(
+	log appPorts.Logger,
+	consumer domainPort.DeadLetterConsumer,
+	uc port.UseCase,
+) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		log:      log,
+		consumer: consumer,
+		uc:       uc,
+	}
+}
+
+func (h *DeadLetterHandler) Start(ctx context.Context) error {
+	const op = "image.DeadLetterHandler.Start"
+
+	h.log.Info("Starting dead-letter handler", "operation", op)
+
+	return h.consumer.StartConsuming(ctx, h.uc.SaveDeadLetter)
+}
+
+func (h *DeadLetterHandler) Stop(_ context.Context) error {
+	return nil
+}
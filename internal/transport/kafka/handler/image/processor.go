@@ -34,6 +34,10 @@ func (h *ProcessorHandler) Start(ctx context.Context) error {
 	return h.consumer.StartProcessing(ctx, h.uc.Process)
 }
 
-func (h *ProcessorHandler) Stop(_ context.Context) error {
-	return nil
+func (h *ProcessorHandler) Stop(ctx context.Context) error {
+	const op = "image.ProcessorHandler.Stop"
+
+	h.log.Info("Stopping image processor handler", "operation", op)
+
+	return h.consumer.Stop(ctx)
 }
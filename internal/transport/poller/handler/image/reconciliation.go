@@ -0,0 +1,107 @@
+package image
+
+import (
+	"context"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/input"
+	"github.com/D1sordxr/image-processor/internal/application/image/port"
+	appPorts "github.com/D1sordxr/image-processor/internal/domain/app/port"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+	"github.com/D1sordxr/image-processor/internal/infrastructure/config"
+)
+
+// ReconciliationHandler periodically settles the images stuck in processing
+// and looks for originals that belong to no image. The stuck images are
+// claimed, so every instance may run it.
+type ReconciliationHandler struct {
+	log appPorts.Logger
+	uc  port.UseCase
+	cfg config.Reconciler
+}
+
+func NewReconciliationHandler(
+	log appPorts.Logger,
+	uc port.UseCase,
+	cfg config.Reconciler,
+) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		log: log,
+		uc:  uc,
+		cfg: cfg,
+	}
+}
+
+func (h *ReconciliationHandler) Start(ctx context.Context) error {
+	const op = "image.ReconciliationHandler.Start"
+
+	h.log.Info("Starting reconciliation handler", "operation", op)
+
+	stuckTicker := time.NewTicker(h.cfg.Interval)
+	defer stuckTicker.Stop()
+	orphanTicker := time.NewTicker(h.cfg.OrphanInterval)
+	defer orphanTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-stuckTicker.C:
+			h.reconcileStuckImages(ctx)
+		case <-orphanTicker.C:
+			h.reconcileOrphanOriginals(ctx)
+		}
+	}
+}
+
+func (h *ReconciliationHandler) reconcileStuckImages(ctx context.Context) {
+	const op = "image.ReconciliationHandler.reconcileStuckImages"
+
+	// a full batch means more images may be stuck
+	for {
+		result, err := h.uc.ReconcileStuckImages(ctx, input.ReconcileStuckImagesInput{
+			Timeout:     h.cfg.StuckTimeout,
+			MaxRequeues: h.cfg.MaxRequeues,
+		})
+		if err != nil {
+			h.log.Error("Failed to reconcile stuck images", "operation", op, "error", err.Error())
+			return
+		}
+		if result.Claimed > 0 {
+			h.log.Info("Reconciled stuck images", "operation", op,
+				"claimed", result.Claimed,
+				"completed", result.Completed,
+				"requeued", result.Requeued,
+				"failed", result.Failed,
+				"skipped", result.Skipped,
+				"errors", result.Errors,
+			)
+		}
+		if result.Claimed < options.ReconcileBatchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (h *ReconciliationHandler) reconcileOrphanOriginals(ctx context.Context) {
+	const op = "image.ReconciliationHandler.reconcileOrphanOriginals"
+
+	result, err := h.uc.ReconcileOrphanOriginals(ctx, input.ReconcileOrphanOriginalsInput{
+		MinAge: h.cfg.OrphanAge,
+		Delete: h.cfg.DeleteOrphans,
+	})
+	if err != nil {
+		h.log.Error("Failed to reconcile orphaned originals", "operation", op, "error", err.Error())
+		return
+	}
+	if len(result.Orphans) > 0 {
+		h.log.Warn("Found originals with no image", "operation", op,
+			"orphans", len(result.Orphans),
+			"deleted", result.Deleted,
+		)
+	}
+}
+
+func (h *ReconciliationHandler) Stop(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,61 @@
+package image
+
+import (
+	"context"
+	"time"
+
+	"github.com/D1sordxr/image-processor/internal/application/image/port"
+	appPorts "github.com/D1sordxr/image-processor/internal/domain/app/port"
+	"github.com/D1sordxr/image-processor/internal/domain/core/image/options"
+)
+
+// WebhookDeliveryHandler polls the due webhook deliveries. Every instance
+// runs its own poller, the deliveries are leased so that each one is sent
+// by a single instance.
+type WebhookDeliveryHandler struct {
+	log appPorts.Logger
+	uc  port.UseCase
+}
+
+func NewWebhookDeliveryHandler(
+	log appPorts.Logger,
+	uc port.UseCase,
+) *WebhookDeliveryHandler {
+	return &WebhookDeliveryHandler{
+		log: log,
+		uc:  uc,
+	}
+}
+
+func (h *WebhookDeliveryHandler) Start(ctx context.Context) error {
+	const op = "image.WebhookDeliveryHandler.Start"
+
+	h.log.Info("Starting webhook delivery handler", "operation", op)
+
+	ticker := time.NewTicker(options.WebhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// a full batch means more deliveries may be due
+		for {
+			sent, err := h.uc.DeliverWebhooks(ctx)
+			if err != nil {
+				h.log.Error("Failed to deliver webhooks", "operation", op, "error", err.Error())
+				break
+			}
+			if sent < options.WebhookBatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+func (h *WebhookDeliveryHandler) Stop(_ context.Context) error {
+	return nil
+}
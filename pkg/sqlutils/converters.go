@@ -19,6 +19,13 @@ func ToNullableTime(t *time.Time) sql.NullTime {
 	return sql.NullTime{Time: *t, Valid: true}
 }
 
+func ToNullableInt64(i *int64) sql.NullInt64 {
+	if i == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *i, Valid: true}
+}
+
 func ToNullableInt32(i *int32) sql.NullInt32 {
 	if i == nil {
 		return sql.NullInt32{}
@@ -16,6 +16,10 @@ class ImageProcessor {
 
     async uploadImage() {
         const formData = new FormData(document.getElementById('uploadForm'));
+        // сервер читает форму потоково, поэтому файл передаётся последним
+        const imageFile = formData.get('image');
+        formData.delete('image');
+        formData.append('image', imageFile);
         const uploadBtn = document.getElementById('uploadBtn');
 
         try {
@@ -91,8 +95,32 @@ class ImageProcessor {
             container.appendChild(imageElement);
         }
 
-        // Запускаем проверку статуса
-        this.pollImageStatus(imageData.image_id);
+        // Подписываемся на изменения статуса
+        this.watchImageStatus(imageData.image_id);
+    }
+
+    watchImageStatus(imageId) {
+        if (!window.EventSource) {
+            this.pollImageStatus(imageId);
+            return;
+        }
+
+        const events = new EventSource(`${this.baseUrl}/api/images/${imageId}/events`);
+        events.addEventListener('status', (e) => {
+            const event = JSON.parse(e.data);
+            const imageUrl = event.status === 'completed' ? `${this.baseUrl}/image/${imageId}` : null;
+            this.updateImageStatus(imageId, event.status, imageUrl);
+
+            if (event.status === 'completed' || event.status === 'failed' || event.status === 'cancelled') {
+                events.close();
+            }
+        });
+        events.onerror = () => {
+            // Поток недоступен (например, изображение удалено) — возвращаемся к опросу
+            if (events.readyState === EventSource.CLOSED) {
+                this.pollImageStatus(imageId);
+            }
+        };
     }
 
     async pollImageStatus(imageId) {
@@ -136,6 +164,7 @@ class ImageProcessor {
                 'processing': '⏳ Обрабатывается...',
                 'completed': '✅ Готово',
                 'failed': '❌ Ошибка',
+                'cancelled': '🚫 Отменено',
                 'uploaded': '📤 Загружено'
             };
 